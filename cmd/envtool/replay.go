@@ -0,0 +1,591 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/FerretDB/wire/wireclient"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/diffreport"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+)
+
+// ReplayParams represents `envtool replay` parameters.
+//
+//nolint:vet // for readability
+type ReplayParams struct {
+	Dir        string        `arg:""                               help:"Directory with records created by '--dev-records-dir'."                        type:"existingdir"`
+	URI        string        `default:"mongodb://127.0.0.1:27017/" help:"Target URI."`
+	CompareURI string        `default:""                           help:"Second target URI; responses from both targets are compared."`
+	Speed      float64       `default:"1"                          help:"Speed relative to the original timing (0 sends requests as fast as possible)."`
+	Timeout    time.Duration `default:"30s"                        help:"Timeout for a single request."`
+}
+
+// replaySkippedCommands contains commands that are not replayed.
+//
+// Recorded authentication exchanges can't succeed again;
+// credentials from the target URI are used instead.
+var replaySkippedCommands = []string{
+	"authenticate",
+	"logout",
+	"saslContinue",
+	"saslStart",
+}
+
+// replayIgnoredPaths contains response fields that are expected to be different between targets.
+var replayIgnoredPaths = []string{
+	"cursor.id",
+	"electionId",
+	"topologyVersion.processId",
+}
+
+// replayCursors maps cursor IDs from the recording to cursor IDs of a single target connection.
+//
+// Recordings contain only requests, so a recorded cursor ID is mapped to the oldest unmapped
+// target cursor of the same namespace when it is used by `getMore` or `killCursors` for the first time.
+type replayCursors struct {
+	ids     map[int64]int64    // recorded -> target
+	created map[string][]int64 // namespace -> unmapped target cursor IDs
+	known   map[int64]struct{} // all target cursor IDs
+}
+
+// newReplayCursors creates a new replayCursors.
+func newReplayCursors() *replayCursors {
+	return &replayCursors{
+		ids:     map[int64]int64{},
+		created: map[string][]int64{},
+		known:   map[int64]struct{}{},
+	}
+}
+
+// track remembers the target cursor returned in the response, if any.
+func (rc *replayCursors) track(res *wirebson.Document) {
+	cursor, _ := res.Get("cursor").(*wirebson.Document)
+	if cursor == nil {
+		return
+	}
+
+	id, _ := cursor.Get("id").(int64)
+	ns, _ := cursor.Get("ns").(string)
+
+	if id == 0 || ns == "" {
+		return
+	}
+
+	if _, ok := rc.known[id]; ok {
+		return
+	}
+
+	rc.known[id] = struct{}{}
+	rc.created[ns] = append(rc.created[ns], id)
+}
+
+// target returns the target cursor ID for the recorded one.
+// If there is no target cursor to map, the recorded ID is returned as is.
+func (rc *replayCursors) target(ns string, recorded int64) int64 {
+	if id, ok := rc.ids[recorded]; ok {
+		return id
+	}
+
+	created := rc.created[ns]
+	if len(created) == 0 {
+		return recorded
+	}
+
+	id := created[0]
+	rc.created[ns] = created[1:]
+	rc.ids[recorded] = id
+
+	return id
+}
+
+// rewrite returns the message with recorded cursor IDs of `getMore` and `killCursors` commands
+// replaced by target cursor IDs.
+// Other messages are returned as is.
+func (rc *replayCursors) rewrite(body wire.MsgBody) (wire.MsgBody, error) {
+	msg, ok := body.(*wire.OpMsg)
+	if !ok {
+		return body, nil
+	}
+
+	doc, err := msg.DocumentDeep()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	db, _ := doc.Get("$db").(string)
+
+	switch command := doc.Command(); command {
+	case "getMore":
+		id, _ := doc.Get(command).(int64)
+		collection, _ := doc.Get("collection").(string)
+
+		if err = doc.Replace(command, rc.target(db+"."+collection, id)); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+	case "killCursors":
+		collection, _ := doc.Get(command).(string)
+
+		ids, _ := doc.Get("cursors").(*wirebson.Array)
+		if ids == nil {
+			return body, nil
+		}
+
+		targets := wirebson.MakeArray(ids.Len())
+
+		for v := range ids.Values() {
+			if id, ok := v.(int64); ok {
+				v = rc.target(db+"."+collection, id)
+			}
+
+			if err = targets.Add(v); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+
+		if err = doc.Replace("cursors", targets); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+	default:
+		return body, nil
+	}
+
+	res, err := wire.NewOpMsg(doc)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// replaySession represents recorded messages of a single client connection.
+type replaySession struct {
+	file     string
+	messages []wire.Record
+	times    []time.Time // nil if timing file is not present
+}
+
+// replayStats represents replay results.
+//
+//nolint:vet // for readability
+type replayStats struct {
+	m sync.Mutex
+
+	requests   int
+	skipped    int
+	errors     int // network and protocol errors
+	failed     int // responses with ok: 0
+	mismatches int
+	latencies  []time.Duration
+}
+
+// replayLoadSessions loads all records from the given directory.
+func replayLoadSessions(dir string) ([]*replaySession, error) {
+	var res []*replaySession
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if filepath.Ext(entry.Name()) != ".bin" {
+			return nil
+		}
+
+		s, err := replayLoadSession(path)
+		if err != nil {
+			return lazyerrors.Errorf("%s: %w", path, err)
+		}
+
+		res = append(res, s)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// replayLoadSession loads a single .bin file and the corresponding .timing file, if present.
+func replayLoadSession(path string) (*replaySession, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer f.Close() //nolint:errcheck // we are only reading it
+
+	s := &replaySession{
+		file: path,
+	}
+
+	r := bufio.NewReader(f)
+
+	for {
+		header, body, err := wire.ReadMessage(r)
+		if errors.Is(err, wire.ErrZeroRead) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		s.messages = append(s.messages, wire.Record{Header: header, Body: body})
+	}
+
+	b, err := os.ReadFile(strings.TrimSuffix(path, ".bin") + ".timing")
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	for _, line := range strings.Fields(string(b)) {
+		var ns int64
+		if ns, err = strconv.ParseInt(line, 10, 64); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		s.times = append(s.times, time.Unix(0, ns))
+	}
+
+	if len(s.times) != len(s.messages) {
+		return nil, lazyerrors.Errorf("got %d timestamps for %d messages", len(s.times), len(s.messages))
+	}
+
+	return s, nil
+}
+
+// replayConnect connects to the given URI and authenticates if credentials are present.
+func replayConnect(ctx context.Context, uri string, l *slog.Logger) (*wireclient.Conn, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	user := u.User
+	u.User = nil
+
+	conn, err := wireclient.Connect(ctx, u.String(), l)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if user == nil {
+		return conn, nil
+	}
+
+	password, _ := user.Password()
+	if err = conn.Login(ctx, user.Username(), password, "admin"); err != nil {
+		_ = conn.Close()
+		return nil, lazyerrors.Error(err)
+	}
+
+	return conn, nil
+}
+
+// replay sends recorded messages to the target and prints the report to w.
+func replay(ctx context.Context, params *ReplayParams, w io.Writer, logger *slog.Logger) error {
+	sessions, err := replayLoadSessions(params.Dir)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if len(sessions) == 0 {
+		return lazyerrors.Errorf("no records found in %s", params.Dir)
+	}
+
+	// the earliest recorded time is the start of the replay
+	var base time.Time
+
+	for _, s := range sessions {
+		if len(s.times) > 0 && (base.IsZero() || s.times[0].Before(base)) {
+			base = s.times[0]
+		}
+	}
+
+	var messages int
+	for _, s := range sessions {
+		messages += len(s.messages)
+	}
+
+	logger.InfoContext(ctx, fmt.Sprintf("Replaying %d messages from %d connections", messages, len(sessions)))
+
+	var stats replayStats
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+
+	for _, s := range sessions {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := replaySessionRun(ctx, params, s, start, base, &stats, logger); err != nil {
+				logger.WarnContext(ctx, "Connection failed", slog.String("file", s.file), logging.Error(err))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	stats.report(w, time.Since(start), params.CompareURI != "")
+
+	return nil
+}
+
+// replaySessionRun replays messages of a single session sequentially.
+func replaySessionRun(ctx context.Context, params *ReplayParams, s *replaySession, start, base time.Time, stats *replayStats, l *slog.Logger) error { //nolint:lll // for readability
+	conn, err := replayConnect(ctx, params.URI, l)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer conn.Close() //nolint:errcheck // not important for replay
+
+	cursors := newReplayCursors()
+
+	var compareConn *wireclient.Conn
+	var compareCursors *replayCursors
+
+	if params.CompareURI != "" {
+		if compareConn, err = replayConnect(ctx, params.CompareURI, l); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		defer compareConn.Close() //nolint:errcheck // not important for replay
+
+		compareCursors = newReplayCursors()
+	}
+
+	for i, m := range s.messages {
+		if params.Speed > 0 && s.times != nil {
+			at := start.Add(time.Duration(float64(s.times[i].Sub(base)) / params.Speed))
+
+			select {
+			case <-time.After(time.Until(at)):
+			case <-ctx.Done():
+				return context.Cause(ctx)
+			}
+		}
+
+		if ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+
+		command := replayCommand(m.Body)
+		if slices.Contains(replaySkippedCommands, command) {
+			stats.add(func(st *replayStats) { st.skipped++ })
+			continue
+		}
+
+		t := time.Now()
+
+		res, err := replayRequest(ctx, conn, cursors, m, params.Timeout)
+		latency := time.Since(t)
+
+		if err != nil {
+			l.WarnContext(ctx, "Request failed", slog.String("command", command), logging.Error(err))
+			stats.add(func(st *replayStats) { st.errors++ })
+
+			continue
+		}
+
+		var diff []diffreport.FieldDiff
+
+		if compareConn != nil {
+			var compareRes *wirebson.Document
+			if compareRes, err = replayRequest(ctx, compareConn, compareCursors, m, params.Timeout); err != nil {
+				l.WarnContext(ctx, "Compare request failed", slog.String("command", command), logging.Error(err))
+				stats.add(func(st *replayStats) { st.errors++ })
+
+				continue
+			}
+
+			diff = slices.DeleteFunc(diffreport.Diff(res, compareRes), func(d diffreport.FieldDiff) bool {
+				return slices.Contains(replayIgnoredPaths, d.Path)
+			})
+		}
+
+		if len(diff) > 0 {
+			l.WarnContext(ctx, "Responses are different", slog.String("command", command), slog.Any("diff", diff))
+		}
+
+		stats.add(func(st *replayStats) {
+			st.requests++
+			st.latencies = append(st.latencies, latency)
+
+			if res != nil && !replayOK(res) {
+				st.failed++
+			}
+
+			if len(diff) > 0 {
+				st.mismatches++
+			}
+		})
+	}
+
+	return nil
+}
+
+// replayRequest sends a single recorded message with a new request ID and returns the response document.
+// It returns nil document if the message does not expect a response.
+//
+// Cursor IDs in the message are replaced using cursors, and cursors returned by the target are tracked there.
+func replayRequest(ctx context.Context, conn *wireclient.Conn, cursors *replayCursors, m wire.Record, timeout time.Duration) (*wirebson.Document, error) { //nolint:lll // for readability
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if msg, ok := m.Body.(*wire.OpMsg); ok && msg.Flags.FlagSet(wire.OpMsgMoreToCome) {
+		header := *m.Header
+		if err := conn.Write(ctx, &header, m.Body); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return nil, nil
+	}
+
+	reqBody, err := cursors.rewrite(m.Body)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	_, body, err := conn.Request(ctx, reqBody)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var doc *wirebson.Document
+
+	switch body := body.(type) {
+	case *wire.OpMsg:
+		doc, err = body.DocumentDeep()
+	case *wire.OpReply:
+		doc, err = body.DocumentDeep()
+	default:
+		err = fmt.Errorf("unexpected response body type %T", body)
+	}
+
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	cursors.track(doc)
+
+	return doc, nil
+}
+
+// replayCommand returns the command name of the recorded message, or empty string.
+func replayCommand(body wire.MsgBody) string {
+	var doc *wirebson.Document
+
+	switch body := body.(type) {
+	case *wire.OpMsg:
+		doc, _ = body.Section0()
+	case *wire.OpQuery:
+		doc, _ = body.Query()
+	}
+
+	if doc == nil {
+		return ""
+	}
+
+	return doc.Command()
+}
+
+// replayOK returns true if the response document has `ok: 1`.
+func replayOK(doc *wirebson.Document) bool {
+	switch ok := doc.Get("ok").(type) {
+	case float64:
+		return ok == 1
+	case int32:
+		return ok == 1
+	case int64:
+		return ok == 1
+	default:
+		return false
+	}
+}
+
+// add calls f with locked stats.
+func (st *replayStats) add(f func(st *replayStats)) {
+	st.m.Lock()
+	defer st.m.Unlock()
+
+	f(st)
+}
+
+// replayPercentile returns the given percentile of sorted latencies.
+func replayPercentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	i := int(float64(len(sorted)-1) * p / 100)
+
+	return sorted[i]
+}
+
+// report writes the report to w.
+func (st *replayStats) report(w io.Writer, elapsed time.Duration, compare bool) {
+	st.m.Lock()
+	defer st.m.Unlock()
+
+	slices.Sort(st.latencies)
+
+	fmt.Fprintf(w, "Requests:   %d (%d skipped)\n", st.requests, st.skipped)
+	fmt.Fprintf(w, "Elapsed:    %s\n", elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "Throughput: %.1f requests/s\n", float64(st.requests)/elapsed.Seconds())
+	fmt.Fprintf(w, "Errors:     %d\n", st.errors)
+	fmt.Fprintf(w, "Failed:     %d\n", st.failed)
+
+	if compare {
+		fmt.Fprintf(w, "Mismatches: %d\n", st.mismatches)
+	}
+
+	fmt.Fprintf(w, "Latency:    p50=%s p90=%s p99=%s max=%s\n",
+		replayPercentile(st.latencies, 50),
+		replayPercentile(st.latencies, 90),
+		replayPercentile(st.latencies, 99),
+		replayPercentile(st.latencies, 100),
+	)
+}
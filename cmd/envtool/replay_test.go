@@ -0,0 +1,244 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+// replayServer starts a server that responds with `{ok: 1, n: <n>}` to every message.
+func replayServer(t *testing.T, n int32) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { lis.Close() })
+
+	go func() {
+		for {
+			c, err := lis.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer c.Close()
+
+				r := bufio.NewReader(c)
+				w := bufio.NewWriter(c)
+
+				for {
+					header, _, err := wire.ReadMessage(r)
+					if err != nil {
+						return
+					}
+
+					res := wire.MustOpMsg("ok", float64(1), "n", n)
+
+					b, err := res.MarshalBinary()
+					if err != nil {
+						return
+					}
+
+					resHeader := &wire.MsgHeader{
+						MessageLength: int32(wire.MsgHeaderLen + len(b)),
+						RequestID:     header.RequestID + 1,
+						ResponseTo:    header.RequestID,
+						OpCode:        wire.OpCodeMsg,
+					}
+
+					if err = wire.WriteMessage(w, resHeader, res); err != nil {
+						return
+					}
+
+					if err = w.Flush(); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	return "mongodb://" + lis.Addr().String() + "/"
+}
+
+// replayRecord writes a record file with given messages and timing file.
+func replayRecord(t *testing.T, dir string, msgs ...*wire.OpMsg) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var timing bytes.Buffer
+
+	w := bufio.NewWriter(&buf)
+	now := time.Now()
+
+	for i, msg := range msgs {
+		b, err := msg.MarshalBinary()
+		require.NoError(t, err)
+
+		header := &wire.MsgHeader{
+			MessageLength: int32(wire.MsgHeaderLen + len(b)),
+			RequestID:     int32(i + 1),
+			OpCode:        wire.OpCodeMsg,
+		}
+
+		require.NoError(t, wire.WriteMessage(w, header, msg))
+
+		fmt.Fprintln(&timing, now.Add(time.Duration(i)*time.Millisecond).UnixNano())
+	}
+
+	require.NoError(t, w.Flush())
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "ab"), 0o777))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ab", "abcd.bin"), buf.Bytes(), 0o666))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ab", "abcd.timing"), timing.Bytes(), 0o666))
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	replayRecord(
+		t, dir,
+		wire.MustOpMsg("saslStart", int32(1), "$db", "admin"),
+		wire.MustOpMsg("ping", int32(1), "$db", "test"),
+		wire.MustOpMsg("find", "test", "$db", "test"),
+	)
+
+	sessions, err := replayLoadSessions(dir)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Len(t, sessions[0].messages, 3)
+	assert.Len(t, sessions[0].times, 3)
+
+	uri := replayServer(t, 1)
+
+	t.Run("Same", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		params := &ReplayParams{
+			Dir:        dir,
+			URI:        uri,
+			CompareURI: uri,
+			Speed:      1,
+			Timeout:    time.Second,
+		}
+
+		require.NoError(t, replay(testutil.Ctx(t), params, &out, testutil.Logger(t)))
+
+		assert.Contains(t, out.String(), "Requests:   2 (1 skipped)\n")
+		assert.Contains(t, out.String(), "Mismatches: 0\n")
+	})
+
+	t.Run("Different", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		params := &ReplayParams{
+			Dir:        dir,
+			URI:        uri,
+			CompareURI: replayServer(t, 2),
+			Timeout:    time.Second,
+		}
+
+		require.NoError(t, replay(testutil.Ctx(t), params, &out, testutil.Logger(t)))
+
+		assert.Contains(t, out.String(), "Requests:   2 (1 skipped)\n")
+		assert.Contains(t, out.String(), "Mismatches: 2\n")
+	})
+}
+
+func TestReplayCursors(t *testing.T) {
+	t.Parallel()
+
+	rc := newReplayCursors()
+
+	rc.track(wirebson.MustDocument(
+		"cursor", wirebson.MustDocument("id", int64(100), "ns", "db.c", "firstBatch", wirebson.MakeArray(0)),
+		"ok", float64(1),
+	))
+	rc.track(wirebson.MustDocument(
+		"cursor", wirebson.MustDocument("id", int64(200), "ns", "db.c", "firstBatch", wirebson.MakeArray(0)),
+		"ok", float64(1),
+	))
+
+	// getMore response for the known cursor is not a new cursor
+	rc.track(wirebson.MustDocument(
+		"cursor", wirebson.MustDocument("id", int64(100), "ns", "db.c", "nextBatch", wirebson.MakeArray(0)),
+		"ok", float64(1),
+	))
+
+	body, err := rc.rewrite(wire.MustOpMsg("getMore", int64(1), "collection", "c", "$db", "db"))
+	require.NoError(t, err)
+
+	doc, err := body.(*wire.OpMsg).DocumentDeep()
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), doc.Get("getMore"))
+
+	body, err = rc.rewrite(wire.MustOpMsg(
+		"killCursors", "c", "cursors", wirebson.MustArray(int64(2), int64(1), int64(3)), "$db", "db",
+	))
+	require.NoError(t, err)
+
+	doc, err = body.(*wire.OpMsg).DocumentDeep()
+	require.NoError(t, err)
+	testutil.AssertEqual(t, wirebson.MustArray(int64(200), int64(100), int64(3)), doc.Get("cursors").(*wirebson.Array))
+
+	body, err = rc.rewrite(wire.MustOpMsg("getMore", int64(4), "collection", "other", "$db", "db"))
+	require.NoError(t, err)
+
+	doc, err = body.(*wire.OpMsg).DocumentDeep()
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), doc.Get("getMore"))
+}
+
+func TestReplayOK(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, replayOK(wirebson.MustDocument("ok", float64(1))))
+	assert.True(t, replayOK(wirebson.MustDocument("ok", int32(1))))
+	assert.False(t, replayOK(wirebson.MustDocument("ok", float64(0))))
+	assert.False(t, replayOK(wirebson.MustDocument()))
+}
+
+func TestReplayPercentile(t *testing.T) {
+	t.Parallel()
+
+	var latencies []time.Duration
+	for i := range 100 {
+		latencies = append(latencies, time.Duration(i+1)*time.Millisecond)
+	}
+
+	assert.Equal(t, time.Duration(0), replayPercentile(nil, 50))
+	assert.Equal(t, 50*time.Millisecond, replayPercentile(latencies, 50))
+	assert.Equal(t, 99*time.Millisecond, replayPercentile(latencies, 99))
+	assert.Equal(t, 100*time.Millisecond, replayPercentile(latencies, 100))
+}
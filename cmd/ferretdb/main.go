@@ -18,14 +18,19 @@
 	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"expvar"
 	"fmt"
 	"log"
 	"log/slog"
 	"math"
+	"net"
+	"net/http"
+	"net/netip"
 	"os"
 	"runtime"
 	runtimedebug "runtime/debug"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -40,9 +45,14 @@
 	"github.com/FerretDB/FerretDB/v2/build/version"
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn"
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/diffreport"
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/router"
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/shadow"
 	"github.com/FerretDB/FerretDB/v2/internal/dataapi"
 	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
 	"github.com/FerretDB/FerretDB/v2/internal/handler"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/topology"
+	"github.com/FerretDB/FerretDB/v2/internal/util/audit"
 	"github.com/FerretDB/FerretDB/v2/internal/util/ctxutil"
 	"github.com/FerretDB/FerretDB/v2/internal/util/debug"
 	"github.com/FerretDB/FerretDB/v2/internal/util/devbuild"
@@ -70,35 +80,77 @@
 	PostgreSQLURL     string `name:"postgresql-url"      default:"postgres://127.0.0.1:5432/postgres"                                                                   help:"PostgreSQL URL." group:"PostgreSQL"`
 	PostgreSQLURLFile []byte `name:"postgresql-url-file" help:"Path to a file containing the PostgreSQL connection URL. If non-empty, this overrides --postgresql-url." group:"PostgreSQL"     type:"filecontent"`
 
+	PostgreSQLReplicaURLs []string `name:"postgresql-replica-urls" default:"" help:"PostgreSQL streaming replica URLs for reads with non-primary read preference." group:"PostgreSQL"`
+
 	Listen struct {
-		Addr        string `default:"127.0.0.1:27017" help:"Listen TCP address for MongoDB protocol."`
-		Unix        string `default:""                help:"Listen Unix domain socket path for MongoDB protocol."`
-		TLS         string `default:""                help:"Listen TLS address for MongoDB protocol."`
-		TLSCertFile string `default:""                help:"TLS cert file path."`
-		TLSKeyFile  string `default:""                help:"TLS key file path."`
-		TLSCaFile   string `default:""                help:"TLS CA file path."`
-		DataAPIAddr string `default:""                help:"Listen TCP address for HTTP Data API."`
+		Addr        []string `default:"127.0.0.1:27017" help:"Listen TCP addresses for MongoDB protocol."`
+		Unix        string   `default:""                help:"Listen Unix domain socket path for MongoDB protocol."`
+		TLS         []string `default:""                help:"Listen TLS addresses for MongoDB protocol."`
+		TLSCertFile []string `default:""                help:"TLS cert file paths (one for all or one per TLS address)."`
+		TLSKeyFile  []string `default:""                help:"TLS key file paths (one for all or one per TLS address)."`
+		TLSCaFile   []string `default:""                help:"TLS CA file paths (one for all or one per TLS address)."`
+		DataAPIAddr string   `default:""                help:"Listen TCP address for HTTP Data API."`
+
+		ProxyProtocolTrusted []string `default:"" help:"Trusted networks (CIDRs) for PROXY protocol headers on TCP and TLS listeners."`
+
+		IdleTimeout          time.Duration `default:"0s"  help:"Close client connections that do not send requests for that time (0 disables)."`
+		TCPKeepAliveIdle     time.Duration `default:"15s" help:"TCP keep-alive idle time (negative value disables keep-alive)."                 name:"tcp-keepalive-idle"`
+		TCPKeepAliveInterval time.Duration `default:"15s" help:"TCP keep-alive probes interval."                                                 name:"tcp-keepalive-interval"`
+		TCPKeepAliveCount    int           `default:"9"   help:"TCP keep-alive probes count."                                                    name:"tcp-keepalive-count"`
 	} `embed:"" prefix:"listen-" group:"Interfaces"`
 
 	Proxy struct {
-		Addr        string `default:"" help:"Proxy address."`
-		TLSCertFile string `default:"" help:"Proxy TLS cert file path."`
-		TLSKeyFile  string `default:"" help:"Proxy TLS key file path."`
-		TLSCaFile   string `default:"" help:"Proxy TLS CA file path."`
+		Addr        string `default:""    help:"Proxy address."`
+		TLSCertFile string `default:""    help:"Proxy TLS cert file path."`
+		TLSKeyFile  string `default:""    help:"Proxy TLS key file path."`
+		TLSCaFile   string `default:""    help:"Proxy TLS CA file path."`
+		MaxConns    int    `default:"100" help:"Maximum number of proxy connections for client connections."`
 	} `embed:"" prefix:"proxy-" group:"Interfaces"`
 
+	ReplSet struct {
+		Name     string   `default:"" help:"Replica set name reported by hello."`
+		Self     string   `default:"" help:"Address of this instance as seen by clients and replica set peers (host:port)."`
+		Peers    []string `default:"" help:"Addresses of other replica set instances (host:port)."`
+		PeersSRV string   `default:"" help:"DNS SRV record name to discover other replica set instances if peers are not set."`
+	} `embed:"" prefix:"repl-set-" group:"Interfaces"`
+
 	DebugAddr string `default:"127.0.0.1:8088" help:"Listen address for HTTP handlers for metrics, pprof, etc." group:"Interfaces"`
 
 	Mode     string `default:"${default_mode}" help:"${help_mode}"                           enum:"${enum_mode}"   group:"Miscellaneous"`
 	StateDir string `default:"."               help:"Process state directory."               group:"Miscellaneous"`
 	Auth     bool   `default:"true"            help:"Enable authentication (on by default)." group:"Miscellaneous" negatable:""`
 
+	DiffReportPath string `default:"" help:"Path to JSON Lines file for mismatch records in diff modes." group:"Miscellaneous"`
+
+	Shadow struct {
+		QueueSize  int     `default:"1000"                     help:"Maximum number of read or write requests waiting to be mirrored in shadow mode."`
+		SampleRate float64 `default:"1"                        help:"Fraction of read requests mirrored in shadow mode (above 0, up to 1); writes are always mirrored."`
+		Filter     string  `default:"${default_shadow_filter}" help:"${help_shadow_filter}"                                                                             enum:"${enum_shadow_filter}"`
+	} `embed:"" prefix:"shadow-" group:"Miscellaneous"`
+
+	Routing struct {
+		Rules []string `default:"" help:"Routing rules for routing mode: 'database[.collection]=normal|proxy' with glob patterns."`
+	} `embed:"" prefix:"routing-" group:"Miscellaneous"`
+
+	ShutdownDrainDelay time.Duration `default:"2s"  help:"Time to report shutdown to clients and probes before closing listeners." group:"Miscellaneous"`
+	ShutdownTimeout    time.Duration `default:"3s"  help:"Time given to connections to finish in-flight requests on shutdown."     group:"Miscellaneous"`
+	SlowOpThreshold    time.Duration `default:"0s"  help:"Log requests that take longer than that at WARN level (0 disables)."     group:"Miscellaneous"`
+	CursorTimeout      time.Duration `default:"10m" help:"Close cursors that were not used for that time."                         group:"Miscellaneous"`
+	SessionTimeout     time.Duration `default:"30m" help:"Expire sessions that were not used for that time (whole minutes)."       group:"Miscellaneous"`
+	MaxConnections     int32         `default:"0"   help:"Maximum number of client connections (0 means no limit)."                group:"Miscellaneous"`
+
 	Log struct {
 		Level  string `default:"${default_log_level}" help:"${help_log_level}"`
 		Format string `default:"console"              help:"${help_log_format}"                     enum:"${enum_log_format}"`
 		UUID   bool   `default:"false"                help:"Add instance UUID to all log messages." negatable:""`
 	} `embed:"" prefix:"log-" group:"Miscellaneous"`
 
+	Audit struct {
+		Destination string   `default:""                      help:"${help_audit_destination}" enum:"${enum_audit_destination}"`
+		Path        string   `default:"ferretdb-audit.jsonl" help:"Audit log file path for 'file' destination."`
+		Filter      []string `default:""                      help:"${help_audit_filter}"`
+	} `embed:"" prefix:"audit-" group:"Miscellaneous"`
+
 	MetricsUUID bool `default:"false" help:"Add instance UUID to all metrics." group:"Miscellaneous" negatable:""`
 
 	OTel struct {
@@ -110,9 +162,8 @@
 	Telemetry telemetry.Flag `default:"undecided" help:"${help_telemetry}" group:"Miscellaneous"`
 
 	Dev struct {
-		Version     bool   `hidden:""`
-		ReplSetName string `hidden:""`
-		RecordsDir  string `hidden:""`
+		Version    bool   `hidden:""`
+		RecordsDir string `hidden:""`
 
 		Telemetry struct {
 			URL            string        `default:"https://beacon.ferretdb.com/" hidden:""`
@@ -134,18 +185,26 @@
 
 	logFormats = []string{"console", "text", "json", "mongo"}
 
+	auditDestinations = []string{"", "file", "syslog"}
+
 	kongOptions = []kong.Option{
 		kong.Vars{
-			"default_log_level": defaultLogLevel().String(),
-			"default_mode":      clientconn.AllModes[0],
-
-			"enum_log_format": strings.Join(logFormats, ","),
-			"enum_mode":       strings.Join(clientconn.AllModes, ","),
-
-			"help_log_format": fmt.Sprintf("Log format: '%s'.", strings.Join(logFormats, "', '")),
-			"help_log_level":  fmt.Sprintf("Log level: '%s'.", strings.Join(logLevels, "', '")),
-			"help_mode":       fmt.Sprintf("Operation mode: '%s'.", strings.Join(clientconn.AllModes, "', '")),
-			"help_telemetry":  "Enable or disable basic telemetry reporting. See https://beacon.ferretdb.com.",
+			"default_log_level":     defaultLogLevel().String(),
+			"default_mode":          clientconn.AllModes[0],
+			"default_shadow_filter": shadow.AllFilters[0],
+
+			"enum_audit_destination": strings.Join(auditDestinations, ","),
+			"enum_log_format":        strings.Join(logFormats, ","),
+			"enum_mode":              strings.Join(clientconn.AllModes, ","),
+			"enum_shadow_filter":     strings.Join(shadow.AllFilters, ","),
+
+			"help_audit_destination": "Audit log destination: 'file', 'syslog' (empty value disables audit log).",
+			"help_audit_filter":      fmt.Sprintf("Audit event types to record (all if empty): '%s'.", strings.Join(auditTypes(), "', '")),
+			"help_log_format":        fmt.Sprintf("Log format: '%s'.", strings.Join(logFormats, "', '")),
+			"help_log_level":         fmt.Sprintf("Log level: '%s'.", strings.Join(logLevels, "', '")),
+			"help_mode":              fmt.Sprintf("Operation mode: '%s'.", strings.Join(clientconn.AllModes, "', '")),
+			"help_shadow_filter":     fmt.Sprintf("Requests mirrored in shadow mode (the proxy must not require authentication): '%s'.", strings.Join(shadow.AllFilters, "', '")),
+			"help_telemetry":         "Enable or disable basic telemetry reporting. See https://beacon.ferretdb.com.",
 		},
 		kong.DefaultEnvars("FERRETDB"),
 	}
@@ -187,6 +246,193 @@ func defaultLogLevel() slog.Level {
 	return slog.LevelInfo
 }
 
+// auditTypes returns all audit event types as strings.
+func auditTypes() []string {
+	res := make([]string, len(audit.AllTypes))
+	for i, t := range audit.AllTypes {
+		res[i] = string(t)
+	}
+
+	return res
+}
+
+// listenAddrs returns listen addresses without empty and disabled (`-`) values.
+func listenAddrs(addrs []string) []string {
+	var res []string
+
+	for _, addr := range addrs {
+		if cmp.Or(addr, "-") != "-" {
+			res = append(res, addr)
+		}
+	}
+
+	return res
+}
+
+// advertisedAddr returns the single address reported to clients by `hello`.
+//
+// It is the replica set self address if set, or the first TCP or TLS listen address
+// with unspecified host (like `0.0.0.0` or `[::]`) replaced by the hostname,
+// because drivers connect to reported addresses during discovery.
+// It returns an empty string if there are no such addresses.
+func advertisedAddr(addrs []string) (string, error) {
+	if cli.ReplSet.Self != "" {
+		return cli.ReplSet.Self, nil
+	}
+
+	if len(addrs) == 0 {
+		return "", nil
+	}
+
+	host, port, err := net.SplitHostPort(addrs[0])
+	if err != nil {
+		return "", err
+	}
+
+	if ip, e := netip.ParseAddr(host); host != "" && (e != nil || !ip.IsUnspecified()) {
+		return addrs[0], nil
+	}
+
+	if host, err = os.Hostname(); err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+// tlsListenerOpts returns TLS listeners configuration based on provided flags.
+//
+// Each of TLS cert, key, and CA file flags should have zero values, one value shared by all TLS listeners,
+// or one value per TLS address.
+func tlsListenerOpts() ([]clientconn.TLSListenerOpts, error) {
+	addrs := listenAddrs(cli.Listen.TLS)
+
+	files := map[string][]string{
+		"--listen-tls-cert-file": cli.Listen.TLSCertFile,
+		"--listen-tls-key-file":  cli.Listen.TLSKeyFile,
+		"--listen-tls-ca-file":   cli.Listen.TLSCaFile,
+	}
+
+	for flag, values := range files {
+		if l := len(values); l > 1 && l != len(addrs) {
+			return nil, fmt.Errorf("%s has %d values, but there are %d TLS addresses", flag, l, len(addrs))
+		}
+	}
+
+	// pick returns value for the i-th TLS address.
+	pick := func(values []string, i int) string {
+		switch len(values) {
+		case 0:
+			return ""
+		case 1:
+			return values[0]
+		default:
+			return values[i]
+		}
+	}
+
+	res := make([]clientconn.TLSListenerOpts, len(addrs))
+	for i, addr := range addrs {
+		res[i] = clientconn.TLSListenerOpts{
+			Addr:     addr,
+			CertFile: pick(cli.Listen.TLSCertFile, i),
+			KeyFile:  pick(cli.Listen.TLSKeyFile, i),
+			CAFile:   pick(cli.Listen.TLSCaFile, i),
+		}
+	}
+
+	return res, nil
+}
+
+// setupAuditor setups auditor based on provided flags.
+// It returns nil if audit log is disabled.
+func setupAuditor(logger *slog.Logger) (*audit.Auditor, error) {
+	var sink audit.Sink
+	var err error
+
+	switch cli.Audit.Destination {
+	case "":
+		return nil, nil
+	case "file":
+		sink, err = audit.NewFileSink(cli.Audit.Path)
+	case "syslog":
+		sink, err = audit.NewSyslogSink("ferretdb")
+	default:
+		panic("unknown audit destination")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var types []audit.Type
+	for _, t := range cli.Audit.Filter {
+		if t != "" {
+			types = append(types, audit.Type(t))
+		}
+	}
+
+	a, err := audit.New(&audit.NewOpts{
+		Sink:  sink,
+		L:     logger,
+		Types: types,
+	})
+	if err != nil {
+		_ = sink.Close()
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// setupTopology creates replica set topology if peers are configured.
+// It returns nil if they are not.
+func setupTopology(p *documentdb.Pool, tcpAddrs []string, logger *slog.Logger) (*topology.Topology, error) {
+	var peers []string
+
+	for _, peer := range cli.ReplSet.Peers {
+		if peer != "" {
+			peers = append(peers, peer)
+		}
+	}
+
+	if len(peers) == 0 && cli.ReplSet.PeersSRV == "" {
+		return nil, nil
+	}
+
+	if cli.ReplSet.Name == "" {
+		return nil, errors.New("replica set peers require replica set name")
+	}
+
+	self := cli.ReplSet.Self
+	if self == "" {
+		if len(tcpAddrs) == 0 {
+			return nil, errors.New("replica set peers require TCP listener or self address")
+		}
+
+		_, port, err := net.SplitHostPort(tcpAddrs[0])
+		if err != nil {
+			return nil, err
+		}
+
+		host, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+
+		self = net.JoinHostPort(host, port)
+	}
+
+	return topology.New(&topology.NewOpts{
+		L:         logger,
+		Self:      self,
+		Peers:     peers,
+		SRV:       cli.ReplSet.PeersSRV,
+		IsPrimary: p.IsPrimary,
+		Timeline:  p.Timeline,
+	})
+}
+
 // setupExpvar setups expvar variables for debug handler.
 func setupExpvar(stateProvider *state.Provider) {
 	// do not include sensitive information like the full PostgreSQL URL
@@ -230,6 +476,9 @@ func setupMetrics(stateProvider *state.Provider) prometheus.Registerer {
 	return r
 }
 
+// logLevel is the log level of the default logger that could be changed at runtime.
+var logLevel = new(slog.LevelVar)
+
 // setupDefaultLogger setups slog logging.
 func setupDefaultLogger(format string, uuid string) *slog.Logger {
 	var level slog.Level
@@ -237,9 +486,11 @@ func setupDefaultLogger(format string, uuid string) *slog.Logger {
 		log.Fatal(err)
 	}
 
+	logLevel.Set(level)
+
 	opts := &logging.NewHandlerOpts{
 		Base:       format,
-		Level:      level,
+		Level:      logLevel,
 		SkipChecks: !devbuild.Enabled,
 	}
 	logging.SetupDefault(opts, uuid)
@@ -378,6 +629,54 @@ func run() {
 	// used to start debug handler with probes as soon as possible, even before listener is created
 	var listener atomic.Pointer[clientconn.Listener]
 
+	// keep debug handler running while listener drains connections,
+	// so the readiness probe reports that
+	debugCtx, debugCancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer debugCancel()
+
+	// used by index usage page and diff reporter once handler is created
+	var readyHandler atomic.Pointer[handler.Handler]
+
+	var diffReporter *diffreport.Reporter
+	debugPages := map[string]debug.Page{
+		"/debug/indexes": {
+			Handler: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				h := readyHandler.Load()
+				if h == nil {
+					http.Error(rw, "handler is not ready", http.StatusServiceUnavailable)
+					return
+				}
+
+				h.ServeIndexUsage(rw, req)
+			}),
+			Description: "Unused and duplicate indexes",
+		},
+	}
+
+	if m := clientconn.Mode(cli.Mode); m == clientconn.DiffNormalMode || m == clientconn.DiffProxyMode {
+		if diffReporter, err = diffreport.New(&diffreport.NewOpts{
+			L:    logging.WithName(logger, "diff"),
+			Path: cli.DiffReportPath,
+			KnownCommand: func(name string) bool {
+				h := readyHandler.Load()
+				return h != nil && h.IsCommand(name)
+			},
+		}); err != nil {
+			logger.LogAttrs(ctx, logging.LevelFatal, "Failed to create diff reporter", logging.Error(err))
+		}
+
+		defer func() {
+			if e := diffReporter.Close(); e != nil {
+				logger.ErrorContext(ctx, "Failed to close diff reporter", logging.Error(e))
+			}
+		}()
+
+		debugPages["/debug/diff"] = debug.Page{
+			Handler:     diffReporter,
+			Description: "Summary of differences in diff modes",
+		}
+	}
+
 	var wg sync.WaitGroup
 
 	if cmp.Or(cli.DebugAddr, "-") != "-" {
@@ -400,16 +699,24 @@ func run() {
 						return false
 					}
 
-					return listener.Load().Listening()
+					return listener.Load().Listening() || listener.Load().Draining()
+				},
+
+				Readyz: func(ctx context.Context) bool {
+					if listener.Load() == nil || listener.Load().Draining() {
+						return false
+					}
+
+					return ready.Probe(ctx)
 				},
 
-				Readyz: ready.Probe,
+				Pages: debugPages,
 			})
 			if e != nil {
 				l.LogAttrs(ctx, logging.LevelFatal, "Failed to create debug handler", logging.Error(e))
 			}
 
-			h.Serve(ctx)
+			h.Serve(debugCtx)
 		}()
 	}
 
@@ -465,36 +772,100 @@ func run() {
 		}()
 	}
 
-	p, err := documentdb.NewPool(cli.PostgreSQLURL, logging.WithName(logger, "pool"), stateProvider)
+	var replicaURLs []string
+
+	for _, u := range cli.PostgreSQLReplicaURLs {
+		if u != "" {
+			replicaURLs = append(replicaURLs, u)
+		}
+	}
+
+	p, err := documentdb.NewPoolWithReplicas(cli.PostgreSQLURL, replicaURLs, logging.WithName(logger, "pool"), stateProvider)
 	if err != nil {
 		logger.LogAttrs(ctx, logging.LevelFatal, "Failed to construct pool", logging.Error(err))
 	}
 
-	tcpAddr := cli.Listen.Addr
-	if cmp.Or(tcpAddr, "-") == "-" {
-		tcpAddr = ""
-	}
+	tcpAddrs := listenAddrs(cli.Listen.Addr)
 
 	unixAddr := cli.Listen.Unix
 	if cmp.Or(unixAddr, "-") == "-" {
 		unixAddr = ""
 	}
 
-	tlsAddr := cli.Listen.TLS
-	if cmp.Or(tlsAddr, "-") == "-" {
-		tlsAddr = ""
+	tlsOpts, err := tlsListenerOpts()
+	if err != nil {
+		p.Close()
+		logger.LogAttrs(ctx, logging.LevelFatal, "Failed to configure TLS listeners", logging.Error(err))
+	}
+
+	var proxyProtocolTrusted []netip.Prefix
+
+	for _, c := range cli.Listen.ProxyProtocolTrusted {
+		if c == "" {
+			continue
+		}
+
+		var prefix netip.Prefix
+		if prefix, err = netip.ParsePrefix(c); err != nil {
+			p.Close()
+			logger.LogAttrs(ctx, logging.LevelFatal, "Failed to parse PROXY protocol trusted network", logging.Error(err))
+		}
+
+		proxyProtocolTrusted = append(proxyProtocolTrusted, prefix.Masked())
+	}
+
+	var routingRules []router.Rule
+
+	for _, r := range cli.Routing.Rules {
+		if r == "" {
+			continue
+		}
+
+		var rule router.Rule
+		if rule, err = router.ParseRule(r); err != nil {
+			p.Close()
+			logger.LogAttrs(ctx, logging.LevelFatal, "Failed to parse routing rule", logging.Error(err))
+		}
+
+		routingRules = append(routingRules, rule)
+	}
+
+	auditor, err := setupAuditor(logging.WithName(logger, "audit"))
+	if err != nil {
+		p.Close()
+		logger.LogAttrs(ctx, logging.LevelFatal, "Failed to set up audit log", logging.Error(err))
+	}
+
+	topo, err := setupTopology(p, tcpAddrs, logging.WithName(logger, "topology"))
+	if err != nil {
+		p.Close()
+		logger.LogAttrs(ctx, logging.LevelFatal, "Failed to set up replica set topology", logging.Error(err))
+	}
+
+	host, err := advertisedAddr(append(slices.Clone(tcpAddrs), listenAddrs(cli.Listen.TLS)...))
+	if err != nil {
+		p.Close()
+		logger.LogAttrs(ctx, logging.LevelFatal, "Failed to get advertised address", logging.Error(err))
 	}
 
 	handlerOpts := &handler.NewOpts{
 		Pool: p,
 		Auth: cli.Auth,
 
-		TCPHost:     tcpAddr,
-		ReplSetName: cli.Dev.ReplSetName,
+		Host:        host,
+		ReplSetName: cli.ReplSet.Name,
+		Topology:    topo,
 
 		L:             logging.WithName(logger, "handler"),
 		ConnMetrics:   lm.ConnMetrics,
 		StateProvider: stateProvider,
+		Audit:         auditor,
+
+		LogLevel:        logLevel,
+		SlowOpThreshold: cli.SlowOpThreshold,
+		CursorTimeout:   cli.CursorTimeout,
+		SessionTimeout:  cli.SessionTimeout,
+		MaxConnections:  cli.MaxConnections,
 	}
 
 	h, err := handler.New(handlerOpts)
@@ -503,26 +874,47 @@ func run() {
 		handlerOpts.L.LogAttrs(ctx, logging.LevelFatal, "Failed to construct handler", logging.Error(err))
 	}
 
+	readyHandler.Store(h)
+
 	lis, err := clientconn.Listen(&clientconn.ListenerOpts{
 		Handler: h,
 		Metrics: lm,
 		Logger:  logger,
 
-		TCP:  tcpAddr,
+		TCP:  tcpAddrs,
 		Unix: unixAddr,
+		TLS:  tlsOpts,
 
-		TLS:         tlsAddr,
-		TLSCertFile: cli.Listen.TLSCertFile,
-		TLSKeyFile:  cli.Listen.TLSKeyFile,
-		TLSCAFile:   cli.Listen.TLSCaFile,
+		ProxyProtocolTrusted: proxyProtocolTrusted,
+
+		IdleTimeout: cli.Listen.IdleTimeout,
+		TCPKeepAlive: net.KeepAliveConfig{
+			Enable:   cli.Listen.TCPKeepAliveIdle >= 0,
+			Idle:     cli.Listen.TCPKeepAliveIdle,
+			Interval: cli.Listen.TCPKeepAliveInterval,
+			Count:    cli.Listen.TCPKeepAliveCount,
+		},
 
 		Mode:             clientconn.Mode(cli.Mode),
 		ProxyAddr:        cli.Proxy.Addr,
 		ProxyTLSCertFile: cli.Proxy.TLSCertFile,
 		ProxyTLSKeyFile:  cli.Proxy.TLSKeyFile,
 		ProxyTLSCAFile:   cli.Proxy.TLSCaFile,
+		ProxyMaxConns:    cli.Proxy.MaxConns,
+
+		RoutingRules: routingRules,
+
+		Shadow: clientconn.ShadowOpts{
+			QueueSize:  cli.Shadow.QueueSize,
+			SampleRate: cli.Shadow.SampleRate,
+			Filter:     shadow.Filter(cli.Shadow.Filter),
+		},
 
 		TestRecordsDir: cli.Dev.RecordsDir,
+		DiffReporter:   diffReporter,
+
+		ShutdownDrainDelay: cli.ShutdownDrainDelay,
+		ShutdownTimeout:    cli.ShutdownTimeout,
 	})
 	if err != nil {
 		p.Close()
@@ -557,6 +949,8 @@ func run() {
 
 	lis.Run(ctx)
 
+	debugCancel()
+
 	wg.Wait()
 
 	if devbuild.Enabled {
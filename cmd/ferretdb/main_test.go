@@ -17,6 +17,8 @@
 import (
 	"context"
 	"encoding/json"
+	"net"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
@@ -71,3 +73,47 @@ func TestDeps(t *testing.T) {
 
 	assert.NotContains(t, res.Deps, "testing", `package "testing" should not be imported by non-testing code`)
 }
+
+func TestAdvertisedAddr(t *testing.T) {
+	t.Parallel()
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		addrs    []string
+		expected string
+	}{
+		"None": {
+			expected: "",
+		},
+		"Loopback": {
+			addrs:    []string{"127.0.0.1:27017", "0.0.0.0:27018"},
+			expected: "127.0.0.1:27017",
+		},
+		"Name": {
+			addrs:    []string{"ferretdb:27017"},
+			expected: "ferretdb:27017",
+		},
+		"EmptyHost": {
+			addrs:    []string{":27017"},
+			expected: net.JoinHostPort(hostname, "27017"),
+		},
+		"IPv4Unspecified": {
+			addrs:    []string{"0.0.0.0:27017"},
+			expected: net.JoinHostPort(hostname, "27017"),
+		},
+		"IPv6Unspecified": {
+			addrs:    []string{"[::]:27017"},
+			expected: net.JoinHostPort(hostname, "27017"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := advertisedAddr(tc.addrs)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
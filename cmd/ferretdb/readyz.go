@@ -39,8 +39,8 @@ type ReadyZ struct {
 func (r *ReadyZ) Probe(ctx context.Context) bool {
 	var urls []string
 
-	if cmp.Or(cli.Listen.Addr, "-") != "-" {
-		host, port, err := net.SplitHostPort(cli.Listen.Addr)
+	for _, addr := range listenAddrs(cli.Listen.Addr) {
+		host, port, err := net.SplitHostPort(addr)
 		if err != nil {
 			r.l.ErrorContext(ctx, "Getting host and port failed", logging.Error(err))
 			return false
@@ -59,7 +59,7 @@ func (r *ReadyZ) Probe(ctx context.Context) bool {
 		urls = append(urls, u.String())
 	}
 
-	if cmp.Or(cli.Listen.TLS, "-") != "-" {
+	if len(listenAddrs(cli.Listen.TLS)) > 0 {
 		// TODO https://github.com/FerretDB/FerretDB/issues/4427
 		r.l.WarnContext(ctx, "TLS ping is not implemented yet")
 	}
@@ -39,6 +39,7 @@
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn/connmetrics"
 	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
 	"github.com/FerretDB/FerretDB/v2/internal/handler"
+	"github.com/FerretDB/FerretDB/v2/internal/util/audit"
 	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
 	"github.com/FerretDB/FerretDB/v2/internal/util/state"
 	"github.com/FerretDB/FerretDB/v2/internal/util/telemetry"
@@ -65,12 +66,19 @@ type Config struct {
 	// Defaults to [io.Discard], effectively disabling logging.
 	LogOutput io.Writer
 
+	// If set, called for each audit event (authentication, user management, DDL).
+	// Defaults to nil, disabling audit log.
+	AuditFunc func(r *AuditRecord)
+
 	// Defaults to undecided.
 	// Set to `true` to enable telemetry, `false` to disable it.
 	// See https://docs.ferretdb.io/telemetry/.
 	Telemetry *bool
 }
 
+// AuditRecord represents a single audit event passed to [Config]'s AuditFunc.
+type AuditRecord = audit.Record
+
 // FerretDB represents an instance of embedded FerretDB implementation.
 type FerretDB struct {
 	tl  *telemetry.Reporter
@@ -129,16 +137,31 @@ func New(config *Config) (*FerretDB, error) {
 		return nil, fmt.Errorf("failed to construct pool: %w", err)
 	}
 
+	var auditor *audit.Auditor
+
+	if config.AuditFunc != nil {
+		auditor, err = audit.New(&audit.NewOpts{
+			Sink: audit.FuncSink(config.AuditFunc),
+			L:    logging.WithName(logger, "audit"),
+		})
+		if err != nil {
+			p.Close()
+			return nil, fmt.Errorf("failed to construct auditor: %w", err)
+		}
+	}
+
 	handlerOpts := &handler.NewOpts{
 		Pool: p,
 		Auth: false,
 
-		TCPHost:     "",
+		Host:        "",
 		ReplSetName: "",
+		Topology:    nil,
 
 		L:             logging.WithName(logger, "handler"),
 		ConnMetrics:   lm.ConnMetrics,
 		StateProvider: stateProvider,
+		Audit:         auditor,
 	}
 
 	h, err := handler.New(handlerOpts)
@@ -147,12 +170,17 @@ func New(config *Config) (*FerretDB, error) {
 		return nil, fmt.Errorf("failed to construct handler: %w", err)
 	}
 
+	var tcp []string
+	if config.ListenAddr != "" {
+		tcp = []string{config.ListenAddr}
+	}
+
 	lis, err := clientconn.Listen(&clientconn.ListenerOpts{
 		Handler: h,
 		Metrics: lm,
 		Logger:  logger,
 
-		TCP: config.ListenAddr,
+		TCP: tcp,
 
 		Mode: clientconn.NormalMode,
 	})
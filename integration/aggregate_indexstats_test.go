@@ -0,0 +1,88 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+	"github.com/FerretDB/FerretDB/v2/integration/shareddata"
+)
+
+func TestAggregateIndexStats(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t, shareddata.Int32s)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"v", int32(1)}}})
+	require.NoError(t, err)
+
+	// use the index
+	err = collection.FindOne(ctx, bson.D{{"v", int32(42)}}, options.FindOne().SetHint("v_1")).Err()
+	require.NoError(t, err)
+
+	cursor, err := collection.Aggregate(ctx, bson.A{
+		bson.D{{"$indexStats", bson.D{}}},
+		bson.D{{"$sort", bson.D{{"name", int32(1)}}}},
+	})
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+	require.Len(t, res, 2)
+
+	for i, expected := range []struct {
+		name string
+		key  bson.D
+	}{
+		{name: "_id_", key: bson.D{{"_id", int32(1)}}},
+		{name: "v_1", key: bson.D{{"v", int32(1)}}},
+	} {
+		m := res[i].Map()
+
+		assert.Equal(t, expected.name, m["name"])
+		assert.Equal(t, expected.key, m["key"])
+		assert.NotEmpty(t, m["host"])
+
+		accesses, ok := m["accesses"].(bson.D)
+		require.True(t, ok)
+		assert.IsType(t, int64(0), accesses.Map()["ops"])
+		assert.IsType(t, primitive.DateTime(0), accesses.Map()["since"])
+
+		spec, ok := m["spec"].(bson.D)
+		require.True(t, ok)
+		assert.Equal(t, expected.name, spec.Map()["name"])
+	}
+}
+
+func TestAggregateIndexStatsErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.Aggregate(ctx, bson.A{bson.D{{"$indexStats", bson.D{{"a", int32(1)}}}}})
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    28803,
+		Name:    "Location28803",
+		Message: "The $indexStats stage specification must be an empty object",
+	}, err)
+}
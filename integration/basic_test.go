@@ -566,7 +566,7 @@ func TestDebugCommandErrors(t *testing.T) {
 
 		var doc bson.D
 		require.NoError(t, res.Decode(&doc))
-		assert.Equal(t, bson.D{{"ok", float64(1)}}, doc)
+		AssertEqualDocuments(t, bson.D{{"ok", float64(1)}}, doc)
 
 		require.NoError(t, db.Client().Ping(s.Ctx, nil), "connection should not be closed")
 	})
@@ -0,0 +1,109 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestCausalConsistency(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	t.Run("Session", func(t *testing.T) {
+		t.Parallel()
+
+		sess, err := collection.Database().Client().StartSession(options.Session().SetCausalConsistency(true))
+		require.NoError(t, err)
+
+		t.Cleanup(func() {
+			sess.EndSession(ctx)
+		})
+
+		err = mongo.WithSession(ctx, sess, func(sctx mongo.SessionContext) error {
+			if _, err := collection.InsertOne(sctx, bson.D{{"_id", "session"}}); err != nil {
+				return err
+			}
+
+			writeTime := sess.OperationTime()
+			require.NotNil(t, writeTime)
+			assert.NotNil(t, sess.ClusterTime())
+
+			// the driver sends afterClusterTime with the operation time of the insert
+			var doc bson.D
+			if err := collection.FindOne(sctx, bson.D{{"_id", "session"}}).Decode(&doc); err != nil {
+				return err
+			}
+
+			assert.Equal(t, bson.D{{"_id", "session"}}, doc)
+
+			readTime := sess.OperationTime()
+			require.NotNil(t, readTime)
+			assert.False(t, readTime.Before(*writeTime), "%v < %v", readTime, writeTime)
+
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Reply", func(t *testing.T) {
+		t.Parallel()
+
+		var res bson.D
+		err := collection.Database().RunCommand(ctx, bson.D{
+			{"insert", collection.Name()},
+			{"documents", bson.A{bson.D{{"_id", "reply"}}}},
+		}).Decode(&res)
+		require.NoError(t, err)
+
+		m := res.Map()
+		require.IsType(t, primitive.Timestamp{}, m["operationTime"])
+
+		clusterTime, ok := m["$clusterTime"].(bson.D)
+		require.True(t, ok, "%v", res)
+		assert.IsType(t, primitive.Timestamp{}, clusterTime.Map()["clusterTime"])
+		assert.IsType(t, bson.D{}, clusterTime.Map()["signature"])
+
+		err = collection.Database().RunCommand(ctx, bson.D{
+			{"find", collection.Name()},
+			{"readConcern", bson.D{{"afterClusterTime", m["operationTime"]}}},
+		}).Decode(&res)
+		require.NoError(t, err)
+	})
+
+	t.Run("AfterClusterTimeInFuture", func(t *testing.T) {
+		t.Parallel()
+
+		err := collection.Database().RunCommand(ctx, bson.D{
+			{"find", collection.Name()},
+			{"readConcern", bson.D{{"afterClusterTime", primitive.Timestamp{T: math.MaxUint32, I: 1}}}},
+		}).Err()
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, int32(72), ce.Code)
+	})
+}
@@ -849,6 +849,87 @@ func TestGetParameterCommandAuthenticationMechanisms(t *testing.T) {
 	})
 }
 
+func TestSetParameterCommand(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, &setup.SetupOpts{
+		DatabaseName: "admin",
+	})
+
+	ctx, db := s.Ctx, s.Collection.Database()
+
+	t.Run("CursorTimeoutMillis", func(t *testing.T) {
+		t.Parallel()
+
+		var res bson.D
+		err := db.RunCommand(ctx, bson.D{{"getParameter", 1}, {"cursorTimeoutMillis", 1}}).Decode(&res)
+		require.NoError(t, err)
+
+		value := res.Map()["cursorTimeoutMillis"]
+		require.IsType(t, int64(0), value)
+
+		// set the same value to avoid affecting other tests
+		err = db.RunCommand(ctx, bson.D{{"setParameter", 1}, {"cursorTimeoutMillis", value}}).Decode(&res)
+		require.NoError(t, err)
+
+		AssertEqualDocuments(t, bson.D{{"was", value}, {"ok", float64(1)}}, res)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		t.Parallel()
+
+		err := db.RunCommand(ctx, bson.D{{"setParameter", 1}, {"noSuchParameter", 1}}).Err()
+
+		expected := mongo.CommandError{
+			Code:    72,
+			Name:    "InvalidOptions",
+			Message: "attempted to set unrecognized parameter [noSuchParameter], use help:true to see options ",
+		}
+		AssertMatchesCommandError(t, expected, err)
+	})
+
+	t.Run("NotSettable", func(t *testing.T) {
+		t.Parallel()
+
+		err := db.RunCommand(ctx, bson.D{{"setParameter", 1}, {"authenticationMechanisms", "SCRAM-SHA-256"}}).Err()
+
+		expected := mongo.CommandError{
+			Code:    20,
+			Name:    "IllegalOperation",
+			Message: "not allowed to change [authenticationMechanisms] at runtime",
+		}
+		AssertMatchesCommandError(t, expected, err)
+	})
+
+	t.Run("NoOption", func(t *testing.T) {
+		t.Parallel()
+
+		err := db.RunCommand(ctx, bson.D{{"setParameter", 1}}).Err()
+
+		expected := mongo.CommandError{
+			Code:    72,
+			Name:    "InvalidOptions",
+			Message: "no option found to set, use help:true to see options ",
+		}
+		AssertMatchesCommandError(t, expected, err)
+	})
+
+	t.Run("NotAdmin", func(t *testing.T) {
+		t.Parallel()
+
+		err := s.Collection.Database().Client().Database("test").RunCommand(
+			ctx, bson.D{{"setParameter", 1}, {"quiet", false}},
+		).Err()
+
+		expected := mongo.CommandError{
+			Code:    13,
+			Name:    "Unauthorized",
+			Message: "setParameter may only be run against the admin database.",
+		}
+		AssertEqualCommandError(t, expected, err)
+	})
+}
+
 func TestBuildInfoCommand(t *testing.T) {
 	t.Parallel()
 	ctx, collection := setup.Setup(t)
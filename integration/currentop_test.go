@@ -299,6 +299,41 @@ func TestCurrentOpExplain(tt *testing.T) {
 	testutil.AssertEqual(t, expected, res)
 }
 
+func TestCurrentOpFilter(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	adminDB := collection.Database().Client().Database("admin")
+
+	t.Run("NoMatch", func(t *testing.T) {
+		t.Parallel()
+
+		var res bson.D
+		err := adminDB.RunCommand(ctx, bson.D{{"currentOp", int32(1)}, {"opid", int32(-1)}}).Decode(&res)
+		require.NoError(t, err)
+
+		AssertEqualDocuments(t, bson.D{{"inprog", bson.A{}}, {"ok", float64(1)}}, res)
+	})
+
+	t.Run("Match", func(t *testing.T) {
+		t.Parallel()
+
+		var res struct {
+			InProg []bson.M `bson:"inprog"`
+		}
+		err := adminDB.RunCommand(ctx, bson.D{
+			{"currentOp", int32(1)},
+			{"$ownOps", true},
+			{"command.comment", "TestCurrentOpFilter"},
+			{"comment", "TestCurrentOpFilter"},
+		}).Decode(&res)
+		require.NoError(t, err)
+
+		require.Len(t, res.InProg, 1)
+		assert.Equal(t, "command", res.InProg[0]["op"])
+	})
+}
+
 // inProgress runs function `f` in `n` go routines while `currentOp` is executed to search an in-progress operation.
 // It repeats until it finds an in-progress operation that matches the search function
 // or reaches the attempts limit.
@@ -23,6 +23,7 @@
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/FerretDB/FerretDB/v2/integration/setup"
+	"github.com/FerretDB/FerretDB/v2/integration/shareddata"
 )
 
 func TestExplainCommandQueryErrors(t *testing.T) {
@@ -159,3 +160,113 @@ func TestExplainLimitInt(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, res)
 }
+
+func TestExplainWriteCommands(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t, shareddata.Int32s)
+
+	for name, tc := range map[string]struct {
+		command bson.D
+	}{
+		"Update": {
+			command: bson.D{
+				{"update", collection.Name()},
+				{"updates", bson.A{bson.D{
+					{"q", bson.D{{"v", int32(42)}}},
+					{"u", bson.D{{"$set", bson.D{{"v", int32(43)}}}}},
+				}}},
+			},
+		},
+		"Delete": {
+			command: bson.D{
+				{"delete", collection.Name()},
+				{"deletes", bson.A{bson.D{
+					{"q", bson.D{{"v", int32(42)}}},
+					{"limit", int32(0)},
+				}}},
+			},
+		},
+		"Distinct": {
+			command: bson.D{
+				{"distinct", collection.Name()},
+				{"key", "v"},
+				{"query", bson.D{{"v", bson.D{{"$gt", int32(0)}}}}},
+			},
+		},
+		"FindAndModify": {
+			command: bson.D{
+				{"findAndModify", collection.Name()},
+				{"query", bson.D{{"v", int32(42)}}},
+				{"remove", true},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, verbosity := range []string{"queryPlanner", "executionStats", "allPlansExecution"} {
+				var res bson.D
+				err := collection.Database().RunCommand(ctx, bson.D{
+					{"explain", tc.command},
+					{"verbosity", verbosity},
+				}).Decode(&res)
+				require.NoError(t, err, verbosity)
+
+				m := res.Map()
+
+				queryPlanner, ok := m["queryPlanner"].(bson.D)
+				require.True(t, ok, verbosity)
+				assert.Equal(t, collection.Database().Name()+"."+collection.Name(), queryPlanner.Map()["namespace"], verbosity)
+				assert.IsType(t, bson.D{}, queryPlanner.Map()["winningPlan"], verbosity)
+
+				if verbosity == "queryPlanner" {
+					assert.NotContains(t, m, "executionStats", verbosity)
+					continue
+				}
+
+				executionStats, ok := m["executionStats"].(bson.D)
+				require.True(t, ok, verbosity)
+				assert.Equal(t, true, executionStats.Map()["executionSuccess"], verbosity)
+			}
+
+			// explain must not modify documents
+			n, err := collection.CountDocuments(ctx, bson.D{{"v", int32(42)}})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), n)
+		})
+	}
+}
+
+func TestExplainVerbosityErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	err := collection.Database().RunCommand(ctx, bson.D{
+		{"explain", bson.D{{"find", collection.Name()}}},
+		{"verbosity", "invalid"},
+	}).Err()
+
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    9,
+		Name:    "FailedToParse",
+		Message: "verbosity string must be one of {'queryPlanner', 'executionStats', 'allPlansExecution'}",
+	}, err)
+
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{"explain", bson.D{
+			{"update", collection.Name()},
+			{"updates", bson.A{
+				bson.D{{"q", bson.D{}}, {"u", bson.D{}}},
+				bson.D{{"q", bson.D{}}, {"u", bson.D{}}},
+			}},
+		}},
+	}).Err()
+
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    16,
+		Name:    "InvalidLength",
+		Message: "explained write batches must be of size 1",
+	}, err)
+}
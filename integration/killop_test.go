@@ -0,0 +1,63 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestKillOp(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	adminDB := collection.Database().Client().Database("admin")
+
+	t.Run("NonExistent", func(t *testing.T) {
+		t.Parallel()
+
+		var res bson.D
+		err := adminDB.RunCommand(ctx, bson.D{{"killOp", int32(1)}, {"op", int32(2147483647)}}).Decode(&res)
+		require.NoError(t, err)
+
+		AssertEqualDocuments(t, bson.D{{"info", "attempting to kill op"}, {"ok", float64(1)}}, res)
+	})
+
+	t.Run("NonAdmin", func(t *testing.T) {
+		t.Parallel()
+
+		err := collection.Database().RunCommand(ctx, bson.D{{"killOp", int32(1)}, {"op", int32(1)}}).Err()
+
+		expected := mongo.CommandError{
+			Code:    13,
+			Name:    "Unauthorized",
+			Message: "killOp may only be run against the admin database.",
+		}
+		AssertMatchesCommandError(t, expected, err)
+	})
+
+	t.Run("MissingOp", func(t *testing.T) {
+		t.Parallel()
+
+		err := adminDB.RunCommand(ctx, bson.D{{"killOp", int32(1)}}).Err()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,60 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestProfileCommand(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	var res bson.D
+	err := db.RunCommand(ctx, bson.D{{"profile", int32(-1)}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"was", int32(0)}, {"slowms", int32(100)}, {"sampleRate", 1.0}, {"ok", 1.0}}, res)
+
+	err = db.RunCommand(ctx, bson.D{{"profile", int32(1)}, {"slowms", int32(20)}, {"sampleRate", 0.5}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"was", int32(0)}, {"slowms", int32(100)}, {"sampleRate", 1.0}, {"ok", 1.0}}, res)
+
+	t.Cleanup(func() {
+		err = db.RunCommand(ctx, bson.D{{"profile", int32(0)}, {"slowms", int32(100)}, {"sampleRate", 1.0}}).Err()
+		require.NoError(t, err)
+	})
+
+	err = db.RunCommand(ctx, bson.D{{"profile", int32(-1)}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"was", int32(1)}, {"slowms", int32(20)}, {"sampleRate", 0.5}, {"ok", 1.0}}, res)
+
+	t.Run("InvalidLevel", func(t *testing.T) {
+		err := db.RunCommand(ctx, bson.D{{"profile", int32(3)}}).Err()
+		AssertMatchesCommandError(t, mongo.CommandError{Code: 2, Name: "BadValue"}, err)
+	})
+
+	t.Run("InvalidSampleRate", func(t *testing.T) {
+		err := db.RunCommand(ctx, bson.D{{"profile", int32(1)}, {"sampleRate", 2.0}}).Err()
+		AssertMatchesCommandError(t, mongo.CommandError{Code: 2, Name: "BadValue"}, err)
+	})
+}
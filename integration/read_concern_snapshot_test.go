@@ -0,0 +1,140 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestReadConcernSnapshot(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	docs := make([]any, 10)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i)}}
+	}
+
+	_, err := collection.InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	t.Run("GetMore", func(t *testing.T) {
+		var res bson.D
+		err := collection.Database().RunCommand(ctx, bson.D{
+			{"find", collection.Name()},
+			{"batchSize", int32(2)},
+			{"readConcern", bson.D{{"level", "snapshot"}}},
+		}).Decode(&res)
+		require.NoError(t, err)
+
+		cursor := res.Map()["cursor"].(bson.D).Map()
+		require.IsType(t, primitive.Timestamp{}, cursor["atClusterTime"])
+		require.Len(t, cursor["firstBatch"], 2)
+
+		cursorID := cursor["id"].(int64)
+		require.NotZero(t, cursorID)
+
+		// changes made after the first page are not visible
+		_, err = collection.InsertOne(ctx, bson.D{{"_id", int32(10)}})
+		require.NoError(t, err)
+
+		_, err = collection.DeleteOne(ctx, bson.D{{"_id", int32(9)}})
+		require.NoError(t, err)
+
+		var ids []any
+
+		for cursorID != 0 {
+			err = collection.Database().RunCommand(ctx, bson.D{
+				{"getMore", cursorID},
+				{"collection", collection.Name()},
+			}).Decode(&res)
+			require.NoError(t, err)
+
+			cursor = res.Map()["cursor"].(bson.D).Map()
+			cursorID = cursor["id"].(int64)
+
+			for _, d := range cursor["nextBatch"].(bson.A) {
+				ids = append(ids, d.(bson.D).Map()["_id"])
+			}
+		}
+
+		assert.Equal(t, []any{int32(2), int32(3), int32(4), int32(5), int32(6), int32(7), int32(8), int32(9)}, ids)
+	})
+
+	t.Run("Session", func(t *testing.T) {
+		sess, err := collection.Database().Client().StartSession(options.Session().SetSnapshot(true))
+		require.NoError(t, err)
+
+		t.Cleanup(func() {
+			sess.EndSession(ctx)
+		})
+
+		err = mongo.WithSession(ctx, sess, func(sctx mongo.SessionContext) error {
+			n1, err := collection.CountDocuments(sctx, bson.D{})
+			if err != nil {
+				return err
+			}
+
+			// the driver sends atClusterTime returned by the first read
+			if _, err = collection.InsertOne(ctx, bson.D{{"_id", "session"}}); err != nil {
+				return err
+			}
+
+			n2, err := collection.CountDocuments(sctx, bson.D{})
+			if err != nil {
+				return err
+			}
+
+			assert.Equal(t, n1, n2)
+
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("AggregateWrites", func(t *testing.T) {
+		err := collection.Database().RunCommand(ctx, bson.D{
+			{"aggregate", collection.Name()},
+			{"pipeline", bson.A{bson.D{{"$out", collection.Name() + "_out"}}}},
+			{"cursor", bson.D{}},
+			{"readConcern", bson.D{{"level", "snapshot"}}},
+		}).Err()
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, int32(72), ce.Code)
+	})
+
+	t.Run("AtClusterTimeWithoutSnapshot", func(t *testing.T) {
+		err := collection.Database().RunCommand(ctx, bson.D{
+			{"find", collection.Name()},
+			{"readConcern", bson.D{{"level", "local"}, {"atClusterTime", primitive.Timestamp{T: 1, I: 1}}}},
+		}).Err()
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, int32(72), ce.Code)
+	})
+}
@@ -22,10 +22,12 @@
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/FerretDB/FerretDB/v2/internal/util/testutil/teststress"
 
 	"github.com/FerretDB/FerretDB/v2/integration/setup"
+	"github.com/FerretDB/FerretDB/v2/integration/shareddata"
 )
 
 func TestRenameCollectionStress(t *testing.T) {
@@ -64,3 +66,114 @@ func TestRenameCollectionStress(t *testing.T) {
 	require.NoError(t, err)
 	require.Contains(t, colls, "rename_collection_stress_renamed")
 }
+
+func TestRenameCollectionCrossDatabase(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t, shareddata.Int32s)
+	db := collection.Database()
+	adminDB := db.Client().Database("admin")
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"v", int32(1)}}})
+	require.NoError(t, err)
+
+	expectedCount, err := collection.CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+
+	targetDB := db.Client().Database(db.Name() + "_target")
+	require.NoError(t, targetDB.Drop(ctx))
+
+	t.Cleanup(func() {
+		require.NoError(t, targetDB.Drop(ctx))
+	})
+
+	from := db.Name() + "." + collection.Name()
+	to := targetDB.Name() + "." + collection.Name()
+
+	err = adminDB.RunCommand(ctx, bson.D{{"renameCollection", from}, {"to", to}}).Err()
+	require.NoError(t, err)
+
+	colls, err := db.ListCollectionNames(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.NotContains(t, colls, collection.Name())
+
+	target := targetDB.Collection(collection.Name())
+
+	count, err := target.CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.Equal(t, expectedCount, count)
+
+	indexes, err := target.Indexes().ListSpecifications(ctx)
+	require.NoError(t, err)
+
+	var names []string
+	for _, index := range indexes {
+		names = append(names, index.Name)
+	}
+
+	assert.ElementsMatch(t, []string{"_id_", "v_1"}, names)
+
+	err = adminDB.RunCommand(ctx, bson.D{{"renameCollection", from}, {"to", to}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    26,
+		Name:    "NamespaceNotFound",
+		Message: "Source collection " + from + " does not exist",
+	}, err)
+
+	require.NoError(t, db.CreateCollection(ctx, collection.Name()))
+
+	err = adminDB.RunCommand(ctx, bson.D{{"renameCollection", from}, {"to", to}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    48,
+		Name:    "NamespaceExists",
+		Message: "target namespace exists",
+	}, err)
+
+	err = adminDB.RunCommand(ctx, bson.D{{"renameCollection", from}, {"to", to}, {"dropTarget", true}}).Err()
+	require.NoError(t, err)
+
+	count, err = target.CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestRenameCollectionCrossDatabaseErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t, shareddata.Int32s)
+	db := collection.Database()
+	adminDB := db.Client().Database("admin")
+
+	targetDB := db.Client().Database(db.Name() + "_target")
+	require.NoError(t, targetDB.Drop(ctx))
+
+	t.Cleanup(func() {
+		require.NoError(t, targetDB.Drop(ctx))
+	})
+
+	view := collection.Name() + "_view"
+	require.NoError(t, db.CreateView(ctx, view, collection.Name(), mongo.Pipeline{}))
+
+	from := db.Name() + "." + view
+	to := targetDB.Name() + "." + view
+
+	err := adminDB.RunCommand(ctx, bson.D{{"renameCollection", from}, {"to", to}}).Err()
+	AssertMatchesCommandError(t, mongo.CommandError{
+		Code: 166,
+		Name: "CommandNotSupportedOnView",
+	}, err)
+
+	from = db.Name() + "." + collection.Name()
+	to = "invalid db." + collection.Name()
+
+	err = adminDB.RunCommand(ctx, bson.D{{"renameCollection", from}, {"to", to}}).Err()
+	AssertMatchesCommandError(t, mongo.CommandError{
+		Code: 73,
+		Name: "InvalidNamespace",
+	}, err)
+
+	colls, err := db.ListCollectionNames(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.Contains(t, colls, collection.Name())
+	assert.Contains(t, colls, view)
+}
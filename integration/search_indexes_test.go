@@ -0,0 +1,115 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestSearchIndexes(t *testing.T) {
+	setup.SkipForMongoDB(t, "Atlas Search indexes are not available in MongoDB Community Server")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"title", "Dune"}, {"v", bson.A{0.1, 0.2, 0.3}}},
+		bson.D{{"_id", int32(2)}, {"title", "Foundation"}, {"v", bson.A{0.3, 0.2, 0.1}}},
+	})
+	require.NoError(t, err)
+
+	siv := collection.SearchIndexes()
+
+	names, err := siv.CreateMany(ctx, []mongo.SearchIndexModel{{
+		Definition: bson.D{{"mappings", bson.D{
+			{"dynamic", false},
+			{"fields", bson.D{{"title", bson.D{{"type", "string"}}}}},
+		}}},
+		Options: options.SearchIndexes().SetName("text"),
+	}, {
+		Definition: bson.D{{"fields", bson.A{bson.D{
+			{"type", "vector"},
+			{"path", "v"},
+			{"numDimensions", int32(3)},
+			{"similarity", "cosine"},
+		}}}},
+		Options: options.SearchIndexes().SetName("vector").SetType("vectorSearch"),
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"text", "vector"}, names)
+
+	list := func(t *testing.T, name string) []bson.D {
+		t.Helper()
+
+		opts := options.SearchIndexes()
+		if name != "" {
+			opts.SetName(name)
+		}
+
+		cursor, err := siv.List(ctx, opts)
+		require.NoError(t, err)
+
+		var res []bson.D
+		require.NoError(t, cursor.All(ctx, &res))
+
+		return res
+	}
+
+	indexes := list(t, "")
+	require.Len(t, indexes, 2)
+
+	for _, index := range indexes {
+		m := index.Map()
+		assert.NotEmpty(t, m["id"])
+		assert.Equal(t, "READY", m["status"])
+		assert.Equal(t, true, m["queryable"])
+	}
+
+	vector := list(t, "vector")
+	require.Len(t, vector, 1)
+	assert.Equal(t, "vectorSearch", vector[0].Map()["type"])
+
+	err = siv.UpdateOne(ctx, "vector", bson.D{{"fields", bson.A{bson.D{
+		{"type", "vector"},
+		{"path", "v"},
+		{"numDimensions", int32(3)},
+		{"similarity", "euclidean"},
+	}}}})
+	require.NoError(t, err)
+
+	vector = list(t, "vector")
+	require.Len(t, vector, 1)
+
+	fields := vector[0].Map()["latestDefinition"].(bson.D).Map()["fields"].(bson.A)
+	assert.Equal(t, "euclidean", fields[0].(bson.D).Map()["similarity"])
+
+	require.NoError(t, siv.DropOne(ctx, "text"))
+	assert.Len(t, list(t, ""), 1)
+
+	err = siv.DropOne(ctx, "text")
+
+	var ce mongo.CommandError
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, int32(27), ce.Code)
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestSearchStages(t *testing.T) {
+	setup.SkipForMongoDB(t, "Atlas Search is not available in MongoDB Community Server")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"summary", "A whale hunt at sea"}, {"v", bson.A{1.0, 0.0, 0.0}}},
+		bson.D{{"_id", int32(2)}, {"summary", "A journey to the stars"}, {"v", bson.A{0.0, 1.0, 0.0}}},
+		bson.D{{"_id", int32(3)}, {"summary", "Whales and sailors"}, {"v", bson.A{0.9, 0.1, 0.0}}},
+	})
+	require.NoError(t, err)
+
+	_, err = collection.SearchIndexes().CreateMany(ctx, []mongo.SearchIndexModel{{
+		Definition: bson.D{{"mappings", bson.D{
+			{"dynamic", false},
+			{"fields", bson.D{{"summary", bson.D{{"type", "string"}}}}},
+		}}},
+		Options: options.SearchIndexes().SetName("text"),
+	}, {
+		Definition: bson.D{{"fields", bson.A{bson.D{
+			{"type", "vector"},
+			{"path", "v"},
+			{"numDimensions", int32(3)},
+			{"similarity", "cosine"},
+		}}}},
+		Options: options.SearchIndexes().SetName("vector").SetType("vectorSearch"),
+	}})
+	require.NoError(t, err)
+
+	aggregate := func(t *testing.T, pipeline bson.A) []bson.D {
+		t.Helper()
+
+		cursor, err := collection.Aggregate(ctx, pipeline)
+		require.NoError(t, err)
+
+		var res []bson.D
+		require.NoError(t, cursor.All(ctx, &res))
+
+		return res
+	}
+
+	t.Run("VectorSearch", func(t *testing.T) {
+		t.Parallel()
+
+		res := aggregate(t, bson.A{
+			bson.D{{"$vectorSearch", bson.D{
+				{"index", "vector"},
+				{"path", "v"},
+				{"queryVector", bson.A{1.0, 0.0, 0.0}},
+				{"numCandidates", int32(10)},
+				{"limit", int32(2)},
+			}}},
+			bson.D{{"$project", bson.D{{"score", bson.D{{"$meta", "vectorSearchScore"}}}}}},
+		})
+		require.Len(t, res, 2)
+
+		assert.Equal(t, int32(1), res[0].Map()["_id"])
+		assert.InDelta(t, 1.0, res[0].Map()["score"], 0.0001)
+		assert.Equal(t, int32(3), res[1].Map()["_id"])
+	})
+
+	t.Run("VectorSearchNoIndex", func(t *testing.T) {
+		t.Parallel()
+
+		res := aggregate(t, bson.A{
+			bson.D{{"$vectorSearch", bson.D{
+				{"index", "none"},
+				{"path", "v"},
+				{"queryVector", bson.A{1.0, 0.0, 0.0}},
+				{"numCandidates", int32(10)},
+				{"limit", int32(2)},
+			}}},
+		})
+		assert.Empty(t, res)
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		t.Parallel()
+
+		res := aggregate(t, bson.A{
+			bson.D{{"$search", bson.D{
+				{"index", "text"},
+				{"text", bson.D{{"query", "whale"}, {"path", "summary"}}},
+			}}},
+			bson.D{{"$project", bson.D{{"score", bson.D{{"$meta", "searchScore"}}}}}},
+		})
+		require.Len(t, res, 2)
+
+		for _, doc := range res {
+			assert.Contains(t, []any{int32(1), int32(3)}, doc.Map()["_id"])
+			assert.Greater(t, doc.Map()["score"], 0.0)
+		}
+	})
+}
@@ -117,8 +117,9 @@ func setupListener(tb testing.TB, ctx context.Context, opts *ListenerOpts, logge
 		Auth: true,
 
 		// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/566
-		TCPHost:     "",
+		Host:        "",
 		ReplSetName: "",
+		Topology:    nil,
 
 		L:             logging.WithName(logger, "handler"),
 		ConnMetrics:   listenerMetrics.ConnMetrics,
@@ -147,7 +148,7 @@ func setupListener(tb testing.TB, ctx context.Context, opts *ListenerOpts, logge
 	case *targetUnixSocketF:
 		listenerOpts.Unix = unixSocketPath(tb)
 	default:
-		listenerOpts.TCP = "127.0.0.1:0"
+		listenerOpts.TCP = []string{"127.0.0.1:0"}
 	}
 
 	l, err := clientconn.Listen(&listenerOpts)
@@ -0,0 +1,74 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestTop(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	adminDB := collection.Database().Client().Database("admin")
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "top"}})
+	require.NoError(t, err)
+
+	err = collection.FindOne(ctx, bson.D{{"_id", "top"}}).Err()
+	require.NoError(t, err)
+
+	var res bson.D
+	err = adminDB.RunCommand(ctx, bson.D{{"top", int32(1)}}).Decode(&res)
+	require.NoError(t, err)
+
+	totals, ok := res.Map()["totals"].(bson.D)
+	require.True(t, ok)
+
+	ns := collection.Database().Name() + "." + collection.Name()
+
+	stats, ok := totals.Map()[ns].(bson.D)
+	require.True(t, ok, "%s is not found", ns)
+
+	m := stats.Map()
+
+	for _, k := range []string{"total", "readLock", "writeLock", "queries", "insert"} {
+		stat, ok := m[k].(bson.D)
+		require.True(t, ok, k)
+
+		assert.GreaterOrEqual(t, stat.Map()["count"], int64(1), k)
+		assert.IsType(t, int64(0), stat.Map()["time"], k)
+	}
+
+	t.Run("NonAdmin", func(t *testing.T) {
+		t.Parallel()
+
+		err := collection.Database().RunCommand(ctx, bson.D{{"top", int32(1)}}).Err()
+
+		expected := mongo.CommandError{
+			Code:    13,
+			Name:    "Unauthorized",
+			Message: "top may only be run against the admin database.",
+		}
+		AssertMatchesCommandError(t, expected, err)
+	})
+}
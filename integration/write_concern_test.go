@@ -0,0 +1,103 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/v2/integration/setup"
+)
+
+func TestWriteConcern(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	for name, tc := range map[string]struct {
+		writeConcern bson.D
+
+		code int32 // expected writeConcernError code, 0 if none
+	}{
+		"W1": {
+			writeConcern: bson.D{{"w", int32(1)}},
+		},
+		"Majority": {
+			writeConcern: bson.D{{"w", "majority"}, {"wtimeout", int32(5000)}},
+		},
+		"Journal": {
+			writeConcern: bson.D{{"w", int32(1)}, {"j", true}},
+		},
+		"NotEnoughNodes": {
+			writeConcern: bson.D{{"w", int32(50)}, {"wtimeout", int32(100)}},
+			code:         100,
+		},
+		"UnknownMode": {
+			writeConcern: bson.D{{"w", "unknown"}},
+			code:         79,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var res bson.D
+			err := collection.Database().RunCommand(ctx, bson.D{
+				{"insert", collection.Name()},
+				{"documents", bson.A{bson.D{{"_id", name}}}},
+				{"writeConcern", tc.writeConcern},
+			}).Decode(&res)
+			require.NoError(t, err)
+
+			m := res.Map()
+			assert.Equal(t, int32(1), m["n"])
+
+			if tc.code == 0 {
+				assert.NotContains(t, m, "writeConcernError")
+				return
+			}
+
+			wcErr, ok := m["writeConcernError"].(bson.D)
+			require.True(t, ok, "%v", res)
+			assert.Equal(t, tc.code, wcErr.Map()["code"])
+
+			// the write itself is not rolled back
+			n, err := collection.CountDocuments(ctx, bson.D{{"_id", name}})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), n)
+		})
+	}
+}
+
+func TestWriteConcernErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	err := collection.Database().RunCommand(ctx, bson.D{
+		{"insert", collection.Name()},
+		{"documents", bson.A{bson.D{}}},
+		{"writeConcern", bson.D{{"w", int32(-1)}}},
+	}).Err()
+
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    9,
+		Name:    "FailedToParse",
+		Message: "w has to be a non-negative number and not greater than 50; found: -1",
+	}, err)
+}
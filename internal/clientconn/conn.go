@@ -30,6 +30,7 @@
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -43,8 +44,12 @@
 
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/diffreport"
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/router"
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/shadow"
 	"github.com/FerretDB/FerretDB/v2/internal/handler"
 	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/operation"
 	"github.com/FerretDB/FerretDB/v2/internal/handler/proxy"
 	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
@@ -67,14 +72,30 @@
 	// DiffProxyMode both handles requests and proxies them, then logs the diff.
 	// Only the proxy response is sent to the client.
 	DiffProxyMode Mode = "diff-proxy"
+	// RoutingMode handles requests or proxies them, depending on their namespace.
+	// See [router.Router].
+	RoutingMode Mode = "routing"
+	// ShadowMode only handles requests and mirrors them to another wire protocol compatible service
+	// asynchronously, without waiting for responses.
+	ShadowMode Mode = "shadow"
 )
 
+// errDrained is returned by [conn.run] when the connection was closed
+// after the listener started draining.
+var errDrained = errors.New("connection drained")
+
+// errIdle is returned by [conn.run] when the client did not send the next request
+// within the idle timeout.
+var errIdle = errors.New("connection idle timeout")
+
 // AllModes includes all operation modes, with the first one being the default.
 var AllModes = []string{
 	string(NormalMode),
 	string(ProxyMode),
 	string(DiffNormalMode),
 	string(DiffProxyMode),
+	string(RoutingMode),
+	string(ShadowMode),
 }
 
 // conn represents client connection.
@@ -85,8 +106,18 @@ type conn struct {
 	h              *handler.Handler
 	m              *connmetrics.ConnMetrics
 	proxy          *proxy.Handler
+	proxyConn      *proxy.Conn // pinned upstream connection; nil in normal mode
 	lastRequestID  atomic.Int32
-	testRecordsDir string // if empty, no records are created
+	testRecordsDir string        // if empty, no records are created
+	recordTimes    []time.Time   // times when recorded requests were read
+	idleTimeout    time.Duration // zero value disables idle timeout
+	diffReporter   *diffreport.Reporter
+	shadow         *shadow.Mirror
+	router         *router.Router
+
+	drainM   sync.Mutex
+	draining bool // protected by drainM
+	idle     bool // waiting for the next message header; protected by drainM
 }
 
 // newConnOpts represents newConn options.
@@ -96,17 +127,18 @@ type newConnOpts struct {
 	l           *slog.Logger
 	handler     *handler.Handler
 	connMetrics *connmetrics.ConnMetrics
+	proxy       *proxy.Handler // shared between all conns; nil in normal mode
 
-	proxyAddr        string
-	proxyTLSCertFile string
-	proxyTLSKeyFile  string
-	proxyTLSCAFile   string
+	diffReporter *diffreport.Reporter // shared between all conns; may be nil
+	shadow       *shadow.Mirror       // shared between all conns; nil unless in shadow mode
+	router       *router.Router       // shared between all conns; nil unless in routing mode
 
-	testRecordsDir string // if empty, no records are created
+	testRecordsDir string        // if empty, no records are created
+	idleTimeout    time.Duration // zero value disables idle timeout
 }
 
 // newConn creates a new client connection for given net.Conn.
-func newConn(opts *newConnOpts) (*conn, error) {
+func newConn(opts *newConnOpts) *conn {
 	if opts.mode == "" {
 		panic("mode required")
 	}
@@ -114,12 +146,16 @@ func newConn(opts *newConnOpts) (*conn, error) {
 		panic("handler required")
 	}
 
-	var p *proxy.Handler
-	if opts.mode != NormalMode {
-		var err error
-		if p, err = proxy.New(opts.proxyAddr, opts.proxyTLSCertFile, opts.proxyTLSKeyFile, opts.proxyTLSCAFile); err != nil {
-			return nil, lazyerrors.Error(err)
-		}
+	if opts.mode != NormalMode && opts.proxy == nil {
+		panic("proxy required")
+	}
+
+	if opts.mode == ShadowMode && opts.shadow == nil {
+		panic("shadow required")
+	}
+
+	if opts.mode == RoutingMode && opts.router == nil {
+		panic("router required")
 	}
 
 	return &conn{
@@ -128,18 +164,29 @@ func newConn(opts *newConnOpts) (*conn, error) {
 		l:              opts.l,
 		h:              opts.handler,
 		m:              opts.connMetrics,
-		proxy:          p,
+		proxy:          opts.proxy,
 		testRecordsDir: opts.testRecordsDir,
-	}, nil
+		idleTimeout:    opts.idleTimeout,
+		diffReporter:   opts.diffReporter,
+		shadow:         opts.shadow,
+		router:         opts.router,
+	}
 }
 
 // run runs the client connection until ctx is canceled, client disconnects,
 // or fatal error or panic is encountered.
 //
+// When drain is closed, the connection stops reading new requests,
+// but the in-flight request (if any) is finished and its response is written
+// until ctx is canceled. [errDrained] is returned in that case.
+//
+// If idle timeout is set and the client does not send the next request in time,
+// [errIdle] is returned.
+//
 // Returned error is always non-nil.
 //
 // The caller is responsible for closing the underlying net.Conn.
-func (c *conn) run(ctx context.Context) (err error) {
+func (c *conn) run(ctx context.Context, drain <-chan struct{}) (err error) {
 	ctx, cancel := context.WithCancelCause(ctx)
 	defer func() {
 		cancel(lazyerrors.Errorf("run exits: %w", err))
@@ -149,6 +196,12 @@ func (c *conn) run(ctx context.Context) (err error) {
 
 	defer connInfo.Close()
 
+	if c.proxy != nil {
+		c.proxyConn = c.proxy.Conn(c.l)
+
+		defer c.proxyConn.Close()
+	}
+
 	if c.netConn.RemoteAddr().Network() != "unix" {
 		connInfo.Peer, err = netip.ParseAddrPort(c.netConn.RemoteAddr().String())
 		if err != nil {
@@ -158,17 +211,30 @@ func (c *conn) run(ctx context.Context) (err error) {
 
 	ctx = conninfo.Ctx(ctx, connInfo)
 
-	// That's not the best – it makes proxy handler very different from the main handler.
-	// Instead, proxy handler should map connections based on connInfo.
-	// TODO https://github.com/FerretDB/FerretDB/issues/4965
-	if c.proxy != nil {
-		go c.proxy.Run(ctx)
-	}
-
 	done := make(chan struct{})
 
-	// handle ctx cancellation
+	// handle draining and ctx cancellation
 	go func() {
+		select {
+		case <-done:
+			return
+		case <-drain:
+			c.drainM.Lock()
+
+			c.draining = true
+
+			// unblocks idle read below; partially read requests are not interrupted,
+			// and writes are not affected, so the in-flight response is still sent
+			if c.idle {
+				if e := c.netConn.SetReadDeadline(time.Unix(0, 0)); e != nil {
+					c.l.WarnContext(ctx, fmt.Sprintf("Failed to set read deadline: %s", e))
+				}
+			}
+
+			c.drainM.Unlock()
+		case <-ctx.Done():
+		}
+
 		select {
 		case <-done:
 			// nothing, let goroutine exit
@@ -227,12 +293,83 @@ func (c *conn) run(ctx context.Context) (err error) {
 	}()
 
 	for {
+		if err = c.waitMessage(ctx, bufr); err != nil {
+			return
+		}
+
 		if err = c.processMessage(ctx, bufr, bufw); err != nil {
+			if errors.Is(err, os.ErrDeadlineExceeded) && c.idleTimeout > 0 && ctx.Err() == nil {
+				err = errIdle
+			}
+
 			return
 		}
 	}
 }
 
+// waitMessage waits for the first byte of the next message header
+// while marking the connection as idle, so draining could interrupt that wait,
+// but not reading of the rest of the message.
+//
+// It returns [errDrained] if draining started before the message arrived,
+// and [errIdle] if the idle timeout passed.
+func (c *conn) waitMessage(ctx context.Context, bufr *bufio.Reader) error {
+	c.drainM.Lock()
+
+	if c.draining {
+		c.drainM.Unlock()
+		return errDrained
+	}
+
+	var deadline time.Time
+	if c.idleTimeout > 0 {
+		deadline = time.Now().Add(c.idleTimeout)
+	}
+
+	if err := c.netConn.SetReadDeadline(deadline); err != nil {
+		c.drainM.Unlock()
+		return err
+	}
+
+	// the deadline set on ctx cancellation could be overridden above
+	if err := ctx.Err(); err != nil {
+		c.drainM.Unlock()
+		return context.Cause(ctx)
+	}
+
+	c.idle = true
+
+	c.drainM.Unlock()
+
+	_, err := bufr.Peek(1)
+
+	c.drainM.Lock()
+	defer c.drainM.Unlock()
+
+	c.idle = false
+
+	if err != nil {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			switch {
+			case c.draining:
+				return errDrained
+			case c.idleTimeout > 0 && ctx.Err() == nil:
+				return errIdle
+			}
+		}
+
+		return err
+	}
+
+	// the message started to arrive; reset the deadline that could be set by draining,
+	// so the message is read completely and handled
+	if c.draining {
+		return c.netConn.SetReadDeadline(deadline)
+	}
+
+	return nil
+}
+
 // processMessage reads the request, routes the request based on the operation mode
 // and writes the response.
 //
@@ -243,49 +380,99 @@ func (c *conn) processMessage(ctx context.Context, bufr *bufio.Reader, bufw *buf
 		return err
 	}
 
+	if c.testRecordsDir != "" {
+		c.recordTimes = append(c.recordTimes, time.Now())
+	}
+
 	if c.l.Enabled(ctx, slog.LevelDebug) {
 		c.l.DebugContext(ctx, "Request header: "+reqHeader.String())
 		c.l.DebugContext(ctx, "Request message:\n"+reqBody.StringIndent())
 	}
 
+	diffMode := c.mode == DiffNormalMode || c.mode == DiffProxyMode
+
+	// proxied requests are sent to proxy, handled requests are handled by FerretDB;
+	// proxyResponse selects the response sent to the client
+	proxied := c.mode == ProxyMode || diffMode
+	handled := c.mode != ProxyMode
+	proxyResponse := c.mode == ProxyMode || c.mode == DiffProxyMode
+
+	// set for routed requests of unauthenticated clients
+	var unauthorized bool
+
+	if c.mode == RoutingMode && c.router.Route(reqBody) == router.TargetProxy {
+		proxied, handled, proxyResponse = true, false, true
+
+		// the proxy does not authenticate routed requests, so FerretDB does
+		if c.h.Auth && !conninfo.Get(ctx).Conv().Succeed() {
+			proxied, unauthorized = false, true
+		}
+	}
+
+	// decode request before it is handled, see below
+	var reqDoc *wirebson.Document
+	if diffMode && c.diffReporter != nil {
+		reqDoc = bodyDocument(reqBody)
+	}
+
 	// diffLogLevel provides the level of logging for the diff between the "normal" and "proxy" responses.
 	// It is set to the highest level of logging used to log response.
 	diffLogLevel := slog.LevelDebug
 
-	// send request to proxy first (unless we are in normal mode)
+	// send request to proxy first (if needed)
 	// because FerretDB's handling could modify reqBody's documents,
 	// creating a data race
 	var proxyHeader *wire.MsgHeader
 	var proxyBody wire.MsgBody
+	var proxyClosed bool
+
+	if c.mode == ShadowMode {
+		// for the same reason, queue the request copy first
+		c.shadow.Mirror(ctx, reqHeader, reqBody)
+	}
+
+	// like the proxy, do not reply to requests with moreToCome flag
+	if msg, ok := reqBody.(*wire.OpMsg); unauthorized && !(ok && msg.Flags.FlagSet(wire.OpMsgMoreToCome)) {
+		protoErr := mongoerrors.New(mongoerrors.ErrUnauthorized, "Command requires authentication")
+		proxyHeader, proxyBody = c.errorResponse(reqHeader, protoErr)
+	}
 
-	if c.mode != NormalMode {
+	if proxied {
 		if c.proxy == nil {
 			panic("proxy addr was nil")
 		}
 
-		// TODO https://github.com/FerretDB/FerretDB/issues/1997
 		var resp *middleware.Response
-		resp, err = c.proxy.Handle(ctx, middleware.RequestWire(reqHeader, reqBody))
-		must.NoError(err)
-
-		proxyHeader = resp.WireHeader()
+		if resp, err = c.proxyConn.Handle(ctx, middleware.RequestWire(reqHeader, reqBody)); err != nil {
+			c.l.WarnContext(ctx, "Proxy request failed", logging.Error(err))
+			proxyHeader, proxyBody = c.proxyError(reqHeader, err)
+
+			// the client can't continue without the authentication state of the upstream connection
+			proxyClosed = c.proxyConn.Closed()
+		} else if resp != nil {
+			proxyHeader = resp.WireHeader()
+
+			switch {
+			case resp.OpMsg != nil:
+				proxyBody = resp.OpMsg
+			case resp.OpReply != nil:
+				proxyBody = resp.OpReply
+			default:
+				panic("response body is nil")
+			}
+		}
 
-		switch {
-		case resp.OpMsg != nil:
-			proxyBody = resp.OpMsg
-		case resp.OpReply != nil:
-			proxyBody = resp.OpReply
-		default:
-			panic("response body is nil")
+		if c.mode == RoutingMode {
+			c.router.Track(reqBody, proxyBody)
 		}
 	}
 
-	// handle request unless we are in proxy mode
+	// handle request unless it was only proxied
 	var resCloseConn bool
 	var resHeader *wire.MsgHeader
 	var resBody wire.MsgBody
 
-	if c.mode != ProxyMode {
+	if handled {
 		resHeader, resBody, resCloseConn = c.route(ctx, reqHeader, reqBody)
 		if level := c.logResponse(ctx, "Response", resHeader, resBody, resCloseConn); level > diffLogLevel {
 			diffLogLevel = level
@@ -293,25 +480,34 @@ func (c *conn) processMessage(ctx context.Context, bufr *bufio.Reader, bufw *buf
 	}
 
 	// log proxy response after the normal response to make it less confusing
-	if c.mode != NormalMode {
+	if proxied && proxyHeader != nil {
 		if level := c.logResponse(ctx, "Proxy response", proxyHeader, proxyBody, false); level > diffLogLevel {
 			diffLogLevel = level
 		}
 	}
 
 	// diff in diff mode
-	if c.l.Enabled(ctx, diffLogLevel) && (c.mode == DiffNormalMode || c.mode == DiffProxyMode) {
+	if c.l.Enabled(ctx, diffLogLevel) && diffMode && proxyHeader != nil {
 		if err = c.logDiff(ctx, resHeader, proxyHeader, resBody, proxyBody, diffLogLevel); err != nil {
 			return err
 		}
 	}
 
-	// replace response with one from proxy in proxy and diff-proxy modes
-	if c.mode == ProxyMode || c.mode == DiffProxyMode {
+	if diffMode && c.diffReporter != nil && proxyHeader != nil {
+		c.diffReporter.Report(ctx, reqDoc, bodyDocument(resBody), bodyDocument(proxyBody))
+	}
+
+	// replace response with one from proxy in proxy and diff-proxy modes, and for routed requests
+	if proxyResponse {
 		resHeader = proxyHeader
 		resBody = proxyBody
 	}
 
+	// the proxy does not reply to requests with moreToCome flag
+	if proxyResponse && resHeader == nil {
+		return nil
+	}
+
 	if resHeader == nil || resBody == nil {
 		panic("no response to send to client")
 	}
@@ -328,6 +524,14 @@ func (c *conn) processMessage(ctx context.Context, bufr *bufio.Reader, bufw *buf
 		return err
 	}
 
+	if proxyResponse && proxyClosed {
+		err = errors.New("upstream connection closed")
+
+		c.l.DebugContext(ctx, "Connection closed after upstream failure", logging.Error(err))
+
+		return err
+	}
+
 	if resCloseConn {
 		err = errors.New("fatal error")
 
@@ -339,6 +543,58 @@ func (c *conn) processMessage(ctx context.Context, bufr *bufio.Reader, bufw *buf
 	return nil
 }
 
+// bodyDocument returns the fully decoded document of the given request or response body.
+// It returns nil if body is nil or could not be decoded.
+func bodyDocument(body wire.MsgBody) *wirebson.Document {
+	var doc *wirebson.Document
+	var err error
+
+	switch body := body.(type) {
+	case *wire.OpMsg:
+		doc, err = body.DocumentDeep()
+	case *wire.OpQuery:
+		doc, err = body.QueryDeep()
+	case *wire.OpReply:
+		doc, err = body.DocumentDeep()
+	}
+
+	if err != nil {
+		return nil
+	}
+
+	return doc
+}
+
+// proxyError returns a network error response for the failed proxy request.
+func (c *conn) proxyError(reqHeader *wire.MsgHeader, err error) (*wire.MsgHeader, wire.MsgBody) {
+	protoErr := mongoerrors.New(mongoerrors.ErrHostUnreachable, "Error connecting to upstream: "+err.Error())
+
+	return c.errorResponse(reqHeader, protoErr)
+}
+
+// errorResponse returns an error response for the request that was not handled or proxied.
+func (c *conn) errorResponse(reqHeader *wire.MsgHeader, protoErr *mongoerrors.Error) (*wire.MsgHeader, wire.MsgBody) {
+	resHeader := &wire.MsgHeader{
+		RequestID:  c.lastRequestID.Add(1),
+		ResponseTo: reqHeader.RequestID,
+	}
+
+	var resBody wire.MsgBody
+
+	if reqHeader.OpCode == wire.OpCodeQuery {
+		resHeader.OpCode = wire.OpCodeReply
+		resBody = protoErr.Reply()
+	} else {
+		resHeader.OpCode = wire.OpCodeMsg
+		resBody = protoErr.Msg()
+	}
+
+	b := must.NotFail(resBody.MarshalBinary())
+	resHeader.MessageLength = int32(wire.MsgHeaderLen + len(b))
+
+	return resHeader, resBody
+}
+
 // route sends request to a handler's command based on the op code provided in the request header.
 //
 // The passed context is canceled when the client disconnects.
@@ -346,10 +602,18 @@ func (c *conn) processMessage(ctx context.Context, bufr *bufio.Reader, bufw *buf
 // Handlers to which it routes, should not panic on bad input, but may do so in "impossible" cases.
 // They also should not use recover(). That allows us to use fuzzing.
 //
+// Requests that take longer than the handler's slow operation threshold are logged.
+//
 // Returned resBody can be nil.
 func (c *conn) route(connCtx context.Context, reqHeader *wire.MsgHeader, reqBody wire.MsgBody) (resHeader *wire.MsgHeader, resBody wire.MsgBody, closeConn bool) { //nolint:lll // argument list is too long
+	start := time.Now()
+
 	var span oteltrace.Span
 
+	// set for requests that reached the handler
+	var db string
+	var cmdDoc *wirebson.Document
+
 	var command, result, argument string
 	defer func() {
 		if result == "" {
@@ -405,10 +669,18 @@ func (c *conn) route(connCtx context.Context, reqHeader *wire.MsgHeader, reqBody
 		connCtx, span = otel.Tracer("").Start(connCtx, "")
 
 		if err == nil {
+			db, _ = doc.Get("$db").(string)
+			cmdDoc = doc
+
+			var op *operation.Operation
+			connCtx, op = c.h.Operations().Start(connCtx, db, doc)
+
 			var res *middleware.Response
 			if res, err = c.h.Handle(connCtx, middleware.RequestWire(reqHeader, msg)); res != nil {
 				resBody = res.OpMsg
 			}
+
+			err = finishOperation(op, err)
 		}
 
 	case wire.OpCodeQuery:
@@ -424,10 +696,18 @@ func (c *conn) route(connCtx context.Context, reqHeader *wire.MsgHeader, reqBody
 		connCtx, span = otel.Tracer("").Start(connCtx, "")
 
 		if err == nil {
+			db, _, _ = strings.Cut(query.FullCollectionName, ".")
+			cmdDoc = q
+
+			var op *operation.Operation
+			connCtx, op = c.h.Operations().Start(connCtx, db, q)
+
 			var res *middleware.Response
 			if res, err = c.h.Handle(connCtx, middleware.RequestWire(reqHeader, query)); res != nil {
 				resBody = res.OpReply
 			}
+
+			err = finishOperation(op, err)
 		}
 
 	case wire.OpCodeReply:
@@ -532,13 +812,33 @@ func (c *conn) route(connCtx context.Context, reqHeader *wire.MsgHeader, reqBody
 		result = "ok"
 	}
 
+	if t, d := c.h.SlowOpThreshold(), time.Since(start); t > 0 && d > t && cmdDoc != nil {
+		// plan summary requires additional queries, so the response should not wait for them
+		go c.logSlowOp(context.WithoutCancel(connCtx), db, cmdDoc, d, int(resHeader.MessageLength), result)
+	}
+
 	return
 }
 
+// finishOperation unregisters the operation and returns the error that should be sent to the client.
+// Errors of killed operations are replaced with [mongoerrors.ErrInterrupted].
+func finishOperation(op *operation.Operation, err error) error {
+	op.Finish()
+
+	if err != nil && op.Killed() {
+		return mongoerrors.New(mongoerrors.ErrInterrupted, "operation was interrupted")
+	}
+
+	return err
+}
+
 // renamePartialFile takes over an open file `f` and closes it.
 // It uses the given error to check if the connection was closed by the client,
 // if so the given file is renamed to a name generated by hash,
 // otherwise, it deletes the given file.
+//
+// Times when requests were read are written to the file with the same name and `.timing` extension,
+// one Unix time in nanoseconds per line.
 func (c *conn) renamePartialFile(ctx context.Context, f *os.File, h hash.Hash, err error) {
 	// do not store partial files
 	if !errors.Is(err, wire.ErrZeroRead) {
@@ -568,6 +868,15 @@ func (c *conn) renamePartialFile(ctx context.Context, f *os.File, h hash.Hash, e
 	if e := os.Rename(f.Name(), path); e != nil {
 		c.l.WarnContext(ctx, "Failed to rename file", logging.Error(e))
 	}
+
+	var timing strings.Builder
+	for _, t := range c.recordTimes {
+		fmt.Fprintln(&timing, t.UnixNano())
+	}
+
+	if e := os.WriteFile(filepath.Join(hashPath, fileName+".timing"), []byte(timing.String()), 0o666); e != nil {
+		c.l.WarnContext(ctx, "Failed to write timing file", logging.Error(e))
+	}
 }
 
 // logResponse logs response's header and body and returns the log level that was used.
@@ -15,15 +15,25 @@
 package clientconn
 
 import (
+	"bufio"
+	"bytes"
 	"crypto/sha256"
 	"errors"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/FerretDB/wire"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/router"
+	"github.com/FerretDB/FerretDB/v2/internal/handler"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/proxy"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
 )
 
@@ -64,5 +74,303 @@ func TestRenameFile(t *testing.T) {
 		files, err := filepath.Glob(filepath.Join(dir, "*", "*.bin"))
 		require.NoError(t, err)
 		require.Len(t, files, 1)
+
+		_, err = os.Stat(strings.TrimSuffix(files[0], ".bin") + ".timing")
+		require.NoError(t, err)
+	})
+}
+
+// tcpConn returns server side of a new TCP connection.
+func tcpConn(t *testing.T) net.Conn {
+	t.Helper()
+
+	server, _ := tcpConns(t)
+
+	return server
+}
+
+// tcpConns returns server and client sides of a new TCP connection.
+func tcpConns(t *testing.T) (net.Conn, net.Conn) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer lis.Close()
+
+	client, err := net.Dial("tcp", lis.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	server, err := lis.Accept()
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Close() })
+
+	return server, client
+}
+
+// pingMsg returns header and body of `ping` request.
+func pingMsg(t *testing.T, requestID int32) (*wire.MsgHeader, *wire.OpMsg) {
+	t.Helper()
+
+	msg := wire.MustOpMsg("ping", int32(1), "$db", "admin")
+
+	b, err := msg.MarshalBinary()
+	require.NoError(t, err)
+
+	header := &wire.MsgHeader{
+		MessageLength: int32(wire.MsgHeaderLen + len(b)),
+		RequestID:     requestID,
+		OpCode:        wire.OpCodeMsg,
+	}
+
+	return header, msg
+}
+
+// slowUpstream starts a fake upstream that replies to each request
+// after receiving a value from release; received gets each request.
+func slowUpstream(t *testing.T, received chan<- struct{}, release <-chan struct{}) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { lis.Close() })
+
+	go func() {
+		for {
+			c, err := lis.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer c.Close()
+
+				bufr := bufio.NewReader(c)
+				bufw := bufio.NewWriter(c)
+
+				for {
+					header, _, err := wire.ReadMessage(bufr)
+					if err != nil {
+						return
+					}
+
+					received <- struct{}{}
+					<-release
+
+					resHeader, resBody := pingMsg(t, header.RequestID+1)
+					resHeader.ResponseTo = header.RequestID
+
+					if err = wire.WriteMessage(bufw, resHeader, resBody); err != nil {
+						return
+					}
+
+					if err = bufw.Flush(); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	return lis.Addr().String()
+}
+
+func TestRunIdle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("IdleTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		c := &conn{
+			netConn:     tcpConn(t),
+			l:           testutil.Logger(t),
+			idleTimeout: 50 * time.Millisecond,
+		}
+
+		err := c.run(testutil.Ctx(t), nil)
+		require.ErrorIs(t, err, errIdle)
+	})
+
+	t.Run("Drain", func(t *testing.T) {
+		t.Parallel()
+
+		c := &conn{
+			netConn:     tcpConn(t),
+			l:           testutil.Logger(t),
+			idleTimeout: time.Hour,
+		}
+
+		drain := make(chan struct{})
+		close(drain)
+
+		err := c.run(testutil.Ctx(t), drain)
+		require.ErrorIs(t, err, errDrained)
+	})
+	t.Run("DrainInFlight", func(t *testing.T) {
+		t.Parallel()
+
+		received := make(chan struct{}, 1)
+		release := make(chan struct{})
+
+		p, err := proxy.New(&proxy.NewOpts{
+			Addr: slowUpstream(t, received, release),
+			L:    testutil.Logger(t),
+		})
+		require.NoError(t, err)
+
+		server, client := tcpConns(t)
+
+		c := &conn{
+			netConn:     server,
+			mode:        ProxyMode,
+			l:           testutil.Logger(t),
+			proxy:       p,
+			idleTimeout: time.Hour,
+		}
+
+		drain := make(chan struct{})
+		errCh := make(chan error, 1)
+
+		go func() {
+			errCh <- c.run(testutil.Ctx(t), drain)
+		}()
+
+		header, body := pingMsg(t, 1)
+
+		bufw := bufio.NewWriter(client)
+		require.NoError(t, wire.WriteMessage(bufw, header, body))
+		require.NoError(t, bufw.Flush())
+
+		// drain while the request is in flight
+		<-received
+		close(drain)
+		close(release)
+
+		resHeader, _, err := wire.ReadMessage(bufio.NewReader(client))
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), resHeader.ResponseTo)
+
+		require.ErrorIs(t, <-errCh, errDrained)
+	})
+
+	t.Run("DrainPartialRequest", func(t *testing.T) {
+		t.Parallel()
+
+		received := make(chan struct{}, 1)
+		release := make(chan struct{})
+		close(release)
+
+		p, err := proxy.New(&proxy.NewOpts{
+			Addr: slowUpstream(t, received, release),
+			L:    testutil.Logger(t),
+		})
+		require.NoError(t, err)
+
+		server, client := tcpConns(t)
+
+		c := &conn{
+			netConn:     server,
+			mode:        ProxyMode,
+			l:           testutil.Logger(t),
+			proxy:       p,
+			idleTimeout: time.Hour,
+		}
+
+		drain := make(chan struct{})
+		errCh := make(chan error, 1)
+
+		go func() {
+			errCh <- c.run(testutil.Ctx(t), drain)
+		}()
+
+		header, body := pingMsg(t, 1)
+
+		var buf bytes.Buffer
+		bufw := bufio.NewWriter(&buf)
+		require.NoError(t, wire.WriteMessage(bufw, header, body))
+		require.NoError(t, bufw.Flush())
+
+		b := buf.Bytes()
+
+		// drain after the request started to arrive, but before it was read completely
+		_, err = client.Write(b[:wire.MsgHeaderLen])
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			c.drainM.Lock()
+			defer c.drainM.Unlock()
+
+			return !c.idle
+		}, time.Second, 10*time.Millisecond)
+
+		close(drain)
+
+		_, err = client.Write(b[wire.MsgHeaderLen:])
+		require.NoError(t, err)
+
+		<-received
+
+		resHeader, _, err := wire.ReadMessage(bufio.NewReader(client))
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), resHeader.ResponseTo)
+
+		require.ErrorIs(t, <-errCh, errDrained)
 	})
 }
+
+func TestRoutingUnauthenticated(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	close(release)
+
+	p, err := proxy.New(&proxy.NewOpts{
+		Addr: slowUpstream(t, received, release),
+		L:    testutil.Logger(t),
+	})
+	require.NoError(t, err)
+
+	server, client := tcpConns(t)
+
+	c := &conn{
+		netConn: server,
+		mode:    RoutingMode,
+		l:       testutil.Logger(t),
+		h:       &handler.Handler{NewOpts: &handler.NewOpts{Auth: true}},
+		proxy:   p,
+		router:  router.New([]router.Rule{{Database: "*", Collection: "*", Target: router.TargetProxy}}),
+	}
+
+	go func() {
+		_ = c.run(testutil.Ctx(t), nil)
+	}()
+
+	msg := wire.MustOpMsg("find", "test", "$db", "test")
+
+	b, err := msg.MarshalBinary()
+	require.NoError(t, err)
+
+	header := &wire.MsgHeader{
+		MessageLength: int32(wire.MsgHeaderLen + len(b)),
+		RequestID:     1,
+		OpCode:        wire.OpCodeMsg,
+	}
+
+	bufw := bufio.NewWriter(client)
+	require.NoError(t, wire.WriteMessage(bufw, header, msg))
+	require.NoError(t, bufw.Flush())
+
+	resHeader, resBody, err := wire.ReadMessage(bufio.NewReader(client))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), resHeader.ResponseTo)
+
+	doc, err := resBody.(*wire.OpMsg).DocumentDeep()
+	require.NoError(t, err)
+	assert.Equal(t, float64(0), doc.Get("ok"))
+	assert.Equal(t, int32(mongoerrors.ErrUnauthorized), doc.Get("code"))
+
+	// the request was not sent to the proxy
+	assert.Empty(t, received)
+}
@@ -19,6 +19,8 @@
 	"net/netip"
 	"sync"
 
+	"github.com/FerretDB/wire/wirebson"
+
 	"github.com/FerretDB/FerretDB/v2/internal/util/resource"
 	"github.com/FerretDB/FerretDB/v2/internal/util/scram"
 )
@@ -27,11 +29,12 @@
 type ConnInfo struct {
 	// the order of fields is weird to make the struct smaller due to alignment
 
-	conv         *scram.Conv    // protected by rw
-	Peer         netip.AddrPort // invalid for Unix domain sockets
-	rw           sync.RWMutex   // rw
-	metadataRecv bool           // protected by rw
-	steps        int            // protected by rw
+	conv         *scram.Conv        // protected by rw
+	metadata     *wirebson.Document // protected by rw
+	Peer         netip.AddrPort     // invalid for Unix domain sockets
+	rw           sync.RWMutex       // rw
+	metadataRecv bool               // protected by rw
+	steps        int                // protected by rw
 
 	token *resource.Token
 }
@@ -80,12 +83,43 @@ func (ci *ConnInfo) MetadataRecv() bool {
 	return ci.metadataRecv
 }
 
-// SetMetadataRecv marks client metadata as received.
-func (ci *ConnInfo) SetMetadataRecv() {
+// SetMetadataRecv marks client metadata as received and stores it.
+func (ci *ConnInfo) SetMetadataRecv(metadata *wirebson.Document) {
 	ci.rw.Lock()
 	defer ci.rw.Unlock()
 
 	ci.metadataRecv = true
+	ci.metadata = metadata
+}
+
+// Metadata returns client metadata sent in the first `hello` command, if any.
+func (ci *ConnInfo) Metadata() *wirebson.Document {
+	ci.rw.RLock()
+	defer ci.rw.RUnlock()
+
+	return ci.metadata
+}
+
+// AppName returns application name from client metadata, if any.
+func (ci *ConnInfo) AppName() string {
+	metadata := ci.Metadata()
+	if metadata == nil {
+		return ""
+	}
+
+	app, _ := metadata.Get("application").(wirebson.AnyDocument)
+	if app == nil {
+		return ""
+	}
+
+	doc, err := app.Decode()
+	if err != nil {
+		return ""
+	}
+
+	name, _ := doc.Get("name").(string)
+
+	return name
 }
 
 // DecrementSteps decreases the steps counter and returns the number of steps left
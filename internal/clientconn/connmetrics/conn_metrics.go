@@ -17,6 +17,7 @@
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
@@ -28,6 +29,22 @@
 type ConnMetrics struct {
 	Requests  *prometheus.CounterVec
 	Responses *prometheus.CounterVec
+
+	// Latencies of collection-level operations by database, collection,
+	// operation type (as reported by `top` command), and lock type ("read" or "write").
+	Latencies *prometheus.HistogramVec
+}
+
+// NamespaceLatency represents cumulative latency of collection-level operations of one type.
+//
+//nolint:vet // for readability
+type NamespaceLatency struct {
+	DB         string
+	Collection string
+	Type       string
+	Lock       string
+	Count      int64
+	Time       time.Duration
 }
 
 // commandMetrics represents command results metrics.
@@ -57,6 +74,28 @@ func newConnMetrics() *ConnMetrics {
 			},
 			[]string{"opcode", "command", "argument", "result"},
 		),
+		Latencies: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "namespace_latency_seconds",
+				Help:      "Latency of collection-level operations in seconds.",
+				Buckets: []float64{
+					(100 * time.Microsecond).Seconds(),
+					(500 * time.Microsecond).Seconds(),
+					(1 * time.Millisecond).Seconds(),
+					(5 * time.Millisecond).Seconds(),
+					(10 * time.Millisecond).Seconds(),
+					(50 * time.Millisecond).Seconds(),
+					(100 * time.Millisecond).Seconds(),
+					(500 * time.Millisecond).Seconds(),
+					(1000 * time.Millisecond).Seconds(),
+					(5000 * time.Millisecond).Seconds(),
+					(10000 * time.Millisecond).Seconds(),
+				},
+			},
+			[]string{"db", "collection", "type", "lock"},
+		),
 	}
 
 	cm.Requests.WithLabelValues("OP_MSG", "find")
@@ -69,12 +108,14 @@ func newConnMetrics() *ConnMetrics {
 func (cm *ConnMetrics) Describe(ch chan<- *prometheus.Desc) {
 	cm.Requests.Describe(ch)
 	cm.Responses.Describe(ch)
+	cm.Latencies.Describe(ch)
 }
 
 // Collect implements [prometheus.Collector].
 func (cm *ConnMetrics) Collect(ch chan<- prometheus.Metric) {
 	cm.Requests.Collect(ch)
 	cm.Responses.Collect(ch)
+	cm.Latencies.Collect(ch)
 }
 
 // GetResponses returns a map with all response metrics:
@@ -146,6 +187,61 @@ func (cm *ConnMetrics) GetResponses() map[string]map[string]map[string]commandMe
 	return res
 }
 
+// GetLatencies returns cumulative latencies of collection-level operations
+// for all databases, collections, operation and lock types.
+func (cm *ConnMetrics) GetLatencies() []NamespaceLatency {
+	metrics := make(chan prometheus.Metric)
+	go func() {
+		cm.Latencies.Collect(metrics)
+		close(metrics)
+	}()
+
+	var res []NamespaceLatency
+
+	for m := range metrics {
+		var content dto.Metric
+		must.NoError(m.Write(&content))
+
+		var l NamespaceLatency
+		for _, label := range content.GetLabel() {
+			switch label.GetName() {
+			case "db":
+				l.DB = label.GetValue()
+			case "collection":
+				l.Collection = label.GetValue()
+			case "type":
+				l.Type = label.GetValue()
+			case "lock":
+				l.Lock = label.GetValue()
+			default:
+				panic(fmt.Sprintf(
+					"%s is not a valid label. Allowed: [db, collection, type, lock]",
+					label.GetName(),
+				))
+			}
+		}
+
+		h := content.GetHistogram()
+		l.Count = int64(h.GetSampleCount())
+		l.Time = time.Duration(h.GetSampleSum() * float64(time.Second))
+
+		res = append(res, l)
+	}
+
+	return res
+}
+
+// DeleteLatencies deletes latencies of the given collection,
+// or of all collections of the given database if collection is empty.
+func (cm *ConnMetrics) DeleteLatencies(db, collection string) {
+	labels := prometheus.Labels{"db": db}
+	if collection != "" {
+		labels["collection"] = collection
+	}
+
+	cm.Latencies.DeletePartialMatch(labels)
+}
+
 // check interfaces
 var (
 	_ prometheus.Collector = (*ConnMetrics)(nil)
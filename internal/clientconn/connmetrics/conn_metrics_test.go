@@ -16,6 +16,7 @@
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -45,3 +46,39 @@ func TestGetResponses(t *testing.T) {
 	}
 	assert.Equal(t, expected, cm.GetResponses())
 }
+
+func TestGetLatencies(t *testing.T) {
+	cm := newConnMetrics()
+	cm.Latencies.WithLabelValues("test", "values", "queries", "read").Observe(0.5)
+	cm.Latencies.WithLabelValues("test", "values", "queries", "read").Observe(0.25)
+	cm.Latencies.WithLabelValues("test", "values", "insert", "write").Observe(0.001)
+
+	expected := []NamespaceLatency{
+		{DB: "test", Collection: "values", Type: "insert", Lock: "write", Count: 1, Time: time.Millisecond},
+		{DB: "test", Collection: "values", Type: "queries", Lock: "read", Count: 2, Time: 750 * time.Millisecond},
+	}
+	assert.ElementsMatch(t, expected, cm.GetLatencies())
+}
+
+func TestDeleteLatencies(t *testing.T) {
+	cm := newConnMetrics()
+	cm.Latencies.WithLabelValues("test", "values", "queries", "read").Observe(0.5)
+	cm.Latencies.WithLabelValues("test", "values", "insert", "write").Observe(0.5)
+	cm.Latencies.WithLabelValues("test", "other", "queries", "read").Observe(0.5)
+	cm.Latencies.WithLabelValues("db", "values", "queries", "read").Observe(0.5)
+
+	cm.DeleteLatencies("test", "values")
+
+	expected := []NamespaceLatency{
+		{DB: "test", Collection: "other", Type: "queries", Lock: "read", Count: 1, Time: 500 * time.Millisecond},
+		{DB: "db", Collection: "values", Type: "queries", Lock: "read", Count: 1, Time: 500 * time.Millisecond},
+	}
+	assert.ElementsMatch(t, expected, cm.GetLatencies())
+
+	cm.DeleteLatencies("test", "")
+
+	expected = []NamespaceLatency{
+		{DB: "db", Collection: "values", Type: "queries", Lock: "read", Count: 1, Time: 500 * time.Millisecond},
+	}
+	assert.ElementsMatch(t, expected, cm.GetLatencies())
+}
@@ -30,6 +30,7 @@
 type ListenerMetrics struct {
 	Accepts     *prometheus.CounterVec
 	Durations   *prometheus.HistogramVec
+	IdleClosed  prometheus.Counter
 	ConnMetrics *ConnMetrics
 }
 
@@ -64,6 +65,14 @@ func NewListenerMetrics() *ListenerMetrics {
 			},
 			[]string{"error"},
 		),
+		IdleClosed: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "idle_closed_total",
+				Help:      "Total number of client connections closed due to idle timeout.",
+			},
+		),
 
 		ConnMetrics: newConnMetrics(),
 	}
@@ -78,6 +87,7 @@ func NewListenerMetrics() *ListenerMetrics {
 func (lm *ListenerMetrics) Describe(ch chan<- *prometheus.Desc) {
 	lm.Accepts.Describe(ch)
 	lm.Durations.Describe(ch)
+	lm.IdleClosed.Describe(ch)
 	lm.ConnMetrics.Describe(ch)
 }
 
@@ -85,6 +95,7 @@ func (lm *ListenerMetrics) Describe(ch chan<- *prometheus.Desc) {
 func (lm *ListenerMetrics) Collect(ch chan<- prometheus.Metric) {
 	lm.Accepts.Collect(ch)
 	lm.Durations.Collect(ch)
+	lm.IdleClosed.Collect(ch)
 	lm.ConnMetrics.Collect(ch)
 }
 
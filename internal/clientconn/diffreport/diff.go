@@ -0,0 +1,199 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diffreport
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/FerretDB/wire/wirebson"
+)
+
+// maxDiffs is the maximal number of field differences recorded for a single request.
+const maxDiffs = 100
+
+// volatileFields contains top-level fields that differ between servers and requests by design.
+// They are ignored when documents are compared and removed from normalized requests.
+var volatileFields = []string{
+	"$clusterTime",
+	"operationTime",
+	"localTime",
+	"connectionId",
+	"lsid",
+}
+
+// volatilePaths contains nested fields that differ between servers by design.
+// They are ignored when documents are compared.
+var volatilePaths = []string{
+	"cursor.id",
+}
+
+// FieldDiff represents a single field difference between normal and proxy responses.
+//
+// Values are formatted as in logs; an empty value means that the field is missing.
+type FieldDiff struct {
+	Path   string `json:"path"`
+	Normal string `json:"normal,omitempty"`
+	Proxy  string `json:"proxy,omitempty"`
+}
+
+// Normalize returns a copy of the document without volatile fields.
+// It returns nil for nil document.
+func Normalize(doc *wirebson.Document) *wirebson.Document {
+	if doc == nil {
+		return nil
+	}
+
+	res := wirebson.MakeDocument(doc.Len())
+
+	for k, v := range doc.All() {
+		if slices.Contains(volatileFields, k) {
+			continue
+		}
+
+		_ = res.Add(k, v)
+	}
+
+	return res
+}
+
+// Diff returns field-level differences between normal and proxy responses.
+// Volatile fields are ignored.
+func Diff(normal, proxy *wirebson.Document) []FieldDiff {
+	var res []FieldDiff
+	diffDocuments("", Normalize(normal), Normalize(proxy), &res)
+
+	return res
+}
+
+// diffValues appends differences between two values to res.
+// Nil values represent missing fields.
+func diffValues(path string, a, b any, res *[]FieldDiff) {
+	if len(*res) >= maxDiffs || slices.Contains(volatilePaths, path) {
+		return
+	}
+
+	if a != nil && b != nil {
+		if da, db := toDocument(a), toDocument(b); da != nil && db != nil {
+			diffDocuments(path, da, db, res)
+			return
+		}
+
+		if aa, ab := toArray(a), toArray(b); aa != nil && ab != nil {
+			diffArrays(path, aa, ab, res)
+			return
+		}
+
+		if wirebson.Equal(a, b) {
+			return
+		}
+	}
+
+	*res = append(*res, FieldDiff{
+		Path:   path,
+		Normal: format(a),
+		Proxy:  format(b),
+	})
+}
+
+// diffDocuments appends differences between two documents to res.
+func diffDocuments(path string, a, b *wirebson.Document, res *[]FieldDiff) {
+	if a == nil || b == nil {
+		if a != b {
+			*res = append(*res, FieldDiff{Path: path, Normal: format(a), Proxy: format(b)})
+		}
+
+		return
+	}
+
+	for k, v := range a.All() {
+		diffValues(join(path, k), v, b.Get(k), res)
+	}
+
+	for k, v := range b.All() {
+		if a.Get(k) == nil {
+			diffValues(join(path, k), nil, v, res)
+		}
+	}
+}
+
+// diffArrays appends differences between two arrays to res.
+func diffArrays(path string, a, b *wirebson.Array, res *[]FieldDiff) {
+	for i := range max(a.Len(), b.Len()) {
+		var va, vb any
+
+		if i < a.Len() {
+			va = a.Get(i)
+		}
+
+		if i < b.Len() {
+			vb = b.Get(i)
+		}
+
+		diffValues(join(path, strconv.Itoa(i)), va, vb, res)
+	}
+}
+
+// join returns dot notation path for the given parent path and key.
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// toDocument returns decoded document, or nil if v is not a document.
+func toDocument(v any) *wirebson.Document {
+	d, ok := v.(wirebson.AnyDocument)
+	if !ok {
+		return nil
+	}
+
+	doc, err := d.Decode()
+	if err != nil {
+		return nil
+	}
+
+	return doc
+}
+
+// toArray returns decoded array, or nil if v is not an array.
+func toArray(v any) *wirebson.Array {
+	a, ok := v.(wirebson.AnyArray)
+	if !ok {
+		return nil
+	}
+
+	arr, err := a.Decode()
+	if err != nil {
+		return nil
+	}
+
+	return arr
+}
+
+// format returns a value formatted for [FieldDiff].
+func format(v any) string {
+	if v == nil {
+		return ""
+	}
+
+	if d, ok := v.(*wirebson.Document); ok && d == nil {
+		return ""
+	}
+
+	return wirebson.LogMessage(v)
+}
@@ -0,0 +1,305 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diffreport provides structured reports of differences
+// between normal and proxy responses in diff modes.
+package diffreport
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// Parts of Prometheus metric names.
+const (
+	namespace = "ferretdb"
+	subsystem = "diff"
+)
+
+const (
+	// unknownCommand is used instead of unknown command names,
+	// so clients can't create arbitrary metric labels and summaries.
+	unknownCommand = "unknown"
+
+	// maxFields is the maximal number of field paths in a single command summary.
+	maxFields = 100
+
+	// otherFields is the summary field path for mismatches over maxFields.
+	otherFields = "(other)"
+)
+
+// Record represents a single request with different normal and proxy responses.
+//
+//nolint:vet // for readability
+type Record struct {
+	Time          time.Time          `json:"ts"`
+	Command       string             `json:"command"`
+	Request       *wirebson.Document `json:"request"` // normalized
+	Response      *wirebson.Document `json:"response"`
+	ProxyResponse *wirebson.Document `json:"proxyResponse"`
+	Diff          []FieldDiff        `json:"diff"`
+}
+
+// CommandSummary represents aggregated differences for a single command.
+//
+//nolint:vet // for readability
+type CommandSummary struct {
+	Command      string           `json:"command"`
+	Requests     int64            `json:"requests"`
+	Mismatches   int64            `json:"mismatches"`
+	Fields       map[string]int64 `json:"fields"` // number of mismatches by field path; see [summaryPath]
+	LastMismatch time.Time        `json:"lastMismatch,omitzero"`
+}
+
+// Reporter compares normal and proxy responses,
+// aggregates differences in memory, and writes them to a file in JSON Lines format.
+//
+// It is safe for concurrent use.
+type Reporter struct {
+	l *slog.Logger
+
+	fm sync.Mutex // protects f and e
+	f  *os.File
+	e  *json.Encoder
+
+	knownCommand func(string) bool
+
+	rw       sync.RWMutex // protects commands
+	commands map[string]*CommandSummary
+
+	requests   *prometheus.CounterVec
+	mismatches *prometheus.CounterVec
+}
+
+// NewOpts represents [New] options.
+type NewOpts struct {
+	L *slog.Logger
+
+	// Path to a file for mismatch records.
+	// If empty, records are only aggregated in memory.
+	Path string
+
+	// KnownCommand returns true for known command names.
+	// Other command names are aggregated as "unknown".
+	// If nil, all command names are considered unknown.
+	KnownCommand func(string) bool
+}
+
+// New creates a new Reporter.
+func New(opts *NewOpts) (*Reporter, error) {
+	must.NotBeZero(opts)
+
+	r := &Reporter{
+		l:            opts.L,
+		knownCommand: opts.KnownCommand,
+		commands:     map[string]*CommandSummary{},
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "requests_total",
+				Help:      "Total number of requests compared in diff modes.",
+			},
+			[]string{"command"},
+		),
+		mismatches: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "mismatches_total",
+				Help:      "Total number of requests with different normal and proxy responses.",
+			},
+			[]string{"command"},
+		),
+	}
+
+	if opts.Path != "" {
+		f, err := os.OpenFile(opts.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		r.f = f
+		r.e = json.NewEncoder(f)
+	}
+
+	return r, nil
+}
+
+// Report compares normal and proxy responses for the given request,
+// updates aggregated data and metrics, and writes a record if responses are different.
+//
+// Any document can be nil if it could not be decoded.
+// It returns a record if responses are different, nil otherwise.
+func (r *Reporter) Report(ctx context.Context, req, res, proxyRes *wirebson.Document) *Record {
+	command := unknownCommand
+	if req != nil && r.knownCommand != nil && r.knownCommand(req.Command()) {
+		command = req.Command()
+	}
+
+	diff := Diff(res, proxyRes)
+
+	r.requests.WithLabelValues(command).Inc()
+
+	var rec *Record
+
+	if len(diff) > 0 {
+		r.mismatches.WithLabelValues(command).Inc()
+
+		rec = &Record{
+			Time:          time.Now(),
+			Command:       command,
+			Request:       Normalize(req),
+			Response:      res,
+			ProxyResponse: proxyRes,
+			Diff:          diff,
+		}
+	}
+
+	r.rw.Lock()
+
+	s := r.commands[command]
+	if s == nil {
+		s = &CommandSummary{
+			Command: command,
+			Fields:  map[string]int64{},
+		}
+		r.commands[command] = s
+	}
+
+	s.Requests++
+
+	if rec != nil {
+		s.Mismatches++
+		s.LastMismatch = rec.Time
+
+		for _, d := range diff {
+			p := summaryPath(d.Path)
+			if _, ok := s.Fields[p]; !ok && len(s.Fields) >= maxFields {
+				p = otherFields
+			}
+
+			s.Fields[p]++
+		}
+	}
+
+	r.rw.Unlock()
+
+	if rec == nil || r.e == nil {
+		return rec
+	}
+
+	r.fm.Lock()
+	defer r.fm.Unlock()
+
+	if err := r.e.Encode(rec); err != nil {
+		r.l.ErrorContext(ctx, "Failed to write diff record", slog.String("command", command), logging.Error(err))
+	}
+
+	return rec
+}
+
+// summaryPath returns the field path with array indexes replaced by `$`,
+// so mismatches in different array elements are aggregated together.
+func summaryPath(path string) string {
+	parts := strings.Split(path, ".")
+
+	for i, p := range parts {
+		if _, err := strconv.Atoi(p); err == nil {
+			parts[i] = "$"
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// Summary returns aggregated differences for all commands,
+// sorted by the number of mismatches (descending), then by command name.
+func (r *Reporter) Summary() []CommandSummary {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	res := make([]CommandSummary, 0, len(r.commands))
+
+	for _, s := range r.commands {
+		c := *s
+		c.Fields = maps.Clone(s.Fields)
+		res = append(res, c)
+	}
+
+	slices.SortFunc(res, func(a, b CommandSummary) int {
+		if c := cmp.Compare(b.Mismatches, a.Mismatches); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.Command, b.Command)
+	})
+
+	return res
+}
+
+// Close closes the file, if any.
+func (r *Reporter) Close() error {
+	r.fm.Lock()
+	defer r.fm.Unlock()
+
+	if r.f == nil {
+		return nil
+	}
+
+	err := r.f.Close()
+	r.f = nil
+	r.e = nil
+
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// Describe implements [prometheus.Collector].
+func (r *Reporter) Describe(ch chan<- *prometheus.Desc) {
+	r.requests.Describe(ch)
+	r.mismatches.Describe(ch)
+}
+
+// Collect implements [prometheus.Collector].
+func (r *Reporter) Collect(ch chan<- prometheus.Metric) {
+	r.requests.Collect(ch)
+	r.mismatches.Collect(ch)
+}
+
+// check interfaces
+var (
+	_ prometheus.Collector = (*Reporter)(nil)
+	_ http.Handler         = (*Reporter)(nil)
+)
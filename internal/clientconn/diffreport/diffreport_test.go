@@ -0,0 +1,180 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diffreport
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		normal   *wirebson.Document
+		proxy    *wirebson.Document
+		expected []FieldDiff
+	}{
+		"Equal": {
+			normal: wirebson.MustDocument("ok", float64(1)),
+			proxy:  wirebson.MustDocument("ok", float64(1)),
+		},
+		"Volatile": {
+			normal: wirebson.MustDocument("ok", float64(1), "operationTime", int64(1), "connectionId", int32(1)),
+			proxy:  wirebson.MustDocument("ok", float64(1), "operationTime", int64(2)),
+		},
+		"Value": {
+			normal: wirebson.MustDocument("ok", float64(1), "n", int32(1)),
+			proxy:  wirebson.MustDocument("ok", float64(1), "n", int32(2)),
+			expected: []FieldDiff{
+				{Path: "n", Normal: "1", Proxy: "2"},
+			},
+		},
+		"Missing": {
+			normal: wirebson.MustDocument("ok", float64(1), "a", "x"),
+			proxy:  wirebson.MustDocument("ok", float64(1), "b", "y"),
+			expected: []FieldDiff{
+				{Path: "a", Normal: "`x`"},
+				{Path: "b", Proxy: "`y`"},
+			},
+		},
+		"Nested": {
+			normal: wirebson.MustDocument(
+				"cursor", wirebson.MustDocument(
+					"firstBatch", wirebson.MustArray(wirebson.MustDocument("v", int32(1)), wirebson.MustDocument("v", int32(2))),
+				),
+			),
+			proxy: wirebson.MustDocument(
+				"cursor", wirebson.MustDocument(
+					"firstBatch", wirebson.MustArray(wirebson.MustDocument("v", int32(1)), wirebson.MustDocument("v", int32(3))),
+				),
+			),
+			expected: []FieldDiff{
+				{Path: "cursor.firstBatch.1.v", Normal: "2", Proxy: "3"},
+			},
+		},
+		"NilProxy": {
+			normal: wirebson.MustDocument("ok", float64(1)),
+			expected: []FieldDiff{
+				{Path: "", Normal: "{`ok`: 1.0}"},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := Diff(tc.normal, tc.proxy)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestReporter(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+	path := filepath.Join(t.TempDir(), "diff.jsonl")
+
+	r, err := New(&NewOpts{
+		L:    testutil.Logger(t),
+		Path: path,
+		KnownCommand: func(name string) bool {
+			return name == "find" || name == "ping"
+		},
+	})
+	require.NoError(t, err)
+
+	req := wirebson.MustDocument("find", "test", "$db", "db", "lsid", wirebson.MustDocument("id", "x"))
+	ok := wirebson.MustDocument("ok", float64(1))
+
+	assert.Nil(t, r.Report(ctx, req, ok, ok))
+
+	rec := r.Report(ctx, req, ok, wirebson.MustDocument("ok", float64(0)))
+	require.NotNil(t, rec)
+	assert.Equal(t, "find", rec.Command)
+	assert.Nil(t, rec.Request.Get("lsid"))
+
+	assert.Nil(t, r.Report(ctx, wirebson.MustDocument("ping", int32(1)), ok, ok))
+
+	// cursor IDs differ by design
+	cursor := func(id int64) *wirebson.Document {
+		return wirebson.MustDocument("cursor", wirebson.MustDocument("id", id, "ns", "db.test"), "ok", float64(1))
+	}
+	assert.Nil(t, r.Report(ctx, req, cursor(1), cursor(2)))
+
+	// array indexes are aggregated
+	batch := func(vs ...int32) *wirebson.Document {
+		arr := wirebson.MakeArray(len(vs))
+		for _, v := range vs {
+			require.NoError(t, arr.Add(wirebson.MustDocument("v", v)))
+		}
+
+		return wirebson.MustDocument("cursor", wirebson.MustDocument("firstBatch", arr), "ok", float64(1))
+	}
+	require.NotNil(t, r.Report(ctx, req, batch(1, 2), batch(3, 4)))
+
+	// unknown commands do not create new labels
+	require.NotNil(t, r.Report(ctx, wirebson.MustDocument("x-random-1", int32(1)), ok, wirebson.MustDocument()))
+	require.NotNil(t, r.Report(ctx, wirebson.MustDocument("x-random-2", int32(1)), ok, wirebson.MustDocument()))
+
+	require.NoError(t, r.Close())
+
+	summary := r.Summary()
+	require.Len(t, summary, 3)
+	assert.Equal(t, "find", summary[0].Command)
+	assert.Equal(t, int64(4), summary[0].Requests)
+	assert.Equal(t, int64(2), summary[0].Mismatches)
+	assert.Equal(t, map[string]int64{"ok": 1, "cursor.firstBatch.$.v": 2}, summary[0].Fields)
+	assert.Equal(t, "unknown", summary[1].Command)
+	assert.Equal(t, int64(2), summary[1].Mismatches)
+	assert.Equal(t, "ping", summary[2].Command)
+	assert.Equal(t, int64(0), summary[2].Mismatches)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { require.NoError(t, f.Close()) })
+
+	var lines int
+
+	for s := bufio.NewScanner(f); s.Scan(); lines++ {
+		var actual map[string]any
+		require.NoError(t, json.Unmarshal(s.Bytes(), &actual))
+		assert.Contains(t, []string{"find", "unknown"}, actual["command"])
+	}
+
+	assert.Equal(t, 4, lines)
+
+	rw := httptest.NewRecorder()
+	r.ServeHTTP(rw, httptest.NewRequest("GET", "/debug/diff?format=json", nil))
+
+	var actual []CommandSummary
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &actual))
+	assert.Equal(t, summary[0].Command, actual[0].Command)
+
+	rw = httptest.NewRecorder()
+	r.ServeHTTP(rw, httptest.NewRequest("GET", "/debug/diff", nil))
+	assert.Contains(t, rw.Body.String(), "find")
+}
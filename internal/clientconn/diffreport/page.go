@@ -0,0 +1,100 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diffreport
+
+import (
+	"cmp"
+	"encoding/json"
+	"html/template"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+)
+
+// maxPageFields is the maximal number of field paths shown for a single command on the summary page.
+const maxPageFields = 10
+
+// pageTemplate is the summary page template.
+var pageTemplate = template.Must(template.New("diff").Parse(`
+	<html>
+	<body>
+	<table border="1">
+	<tr><th>Command</th><th>Requests</th><th>Mismatches</th><th>Top mismatched fields</th><th>Last mismatch</th></tr>
+	{{range .}}
+		<tr>
+		<td>{{.Command}}</td>
+		<td>{{.Requests}}</td>
+		<td>{{.Mismatches}}</td>
+		<td>{{range .TopFields}}{{.Path}}: {{.Count}}<br />{{end}}</td>
+		<td>{{if not .LastMismatch.IsZero}}{{.LastMismatch.Format "2006-01-02T15:04:05Z07:00"}}{{end}}</td>
+		</tr>
+	{{end}}
+	</table>
+	<p><a href="?format=json">JSON</a></p>
+	</body>
+	</html>
+`))
+
+// pageField represents a field path with the number of mismatches.
+type pageField struct {
+	Path  string
+	Count int64
+}
+
+// pageRow represents a single row of the summary page.
+type pageRow struct {
+	CommandSummary
+	TopFields []pageField
+}
+
+// ServeHTTP implements [http.Handler] by rendering the summary page.
+// With `format=json` query parameter, it returns [Reporter.Summary] as JSON.
+func (r *Reporter) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	summary := r.Summary()
+
+	if req.URL.Query().Get("format") == "json" {
+		rw.Header().Set("Content-Type", "application/json")
+
+		if err := json.NewEncoder(rw).Encode(summary); err != nil {
+			r.l.WarnContext(req.Context(), "Failed to write diff summary", logging.Error(err))
+		}
+
+		return
+	}
+
+	rows := make([]pageRow, len(summary))
+
+	for i, s := range summary {
+		paths := slices.SortedFunc(maps.Keys(s.Fields), func(a, b string) int {
+			if c := cmp.Compare(s.Fields[b], s.Fields[a]); c != 0 {
+				return c
+			}
+
+			return cmp.Compare(a, b)
+		})
+
+		rows[i] = pageRow{CommandSummary: s}
+
+		for _, p := range paths[:min(len(paths), maxPageFields)] {
+			rows[i].TopFields = append(rows[i].TopFields, pageField{Path: p, Count: s.Fields[p]})
+		}
+	}
+
+	if err := pageTemplate.Execute(rw, rows); err != nil {
+		r.l.WarnContext(req.Context(), "Failed to render diff summary", logging.Error(err))
+	}
+}
@@ -15,6 +15,7 @@
 package clientconn
 
 import (
+	"cmp"
 	"context"
 	"crypto/tls"
 	"errors"
@@ -22,14 +23,22 @@
 	"log/slog"
 	"math/rand"
 	"net"
+	"net/netip"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/FerretDB/wire"
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/diffreport"
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/proxyproto"
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/router"
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/shadow"
 	"github.com/FerretDB/FerretDB/v2/internal/handler"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/proxy"
 	"github.com/FerretDB/FerretDB/v2/internal/util/ctxutil"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
@@ -43,10 +52,17 @@ type Listener struct {
 
 	ll *slog.Logger
 
-	tcpListener  net.Listener
+	tcpListeners []net.Listener
 	unixListener net.Listener
-	tlsListener  net.Listener
+	tlsListeners []net.Listener
 
+	proxy  *proxy.Handler // nil in normal mode
+	shadow *shadow.Mirror // nil unless in shadow mode
+	router *router.Router // nil unless in routing mode
+
+	conns atomic.Int64 // number of open client connections
+
+	draining        chan struct{}
 	listenersClosed chan struct{}
 }
 
@@ -56,21 +72,74 @@ type ListenerOpts struct {
 	Metrics *connmetrics.ListenerMetrics
 	Logger  *slog.Logger
 
-	TCP  string // empty value disables TCP listener
-	Unix string // empty value disables Unix listener
+	TCP  []string // empty value disables TCP listeners
+	Unix string   // empty value disables Unix listener
 
-	TLS         string // empty value disables TLS listener
-	TLSCertFile string
-	TLSKeyFile  string
-	TLSCAFile   string
+	TLS []TLSListenerOpts // empty value disables TLS listeners
+
+	// ProxyProtocolTrusted contains networks of trusted load balancers.
+	// PROXY protocol headers are parsed on TCP and TLS connections from them.
+	// Empty value disables PROXY protocol support.
+	ProxyProtocolTrusted []netip.Prefix
 
 	Mode             Mode
 	ProxyAddr        string
 	ProxyTLSCertFile string
 	ProxyTLSKeyFile  string
 	ProxyTLSCAFile   string
+	ProxyMaxConns    int // maximum number of upstream connections pinned to client connections
+
+	// Shadow configures request mirroring in shadow mode.
+	Shadow ShadowOpts
+
+	// RoutingRules select backends for requests in routing mode.
+	// The first matching rule wins; other requests are handled by FerretDB.
+	RoutingRules []router.Rule
 
 	TestRecordsDir string // if empty, no records are created
+
+	// DiffReporter receives normal and proxy responses in diff modes.
+	// If nil, differences are only logged.
+	DiffReporter *diffreport.Reporter
+
+	// IdleTimeout is the maximum time to wait for the next client request
+	// before closing the connection on all listeners.
+	// Zero value disables idle timeout.
+	IdleTimeout time.Duration
+
+	// TCPKeepAlive configures TCP keep-alive probes on TCP and TLS listeners.
+	// Zero value uses Go defaults; keep-alive is disabled if Enable is false otherwise.
+	TCPKeepAlive net.KeepAliveConfig
+
+	// ShutdownDrainDelay is the time between shutdown initiation and closing listeners.
+	// During that time, readiness probe fails and `hello` returns an error,
+	// but new connections are accepted and established connections keep reading requests,
+	// so drivers and load balancers could select another server.
+	// Zero value disables delay.
+	ShutdownDrainDelay time.Duration
+
+	// ShutdownTimeout is the time given to established connections
+	// to finish in-flight requests after listeners are closed.
+	// Zero value means [ctxutil.DefaultDelay].
+	ShutdownTimeout time.Duration
+}
+
+// ShadowOpts represents request mirroring configuration for shadow mode.
+// Requests are mirrored to the proxy address.
+//
+//nolint:vet // for readability
+type ShadowOpts struct {
+	QueueSize  int     // zero value means default
+	SampleRate float64 // fraction of requests to mirror, greater than 0 and up to 1
+	Filter     shadow.Filter
+}
+
+// TLSListenerOpts represents a single TLS listener configuration.
+type TLSListenerOpts struct {
+	Addr     string
+	CertFile string
+	KeyFile  string
+	CAFile   string
 }
 
 // Listen creates a new listener and starts listening on configured interfaces.
@@ -81,6 +150,7 @@ func Listen(opts *ListenerOpts) (l *Listener, err error) {
 	l = &Listener{
 		ListenerOpts:    opts,
 		ll:              ll,
+		draining:        make(chan struct{}),
 		listenersClosed: make(chan struct{}),
 	}
 
@@ -93,13 +163,50 @@ func Listen(opts *ListenerOpts) (l *Listener, err error) {
 
 	ctx := context.Background()
 
-	if l.TCP != "" {
-		if l.tcpListener, err = net.Listen("tcp", l.TCP); err != nil {
+	if l.Mode != NormalMode {
+		l.proxy, err = proxy.New(&proxy.NewOpts{
+			Addr:     l.ProxyAddr,
+			CertFile: l.ProxyTLSCertFile,
+			KeyFile:  l.ProxyTLSKeyFile,
+			CAFile:   l.ProxyTLSCAFile,
+			L:        logging.WithName(opts.Logger, "proxy"),
+			MaxConns: l.ProxyMaxConns,
+		})
+		if err != nil {
+			err = lazyerrors.Error(err)
+			return
+		}
+	}
+
+	if l.Mode == ShadowMode {
+		l.shadow, err = shadow.New(&shadow.NewOpts{
+			L:          logging.WithName(opts.Logger, "shadow"),
+			Handler:    l.proxy.Handle,
+			QueueSize:  l.Shadow.QueueSize,
+			SampleRate: l.Shadow.SampleRate,
+			Filter:     l.Shadow.Filter,
+		})
+		if err != nil {
+			err = lazyerrors.Error(err)
+			return
+		}
+	}
+
+	if l.Mode == RoutingMode {
+		l.router = router.New(l.RoutingRules)
+	}
+
+	for _, addr := range l.TCP {
+		var lis net.Listener
+		if lis, err = l.listenConfig().Listen(ctx, "tcp", addr); err != nil {
 			err = lazyerrors.Error(err)
 			return
 		}
 
-		ll.InfoContext(ctx, fmt.Sprintf("Listening on TCP %s", l.TCPAddr()))
+		lis = l.wrapProxyProtocol(lis)
+		l.tcpListeners = append(l.tcpListeners, lis)
+
+		ll.InfoContext(ctx, fmt.Sprintf("Listening on TCP %s", lis.Addr()))
 	}
 
 	if l.Unix != "" {
@@ -111,37 +218,70 @@ func Listen(opts *ListenerOpts) (l *Listener, err error) {
 		ll.InfoContext(ctx, fmt.Sprintf("Listening on Unix %s", l.UnixAddr()))
 	}
 
-	if l.TLS != "" {
+	for _, o := range l.TLS {
 		var config *tls.Config
 
-		if config, err = tlsutil.Config(l.TLSCertFile, l.TLSKeyFile, l.TLSCAFile); err != nil {
+		if config, err = tlsutil.Config(o.CertFile, o.KeyFile, o.CAFile); err != nil {
 			err = lazyerrors.Error(err)
 			return
 		}
 
-		if l.tlsListener, err = tls.Listen("tcp", l.TLS, config); err != nil {
+		var lis net.Listener
+		if lis, err = l.listenConfig().Listen(ctx, "tcp", o.Addr); err != nil {
 			err = lazyerrors.Error(err)
 			return
 		}
 
-		ll.InfoContext(ctx, fmt.Sprintf("Listening on TLS %s", l.TLSAddr()))
+		// PROXY header is sent before TLS handshake
+		lis = tls.NewListener(l.wrapProxyProtocol(lis), config)
+		l.tlsListeners = append(l.tlsListeners, lis)
+
+		ll.InfoContext(ctx, fmt.Sprintf("Listening on TLS %s", lis.Addr()))
 	}
 
 	return
 }
 
-// close closes all listeners.
-func (l *Listener) close() {
-	if l.tcpListener != nil {
-		_ = l.tcpListener.Close()
+// listenConfig returns configuration for TCP and TLS listeners.
+func (l *Listener) listenConfig() *net.ListenConfig {
+	var lc net.ListenConfig
+
+	if l.TCPKeepAlive == (net.KeepAliveConfig{}) {
+		return &lc
+	}
+
+	lc.KeepAliveConfig = l.TCPKeepAlive
+	if !l.TCPKeepAlive.Enable {
+		lc.KeepAlive = -1
+	}
+
+	return &lc
+}
+
+// wrapProxyProtocol wraps the given TCP listener to parse PROXY protocol headers, if enabled.
+func (l *Listener) wrapProxyProtocol(lis net.Listener) net.Listener {
+	if len(l.ProxyProtocolTrusted) == 0 {
+		return lis
 	}
 
+	return proxyproto.NewListener(lis, l.ProxyProtocolTrusted)
+}
+
+// all returns all listeners.
+func (l *Listener) all() []net.Listener {
+	res := slices.Clone(l.tcpListeners)
+
 	if l.unixListener != nil {
-		_ = l.unixListener.Close()
+		res = append(res, l.unixListener)
 	}
 
-	if l.tlsListener != nil {
-		_ = l.tlsListener.Close()
+	return append(res, l.tlsListeners...)
+}
+
+// close closes all listeners.
+func (l *Listener) close() {
+	for _, lis := range l.all() {
+		_ = lis.Close()
 	}
 
 	close(l.listenersClosed)
@@ -160,8 +300,25 @@ func (l *Listener) Listening() bool {
 	}
 }
 
+// Draining returns true if the listener is shutting down.
+//
+// It becomes true before listeners are closed,
+// and stays true while established connections finish in-flight requests.
+func (l *Listener) Draining() bool {
+	select {
+	case <-l.draining:
+		return true
+	default:
+		return false
+	}
+}
+
 // Run runs the listener (and handler) until ctx is canceled.
 //
+// On cancellation, listener starts draining: readiness probe fails and `hello` returns an error.
+// After [ListenerOpts.ShutdownDrainDelay], listeners are closed, and idle connections are closed.
+// Connections that process requests are given [ListenerOpts.ShutdownTimeout] to finish them.
+//
 // When this method returns, listener and all connections are closed, and handler is stopped.
 func (l *Listener) Run(ctx context.Context) {
 	// inherit ctx's values
@@ -173,48 +330,56 @@ func (l *Listener) Run(ctx context.Context) {
 		l.Handler.Run(handlerCtx)
 	}()
 
-	var wg sync.WaitGroup
+	proxyDone := make(chan struct{})
 
-	if l.tcpListener != nil {
-		wg.Add(1)
+	go func() {
+		defer close(proxyDone)
 
-		go func() {
-			defer func() {
-				l.ll.InfoContext(ctx, fmt.Sprintf("%s stopped", l.TCPAddr()))
-				wg.Done()
-			}()
+		if l.proxy != nil {
+			l.proxy.Run(handlerCtx)
+		}
+	}()
 
-			acceptLoop(ctx, l.tcpListener, &wg, l)
-		}()
-	}
+	shadowDone := make(chan struct{})
 
-	if l.unixListener != nil {
-		wg.Add(1)
+	go func() {
+		defer close(shadowDone)
 
-		go func() {
-			defer func() {
-				l.ll.InfoContext(ctx, fmt.Sprintf("%s stopped", l.UnixAddr()))
-				wg.Done()
-			}()
+		if l.shadow != nil {
+			l.shadow.Run(handlerCtx)
+		}
+	}()
 
-			acceptLoop(ctx, l.unixListener, &wg, l)
-		}()
-	}
+	// canceled after drain delay; stops accepting new connections and reading new requests
+	stopCtx, stopCancel := ctxutil.WithDelay(ctx, l.ShutdownDrainDelay)
+	defer stopCancel(nil)
 
-	if l.tlsListener != nil {
+	var wg sync.WaitGroup
+
+	for _, lis := range l.all() {
 		wg.Add(1)
 
 		go func() {
 			defer func() {
-				l.ll.InfoContext(ctx, fmt.Sprintf("%s stopped", l.TLSAddr()))
+				l.ll.InfoContext(ctx, fmt.Sprintf("%s stopped", lis.Addr()))
 				wg.Done()
 			}()
 
-			acceptLoop(ctx, l.tlsListener, &wg, l)
+			acceptLoop(stopCtx, lis, &wg, l)
 		}()
 	}
 
 	<-ctx.Done()
+
+	close(l.draining)
+	l.Handler.Drain()
+
+	if l.ShutdownDrainDelay > 0 {
+		l.ll.InfoContext(ctx, fmt.Sprintf("Draining for %s before closing listeners", l.ShutdownDrainDelay))
+	}
+
+	<-stopCtx.Done()
+
 	l.close()
 	l.ll.InfoContext(ctx, "Waiting for all connections to close")
 	wg.Wait()
@@ -223,6 +388,8 @@ func (l *Listener) Run(ctx context.Context) {
 	// stop handler only after the last client disconnects
 	handlerCancel()
 	<-handlerDone
+	<-proxyDone
+	<-shadowDone
 }
 
 // acceptLoop runs listener's connection accepting loop until context is canceled.
@@ -246,7 +413,25 @@ func acceptLoop(ctx context.Context, listener net.Listener, wg *sync.WaitGroup,
 			continue
 		}
 
+		if limit := l.Handler.MaxConnections(); limit > 0 && l.conns.Load() >= int64(limit) {
+			// log the socket address; reading the PROXY header there could block the accept loop
+			socket := netConn
+			if pc, ok := netConn.(*proxyproto.Conn); ok {
+				socket = pc.Conn
+			}
+
+			l.ll.WarnContext(
+				ctx, "Connection refused because there are too many open connections",
+				slog.String("remote", socket.RemoteAddr().String()), slog.Int("limit", limit),
+			)
+
+			netConn.Close()
+
+			continue
+		}
+
 		wg.Add(1)
+		l.conns.Add(1)
 		l.Metrics.Accepts.WithLabelValues("0").Inc()
 
 		go func() {
@@ -261,11 +446,12 @@ func acceptLoop(ctx context.Context, listener net.Listener, wg *sync.WaitGroup,
 
 				l.Metrics.Durations.WithLabelValues(lv).Observe(time.Since(start).Seconds())
 				netConn.Close()
+				l.conns.Add(-1)
 				wg.Done()
 			}()
 
-			// give already connected clients a few seconds to gracefully disconnect
-			connCtx, connCancel := ctxutil.WithDelay(ctx)
+			// give already connected clients some time to finish in-flight requests
+			connCtx, connCancel := ctxutil.WithDelay(ctx, cmp.Or(l.ShutdownTimeout, ctxutil.DefaultDelay))
 			defer connCancel(nil)
 
 			remoteAddr := netConn.RemoteAddr().String()
@@ -282,43 +468,52 @@ func acceptLoop(ctx context.Context, listener net.Listener, wg *sync.WaitGroup,
 				l:           logging.WithName(l.ll, "// "+connID+" "), // derive from the original unnamed logger
 				handler:     l.Handler,
 				connMetrics: l.Metrics.ConnMetrics, // share between all conns
-
-				proxyAddr:        l.ProxyAddr,
-				proxyTLSCertFile: l.ProxyTLSCertFile,
-				proxyTLSKeyFile:  l.ProxyTLSKeyFile,
-				proxyTLSCAFile:   l.ProxyTLSCAFile,
+				proxy:       l.proxy,               // share between all conns
 
 				testRecordsDir: l.TestRecordsDir,
+				idleTimeout:    l.IdleTimeout,
+				diffReporter:   l.DiffReporter,
+				shadow:         l.shadow,
+				router:         l.router,
 			}
 
-			conn, connErr := newConn(opts)
-			if connErr != nil {
-				l.ll.WarnContext(connCtx, "Failed to create connection", slog.String("conn", connID), logging.Error(connErr))
-				return
-			}
+			conn := newConn(opts)
 
 			l.ll.InfoContext(ctx, "Connection started", slog.String("conn", connID))
 
-			connErr = conn.run(connCtx)
-			if errors.Is(connErr, wire.ErrZeroRead) {
+			connErr = conn.run(connCtx, ctx.Done())
+			switch {
+			case errors.Is(connErr, wire.ErrZeroRead), errors.Is(connErr, errDrained):
 				connErr = nil
 
 				l.ll.InfoContext(ctx, "Connection stopped", slog.String("conn", connID))
-			} else {
+
+			case errors.Is(connErr, errIdle):
+				connErr = nil
+				l.Metrics.IdleClosed.Inc()
+
+				l.ll.InfoContext(ctx, "Connection closed due to idle timeout", slog.String("conn", connID))
+
+			default:
 				l.ll.WarnContext(ctx, "Connection stopped", slog.String("conn", connID), logging.Error(connErr))
 			}
 		}()
 	}
 }
 
-// TCPAddr returns TCP listener's address, or nil, if TCP listener is disabled.
+// TCPAddr returns the first TCP listener's address, or nil, if TCP listeners are disabled.
 // It can be used to determine an actually used port, if it was zero.
 func (l *Listener) TCPAddr() net.Addr {
-	if l.tcpListener == nil {
+	if len(l.tcpListeners) == 0 {
 		return nil
 	}
 
-	return l.tcpListener.Addr()
+	return l.tcpListeners[0].Addr()
+}
+
+// TCPAddrs returns addresses of all TCP listeners in the order of [ListenerOpts.TCP].
+func (l *Listener) TCPAddrs() []net.Addr {
+	return addrs(l.tcpListeners)
 }
 
 // UnixAddr returns Unix domain socket listener's address, or nil, if Unix listener is disabled.
@@ -330,26 +525,57 @@ func (l *Listener) UnixAddr() net.Addr {
 	return l.unixListener.Addr()
 }
 
-// TLSAddr returns TLS listener's address, or nil, if TLS listener is disabled.
+// TLSAddr returns the first TLS listener's address, or nil, if TLS listeners are disabled.
 // It can be used to determine an actually used port, if it was zero.
 func (l *Listener) TLSAddr() net.Addr {
-	if l.tlsListener == nil {
+	if len(l.tlsListeners) == 0 {
 		return nil
 	}
 
-	return l.tlsListener.Addr()
+	return l.tlsListeners[0].Addr()
+}
+
+// TLSAddrs returns addresses of all TLS listeners in the order of [ListenerOpts.TLS].
+func (l *Listener) TLSAddrs() []net.Addr {
+	return addrs(l.tlsListeners)
+}
+
+// addrs returns addresses of given listeners.
+func addrs(listeners []net.Listener) []net.Addr {
+	res := make([]net.Addr, len(listeners))
+	for i, lis := range listeners {
+		res[i] = lis.Addr()
+	}
+
+	return res
 }
 
 // Describe implements [prometheus.Collector].
 func (l *Listener) Describe(ch chan<- *prometheus.Desc) {
 	l.Metrics.Describe(ch)
 	l.Handler.Describe(ch)
+
+	if l.DiffReporter != nil {
+		l.DiffReporter.Describe(ch)
+	}
+
+	if l.shadow != nil {
+		l.shadow.Describe(ch)
+	}
 }
 
 // Collect implements [prometheus.Collector].
 func (l *Listener) Collect(ch chan<- prometheus.Metric) {
 	l.Metrics.Collect(ch)
 	l.Handler.Collect(ch)
+
+	if l.DiffReporter != nil {
+		l.DiffReporter.Collect(ch)
+	}
+
+	if l.shadow != nil {
+		l.shadow.Collect(ch)
+	}
 }
 
 // check interfaces
@@ -27,7 +27,8 @@
 func TestListener(t *testing.T) {
 	l, err := Listen(&ListenerOpts{
 		Logger: testutil.Logger(t),
-		TCP:    "127.0.0.1:0",
+		TCP:    []string{"127.0.0.1:0", "127.0.0.1:0"},
+		Mode:   NormalMode,
 	})
 	require.NoError(t, err)
 
@@ -36,5 +37,12 @@ func TestListener(t *testing.T) {
 	assert.Equal(t, "127.0.0.1", host)
 	assert.NotZero(t, port)
 
+	addrs := l.TCPAddrs()
+	require.Len(t, addrs, 2)
+	assert.Equal(t, l.TCPAddr(), addrs[0])
+	assert.NotEqual(t, addrs[0].String(), addrs[1].String())
+
 	assert.Nil(t, l.UnixAddr())
+	assert.Nil(t, l.TLSAddr())
+	assert.Empty(t, l.TLSAddrs())
 }
@@ -0,0 +1,331 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package proxyproto implements server side of HAProxy's PROXY protocol versions 1 and 2.
+//
+// See https://www.haproxy.org/download/2.9/doc/proxy-protocol.txt.
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// headerTimeout is the maximum time to wait for the PROXY header.
+const headerTimeout = 10 * time.Second
+
+const (
+	// v1Prefix is the beginning of the version 1 (text) header.
+	v1Prefix = "PROXY "
+
+	// v1MaxLen is the maximum length of the version 1 header, including CRLF.
+	v1MaxLen = 107
+)
+
+// v2Sig is the signature of the version 2 (binary) header.
+var v2Sig = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// Listener wraps [net.Listener] and parses PROXY headers on accepted connections
+// from trusted sources.
+//
+// Connections from untrusted sources are returned as is,
+// so their PROXY headers (if any) are not parsed and cause protocol errors later.
+type Listener struct {
+	net.Listener
+	trusted []netip.Prefix
+}
+
+// NewListener returns a new Listener that trusts PROXY headers only from given source networks.
+func NewListener(l net.Listener, trusted []netip.Prefix) *Listener {
+	return &Listener{
+		Listener: l,
+		trusted:  trusted,
+	}
+}
+
+// Accept implements [net.Listener].
+//
+// It does not block on reading the header; that happens on the first
+// [Conn.Read], [Conn.RemoteAddr], or [Conn.LocalAddr] call.
+func (l *Listener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	if !l.isTrusted(c.RemoteAddr()) {
+		return c, nil
+	}
+
+	return &Conn{
+		Conn: c,
+		r:    bufio.NewReader(c),
+	}, nil
+}
+
+// isTrusted returns true if the given address belongs to one of trusted networks.
+func (l *Listener) isTrusted(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+
+	ip, ok := netip.AddrFromSlice(tcpAddr.IP)
+	if !ok {
+		return false
+	}
+
+	ip = ip.Unmap()
+
+	for _, p := range l.trusted {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Conn wraps [net.Conn] from a trusted source.
+//
+// Its RemoteAddr and LocalAddr return addresses from the PROXY header, if present.
+type Conn struct {
+	net.Conn
+	r *bufio.Reader
+
+	once   sync.Once
+	remote net.Addr
+	local  net.Addr
+	err    error
+}
+
+// Read implements [net.Conn].
+func (c *Conn) Read(b []byte) (int, error) {
+	c.once.Do(c.readHeader)
+
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	return c.r.Read(b)
+}
+
+// RemoteAddr implements [net.Conn].
+func (c *Conn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+
+	if c.remote != nil {
+		return c.remote
+	}
+
+	return c.Conn.RemoteAddr()
+}
+
+// LocalAddr implements [net.Conn].
+func (c *Conn) LocalAddr() net.Addr {
+	c.once.Do(c.readHeader)
+
+	if c.local != nil {
+		return c.local
+	}
+
+	return c.Conn.LocalAddr()
+}
+
+// readHeader reads and parses the PROXY header, if present.
+// It must be called only once.
+func (c *Conn) readHeader() {
+	if c.err = c.Conn.SetReadDeadline(time.Now().Add(headerTimeout)); c.err != nil {
+		return
+	}
+
+	c.remote, c.local, c.err = readHeader(c.r)
+
+	if err := c.Conn.SetReadDeadline(time.Time{}); err != nil && c.err == nil {
+		c.err = err
+	}
+}
+
+// readHeader reads the PROXY header of any version from r.
+//
+// If there is no header, nil addresses and nil error are returned, and nothing is consumed.
+// Nil addresses are also returned for headers without address information
+// (`UNKNOWN` in version 1, `LOCAL` command or unsupported family in version 2).
+func readHeader(r *bufio.Reader) (remote, local net.Addr, err error) {
+	b, err := r.Peek(1)
+	if err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	switch b[0] {
+	case v1Prefix[0]:
+		if b, err = r.Peek(len(v1Prefix)); err != nil {
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		if string(b) == v1Prefix {
+			return readV1(r)
+		}
+
+	case v2Sig[0]:
+		if b, err = r.Peek(len(v2Sig)); err != nil {
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		if bytes.Equal(b, v2Sig) {
+			return readV2(r)
+		}
+	}
+
+	return nil, nil, nil
+}
+
+// readV1 reads version 1 header.
+func readV1(r *bufio.Reader) (remote, local net.Addr, err error) {
+	var line []byte
+
+	for len(line) < v1MaxLen {
+		var b byte
+		if b, err = r.ReadByte(); err != nil {
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		line = append(line, b)
+
+		if bytes.HasSuffix(line, []byte("\r\n")) {
+			break
+		}
+	}
+
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, nil, lazyerrors.New("PROXY v1 header is too long")
+	}
+
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+
+	if len(fields) < 2 {
+		return nil, nil, lazyerrors.Errorf("invalid PROXY v1 header %q", line)
+	}
+
+	switch fields[1] {
+	case "UNKNOWN":
+		return nil, nil, nil
+
+	case "TCP4", "TCP6":
+		if len(fields) != 6 {
+			return nil, nil, lazyerrors.Errorf("invalid PROXY v1 header %q", line)
+		}
+
+		var src, dst netip.AddrPort
+
+		if src, err = parseV1Addr(fields[2], fields[4]); err != nil {
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		if dst, err = parseV1Addr(fields[3], fields[5]); err != nil {
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		return net.TCPAddrFromAddrPort(src), net.TCPAddrFromAddrPort(dst), nil
+
+	default:
+		return nil, nil, lazyerrors.Errorf("unsupported PROXY v1 protocol %q", fields[1])
+	}
+}
+
+// parseV1Addr parses address and port of version 1 header.
+func parseV1Addr(addr, port string) (netip.AddrPort, error) {
+	a, err := netip.ParseAddr(addr)
+	if err != nil {
+		return netip.AddrPort{}, lazyerrors.Error(err)
+	}
+
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return netip.AddrPort{}, lazyerrors.Error(err)
+	}
+
+	return netip.AddrPortFrom(a, uint16(p)), nil
+}
+
+// readV2 reads version 2 header.
+func readV2(r *bufio.Reader) (remote, local net.Addr, err error) {
+	var h [16]byte
+	if _, err = io.ReadFull(r, h[:]); err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	verCmd, fam := h[12], h[13]
+
+	if verCmd>>4 != 2 {
+		return nil, nil, lazyerrors.Errorf("unsupported PROXY v2 version %d", verCmd>>4)
+	}
+
+	body := make([]byte, binary.BigEndian.Uint16(h[14:]))
+	if _, err = io.ReadFull(r, body); err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	switch cmd := verCmd & 0x0f; cmd {
+	case 0x00: // LOCAL, for example, health checks
+		return nil, nil, nil
+	case 0x01: // PROXY
+	default:
+		return nil, nil, lazyerrors.Errorf("unsupported PROXY v2 command %d", cmd)
+	}
+
+	var n int
+
+	switch fam {
+	case 0x11: // TCP over IPv4
+		n = 4
+	case 0x21: // TCP over IPv6
+		n = 16
+	default:
+		// UDP, Unix sockets, and unspecified families are ignored
+		return nil, nil, nil
+	}
+
+	if len(body) < 2*n+4 {
+		return nil, nil, lazyerrors.Errorf("PROXY v2 address block is too short: %d", len(body))
+	}
+
+	// TLVs after addresses are ignored
+
+	src, _ := netip.AddrFromSlice(body[:n])
+	dst, _ := netip.AddrFromSlice(body[n : 2*n])
+	srcPort := binary.BigEndian.Uint16(body[2*n:])
+	dstPort := binary.BigEndian.Uint16(body[2*n+2:])
+
+	remote = net.TCPAddrFromAddrPort(netip.AddrPortFrom(src, srcPort))
+	local = net.TCPAddrFromAddrPort(netip.AddrPortFrom(dst, dstPort))
+
+	return remote, local, nil
+}
+
+// check interfaces
+var (
+	_ net.Listener = (*Listener)(nil)
+	_ net.Conn     = (*Conn)(nil)
+)
@@ -0,0 +1,188 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// v2Header returns version 2 header for TCP over IPv4.
+func v2Header(t *testing.T, cmd byte, src, dst netip.AddrPort) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	b.Write(v2Sig)
+	b.WriteByte(0x20 | cmd)
+	b.WriteByte(0x11)
+	require.NoError(t, binary.Write(&b, binary.BigEndian, uint16(12+3))) // addresses + empty TLV
+
+	s, d := src.Addr().As4(), dst.Addr().As4()
+	b.Write(s[:])
+	b.Write(d[:])
+	require.NoError(t, binary.Write(&b, binary.BigEndian, src.Port()))
+	require.NoError(t, binary.Write(&b, binary.BigEndian, dst.Port()))
+	b.Write([]byte{0x04, 0x00, 0x00}) // PP2_TYPE_NOOP
+
+	return b.Bytes()
+}
+
+func TestReadHeader(t *testing.T) {
+	t.Parallel()
+
+	src := netip.MustParseAddrPort("192.0.2.1:56324")
+	dst := netip.MustParseAddrPort("192.0.2.2:27017")
+
+	for name, tc := range map[string]struct {
+		header []byte
+		remote string
+		local  string
+		err    string
+	}{
+		"None": {
+			header: []byte{0x50, 0x00, 0x00, 0x00}, // same first byte as "PROXY"
+		},
+		"V1TCP4": {
+			header: []byte("PROXY TCP4 192.0.2.1 192.0.2.2 56324 27017\r\n"),
+			remote: "192.0.2.1:56324",
+			local:  "192.0.2.2:27017",
+		},
+		"V1TCP6": {
+			header: []byte("PROXY TCP6 2001:db8::1 2001:db8::2 56324 27017\r\n"),
+			remote: "[2001:db8::1]:56324",
+			local:  "[2001:db8::2]:27017",
+		},
+		"V1Unknown": {
+			header: []byte("PROXY UNKNOWN\r\n"),
+		},
+		"V1TooLong": {
+			header: []byte("PROXY TCP4 " + strings.Repeat("1", 200) + "\r\n"),
+			err:    "too long",
+		},
+		"V1Invalid": {
+			header: []byte("PROXY TCP4 192.0.2.1 56324\r\n"),
+			err:    "invalid PROXY v1 header",
+		},
+		"V2Proxy": {
+			header: v2Header(t, 0x01, src, dst),
+			remote: "192.0.2.1:56324",
+			local:  "192.0.2.2:27017",
+		},
+		"V2Local": {
+			header: v2Header(t, 0x00, src, dst),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			payload := []byte("payload")
+			r := bufio.NewReader(io.MultiReader(bytes.NewReader(tc.header), bytes.NewReader(payload)))
+
+			remote, local, err := readHeader(r)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tc.remote == "" {
+				assert.Nil(t, remote)
+				assert.Nil(t, local)
+			} else {
+				assert.Equal(t, tc.remote, remote.String())
+				assert.Equal(t, tc.local, local.String())
+			}
+
+			rest, err := io.ReadAll(r)
+			require.NoError(t, err)
+
+			if tc.remote == "" && !bytes.HasPrefix(tc.header, []byte(v1Prefix)) && !bytes.HasPrefix(tc.header, v2Sig) {
+				// nothing should be consumed
+				assert.Equal(t, append(tc.header, payload...), rest)
+				return
+			}
+
+			assert.Equal(t, payload, rest)
+		})
+	}
+}
+
+func TestListener(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		trusted string
+		remote  string
+	}{
+		"Trusted": {
+			trusted: "127.0.0.0/8",
+			remote:  "192.0.2.1:56324",
+		},
+		"Untrusted": {
+			trusted: "192.0.2.0/24",
+			remote:  "127.0.0.1",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tcp, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+
+			l := NewListener(tcp, []netip.Prefix{netip.MustParsePrefix(tc.trusted)})
+			t.Cleanup(func() { require.NoError(t, l.Close()) })
+
+			header := "PROXY TCP4 192.0.2.1 192.0.2.2 56324 27017\r\n"
+
+			go func() {
+				c, e := net.Dial("tcp", l.Addr().String())
+				if e != nil {
+					return
+				}
+
+				defer c.Close()
+
+				_, _ = c.Write([]byte(header + "payload"))
+			}()
+
+			c, err := l.Accept()
+			require.NoError(t, err)
+
+			defer c.Close()
+
+			assert.True(t, strings.HasPrefix(c.RemoteAddr().String(), tc.remote), "%s", c.RemoteAddr())
+
+			b, err := io.ReadAll(c)
+			require.NoError(t, err)
+
+			if tc.remote == "127.0.0.1" {
+				assert.Equal(t, header+"payload", string(b))
+				return
+			}
+
+			assert.Equal(t, "payload", string(b))
+		})
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package router decides which backend handles a request in routing mode.
+//
+// Authentication commands are handled by FerretDB, so requests sent to the proxy
+// are executed by unauthenticated upstream connections.
+// When authentication is enabled, the client connection rejects routed requests
+// of clients that did not authenticate with FerretDB.
+// The proxy should not require authentication for routed namespaces.
+package router
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// cursorTTL is the time after which pinned cursors are forgotten.
+// It should be larger than the cursor timeout of both backends.
+const cursorTTL = time.Hour
+
+// Target represents a backend that handles requests.
+type Target string
+
+const (
+	// TargetNormal handles requests by FerretDB.
+	TargetNormal Target = "normal"
+	// TargetProxy sends requests to another wire protocol compatible service.
+	TargetProxy Target = "proxy"
+)
+
+// Rule represents a single routing rule.
+type Rule struct {
+	// Database and Collection are glob patterns as accepted by [path.Match].
+	Database   string
+	Collection string
+	Target     Target
+}
+
+// ParseRule parses a rule in the `database[.collection]=target` format.
+// If collection pattern is omitted, rule matches all collections.
+func ParseRule(s string) (Rule, error) {
+	ns, target, ok := strings.Cut(s, "=")
+	if !ok {
+		return Rule{}, lazyerrors.Errorf("invalid rule %q: expected 'database[.collection]=target'", s)
+	}
+
+	db, coll, ok := strings.Cut(ns, ".")
+	if !ok {
+		coll = "*"
+	}
+
+	r := Rule{
+		Database:   db,
+		Collection: coll,
+		Target:     Target(target),
+	}
+
+	switch r.Target {
+	case TargetNormal, TargetProxy:
+	default:
+		return Rule{}, lazyerrors.Errorf("invalid rule %q: unknown target %q", s, target)
+	}
+
+	// check patterns syntax
+	for _, p := range []string{r.Database, r.Collection} {
+		if _, err := path.Match(p, ""); err != nil || p == "" {
+			return Rule{}, lazyerrors.Errorf("invalid rule %q: invalid pattern %q", s, p)
+		}
+	}
+
+	return r, nil
+}
+
+// String implements [fmt.Stringer].
+func (r Rule) String() string {
+	return fmt.Sprintf("%s.%s=%s", r.Database, r.Collection, r.Target)
+}
+
+// match returns true if the rule matches the given namespace.
+func (r Rule) match(db, coll string) bool {
+	if ok, _ := path.Match(r.Database, db); !ok {
+		return false
+	}
+
+	ok, _ := path.Match(r.Collection, coll)
+
+	return ok
+}
+
+// Router routes requests to backends by their namespace.
+//
+// Cursors created by the proxy are pinned to it;
+// `getMore` and `killCursors` requests for them are always sent to the proxy.
+// Pinned cursors are shared between all client connections, like in MongoDB.
+//
+// It is safe for concurrent use.
+type Router struct {
+	rules []Rule
+
+	m         sync.Mutex
+	cursors   map[int64]time.Time // cursors pinned to the proxy, with last use time
+	lastPurge time.Time
+}
+
+// New creates a new Router with the given rules.
+//
+// The first matching rule wins; requests that do not match any rule are handled by FerretDB.
+func New(rules []Rule) *Router {
+	return &Router{
+		rules:   rules,
+		cursors: map[int64]time.Time{},
+	}
+}
+
+// Route returns the target for the given request.
+//
+// Legacy OP_QUERY requests are only used for the handshake; they are always handled by FerretDB.
+func (r *Router) Route(body wire.MsgBody) Target {
+	doc := section0(body)
+	if doc == nil {
+		return TargetNormal
+	}
+
+	switch doc.Command() {
+	case "authenticate", "logout", "saslContinue", "saslStart":
+		return TargetNormal
+
+	case "getMore":
+		id, _ := doc.Get("getMore").(int64)
+		if r.pinned(id) {
+			return TargetProxy
+		}
+
+		return TargetNormal
+
+	case "killCursors":
+		ids, _ := doc.Get("cursors").(wirebson.AnyArray)
+		if ids == nil {
+			break
+		}
+
+		arr, err := ids.Decode()
+		if err != nil {
+			break
+		}
+
+		for v := range arr.Values() {
+			if id, _ := v.(int64); r.pinned(id) {
+				return TargetProxy
+			}
+		}
+
+		return TargetNormal
+	}
+
+	db, _ := doc.Get("$db").(string)
+	coll, _ := doc.Get(doc.Command()).(string)
+
+	for _, rule := range r.rules {
+		if rule.match(db, coll) {
+			return rule.Target
+		}
+	}
+
+	return TargetNormal
+}
+
+// Track updates pinned cursors for the request sent to the proxy and its response.
+func (r *Router) Track(reqBody, resBody wire.MsgBody) {
+	req := section0(reqBody)
+	res := section0(resBody)
+
+	if req == nil || res == nil {
+		return
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(r.lastPurge) > time.Minute {
+		for id, t := range r.cursors {
+			if now.Sub(t) > cursorTTL {
+				delete(r.cursors, id)
+			}
+		}
+
+		r.lastPurge = now
+	}
+
+	if req.Command() == "killCursors" {
+		if ids, _ := req.Get("cursors").(wirebson.AnyArray); ids != nil {
+			if arr, err := ids.Decode(); err == nil {
+				for v := range arr.Values() {
+					id, _ := v.(int64)
+					delete(r.cursors, id)
+				}
+			}
+		}
+
+		return
+	}
+
+	cursor, _ := res.Get("cursor").(wirebson.AnyDocument)
+	if cursor == nil {
+		return
+	}
+
+	cursorDoc, err := cursor.Decode()
+	if err != nil {
+		return
+	}
+
+	id, _ := cursorDoc.Get("id").(int64)
+	if id != 0 {
+		r.cursors[id] = now
+		return
+	}
+
+	// cursor is exhausted
+	if req.Command() == "getMore" {
+		reqID, _ := req.Get("getMore").(int64)
+		delete(r.cursors, reqID)
+	}
+}
+
+// pinned returns true if the cursor with the given ID is pinned to the proxy.
+func (r *Router) pinned(id int64) bool {
+	if id == 0 {
+		return false
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	_, ok := r.cursors[id]
+
+	return ok
+}
+
+// section0 returns the decoded section 0 of the OP_MSG body, or nil.
+func section0(body wire.MsgBody) *wirebson.Document {
+	msg, ok := body.(*wire.OpMsg)
+	if !ok {
+		return nil
+	}
+
+	doc, err := msg.Section0()
+	if err != nil {
+		return nil
+	}
+
+	return doc
+}
+
+// check interfaces
+var (
+	_ fmt.Stringer = Rule{}
+)
@@ -0,0 +1,152 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRule(t *testing.T) {
+	t.Parallel()
+
+	for s, tc := range map[string]struct {
+		expected Rule
+		err      bool
+	}{
+		"db=proxy": {
+			expected: Rule{Database: "db", Collection: "*", Target: TargetProxy},
+		},
+		"db.coll=normal": {
+			expected: Rule{Database: "db", Collection: "coll", Target: TargetNormal},
+		},
+		"logs_*.a.b=proxy": {
+			expected: Rule{Database: "logs_*", Collection: "a.b", Target: TargetProxy},
+		},
+		"db":           {err: true},
+		"db=mongodb":   {err: true},
+		"[.coll=proxy": {err: true},
+		"=proxy":       {err: true},
+	} {
+		t.Run(s, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := ParseRule(s)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestRoute(t *testing.T) {
+	t.Parallel()
+
+	var rules []Rule
+
+	for _, s := range []string{"sales.orders=normal", "sales=proxy", "logs_*=proxy"} {
+		rule, err := ParseRule(s)
+		require.NoError(t, err)
+
+		rules = append(rules, rule)
+	}
+
+	r := New(rules)
+
+	for name, tc := range map[string]struct {
+		body     wire.MsgBody
+		expected Target
+	}{
+		"Collection": {
+			body:     wire.MustOpMsg("find", "orders", "$db", "sales"),
+			expected: TargetNormal,
+		},
+		"Database": {
+			body:     wire.MustOpMsg("insert", "customers", "$db", "sales"),
+			expected: TargetProxy,
+		},
+		"DatabaseCommand": {
+			body:     wire.MustOpMsg("listCollections", int32(1), "$db", "sales"),
+			expected: TargetProxy,
+		},
+		"Glob": {
+			body:     wire.MustOpMsg("find", "events", "$db", "logs_2025"),
+			expected: TargetProxy,
+		},
+		"Unmatched": {
+			body:     wire.MustOpMsg("find", "events", "$db", "test"),
+			expected: TargetNormal,
+		},
+		"OpQuery": {
+			body:     wire.MustOpQuery("isMaster", int32(1)),
+			expected: TargetNormal,
+		},
+		"Auth": {
+			body:     wire.MustOpMsg("saslStart", int32(1), "$db", "sales"),
+			expected: TargetNormal,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, r.Route(tc.body))
+		})
+	}
+}
+
+func TestCursors(t *testing.T) {
+	t.Parallel()
+
+	r := New([]Rule{{Database: "proxied", Collection: "*", Target: TargetProxy}})
+
+	find := wire.MustOpMsg("find", "test", "$db", "proxied")
+	require.Equal(t, TargetProxy, r.Route(find))
+
+	r.Track(find, wire.MustOpMsg(
+		"cursor", wirebson.MustDocument("firstBatch", wirebson.MakeArray(0), "id", int64(42), "ns", "proxied.test"),
+		"ok", float64(1),
+	))
+
+	// getMore does not contain $db of the original request
+	getMore := wire.MustOpMsg("getMore", int64(42), "collection", "test", "$db", "other")
+	assert.Equal(t, TargetProxy, r.Route(getMore))
+	assert.Equal(t, TargetNormal, r.Route(wire.MustOpMsg("getMore", int64(43), "collection", "test", "$db", "proxied")))
+
+	r.Track(getMore, wire.MustOpMsg(
+		"cursor", wirebson.MustDocument("nextBatch", wirebson.MakeArray(0), "id", int64(0), "ns", "proxied.test"),
+		"ok", float64(1),
+	))
+
+	assert.Equal(t, TargetNormal, r.Route(getMore))
+
+	r.Track(find, wire.MustOpMsg(
+		"cursor", wirebson.MustDocument("firstBatch", wirebson.MakeArray(0), "id", int64(43), "ns", "proxied.test"),
+		"ok", float64(1),
+	))
+
+	killCursors := wire.MustOpMsg("killCursors", "test", "cursors", wirebson.MustArray(int64(43)), "$db", "other")
+	assert.Equal(t, TargetProxy, r.Route(killCursors))
+
+	r.Track(killCursors, wire.MustOpMsg("cursorsKilled", wirebson.MustArray(int64(43)), "ok", float64(1)))
+	assert.Equal(t, TargetNormal, r.Route(killCursors))
+}
@@ -0,0 +1,373 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shadow mirrors client requests to another wire protocol compatible service asynchronously.
+package shadow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+const (
+	namespace = "ferretdb"
+	subsystem = "shadow"
+)
+
+const (
+	// defaultQueueSize is the default number of read or write requests waiting to be mirrored.
+	defaultQueueSize = 1000
+
+	// defaultWorkers is the default number of read requests mirrored concurrently.
+	defaultWorkers = 4
+
+	// requestTimeout is the timeout of a single mirrored request.
+	requestTimeout = 30 * time.Second
+)
+
+// Filter represents a kind of requests to mirror.
+type Filter string
+
+const (
+	// FilterAll mirrors all supported requests.
+	FilterAll Filter = "all"
+	// FilterReads mirrors only read requests.
+	FilterReads Filter = "reads"
+	// FilterWrites mirrors only write requests.
+	FilterWrites Filter = "writes"
+)
+
+// AllFilters includes all filters, with the first one being the default.
+var AllFilters = []string{
+	string(FilterAll),
+	string(FilterReads),
+	string(FilterWrites),
+}
+
+// writeCommands contains commands that modify data or metadata.
+//
+// Transaction commands are included, so mirrored transactional writes are committed or aborted.
+var writeCommands = []string{
+	"abortTransaction",
+	"collMod",
+	"commitTransaction",
+	"compact",
+	"create",
+	"createIndexes",
+	"createSearchIndexes",
+	"createUser",
+	"delete",
+	"drop",
+	"dropAllUsersFromDatabase",
+	"dropDatabase",
+	"dropIndexes",
+	"dropSearchIndex",
+	"dropUser",
+	"findAndModify",
+	"insert",
+	"reIndex",
+	"renameCollection",
+	"update",
+	"updateSearchIndex",
+	"updateUser",
+}
+
+// skippedCommands contains commands that are never mirrored
+// because they depend on the client connection's state
+// (cursors, sessions, authentication) that does not exist on the shadow.
+var skippedCommands = []string{
+	"authenticate",
+	"endSessions",
+	"getMore",
+	"killCursors",
+	"killSessions",
+	"logout",
+	"saslContinue",
+	"saslStart",
+}
+
+// item represents a single request waiting to be mirrored.
+type item struct {
+	header  *wire.MsgHeader
+	body    *wire.OpMsg
+	command string
+}
+
+// Mirror sends copies of client requests to another wire protocol compatible service
+// without waiting for responses.
+//
+// Requests are placed into bounded queues; they are dropped when the queue is full.
+// Write requests are always selected and sent one by one in order by a single worker,
+// so the target applies them in the same order.
+// Read requests are sampled and sent concurrently by several workers.
+// Responses are discarded.
+//
+// The target should not require authentication, because mirrored requests are sent
+// over shared unauthenticated connections.
+//
+// It is safe for concurrent use.
+type Mirror struct {
+	l          *slog.Logger
+	handler    middleware.HandleFunc
+	queue      chan *item // read requests
+	writes     chan *item // write requests
+	workers    int
+	sampleRate float64
+	filter     Filter
+
+	requests *prometheus.CounterVec
+	queued   prometheus.GaugeFunc
+}
+
+// NewOpts represents [New] options.
+//
+//nolint:vet // for readability
+type NewOpts struct {
+	L       *slog.Logger
+	Handler middleware.HandleFunc // usually proxy handler
+
+	QueueSize  int     // size of read and write queues; defaults to 1000
+	Workers    int     // number of concurrent read workers; defaults to 4
+	SampleRate float64 // fraction of read requests to mirror, greater than 0 and up to 1
+	Filter     Filter  // defaults to FilterAll
+}
+
+// New creates a new Mirror.
+//
+// [Mirror.Run] must be called on the returned value.
+func New(opts *NewOpts) (*Mirror, error) {
+	must.NotBeZero(opts)
+
+	if opts.Handler == nil {
+		return nil, lazyerrors.New("handler is required")
+	}
+
+	// zero value is likely a mistake, not a request to mirror nothing
+	if opts.SampleRate <= 0 || opts.SampleRate > 1 {
+		return nil, lazyerrors.Errorf("invalid sample rate %v, must be greater than 0 and not greater than 1", opts.SampleRate)
+	}
+
+	filter := opts.Filter
+	if filter == "" {
+		filter = FilterAll
+	}
+
+	if !slices.Contains(AllFilters, string(filter)) {
+		return nil, lazyerrors.Errorf("invalid filter %q", filter)
+	}
+
+	queueSize := opts.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	m := &Mirror{
+		l:          opts.L,
+		handler:    opts.Handler,
+		queue:      make(chan *item, queueSize),
+		writes:     make(chan *item, queueSize),
+		workers:    workers,
+		sampleRate: opts.SampleRate,
+		filter:     filter,
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "requests_total",
+				Help:      "Total number of requests selected for mirroring by result.",
+			},
+			[]string{"command", "result"},
+		),
+	}
+
+	m.queued = prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "queue_length",
+			Help:      "The current number of requests waiting to be mirrored.",
+		},
+		func() float64 { return float64(len(m.queue) + len(m.writes)) },
+	)
+
+	return m, nil
+}
+
+// Run mirrors queued requests until ctx is canceled.
+//
+// Requests that are still queued when ctx is canceled are dropped.
+func (m *Mirror) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	// a single worker for writes keeps their order
+	queues := []chan *item{m.writes}
+	for range m.workers {
+		queues = append(queues, m.queue)
+	}
+
+	for _, queue := range queues {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case it := <-queue:
+					m.send(ctx, it)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if n := len(m.queue) + len(m.writes); n > 0 {
+		m.l.InfoContext(ctx, fmt.Sprintf("Dropped %d queued requests on shutdown", n))
+	}
+}
+
+// Mirror queues a copy of the request for mirroring if it passes the filter and, for reads, sampling.
+// It never blocks; if the queue is full, the request is dropped.
+//
+// It should be called before the request is handled,
+// because handling could modify request documents.
+func (m *Mirror) Mirror(ctx context.Context, header *wire.MsgHeader, body wire.MsgBody) {
+	msg, ok := body.(*wire.OpMsg)
+	if !ok {
+		// legacy OP_QUERY requests are only used for the handshake
+		return
+	}
+
+	doc, err := msg.Section0()
+	if err != nil {
+		m.l.DebugContext(ctx, "Failed to decode request", logging.Error(err))
+		return
+	}
+
+	command := doc.Command()
+
+	if !m.selected(command, doc) {
+		return
+	}
+
+	// sampled writes would make the target diverge
+	queue := m.writes
+	if !isWrite(command, doc) {
+		if m.sampleRate < 1 && rand.Float64() >= m.sampleRate {
+			return
+		}
+
+		queue = m.queue
+	}
+
+	b, err := msg.MarshalBinary()
+	if err != nil {
+		m.l.DebugContext(ctx, "Failed to copy request", logging.Error(err))
+		return
+	}
+
+	var bodyCopy wire.OpMsg
+	if err = bodyCopy.UnmarshalBinaryNocopy(b); err != nil {
+		m.l.DebugContext(ctx, "Failed to copy request", logging.Error(err))
+		return
+	}
+
+	headerCopy := *header
+
+	select {
+	case queue <- &item{header: &headerCopy, body: &bodyCopy, command: command}:
+	default:
+		m.requests.WithLabelValues(command, "dropped").Inc()
+	}
+}
+
+// selected returns true if the command passes the filter.
+func (m *Mirror) selected(command string, doc *wirebson.Document) bool {
+	if command == "" || slices.Contains(skippedCommands, command) {
+		return false
+	}
+
+	switch m.filter {
+	case FilterReads:
+		return !isWrite(command, doc)
+	case FilterWrites:
+		return isWrite(command, doc)
+	default:
+		return true
+	}
+}
+
+// isWrite returns true if the command modifies data or metadata.
+func isWrite(command string, doc *wirebson.Document) bool {
+	if slices.Contains(writeCommands, command) {
+		return true
+	}
+
+	// aggregation pipelines with $out or $merge stages are writes
+	return middleware.AggregateWrites(doc)
+}
+
+// send sends a single request, discarding the response.
+func (m *Mirror) send(ctx context.Context, it *item) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	if _, err := m.handler(ctx, middleware.RequestWire(it.header, it.body)); err != nil {
+		m.requests.WithLabelValues(it.command, "failed").Inc()
+		m.l.DebugContext(ctx, "Failed to mirror request", slog.String("command", it.command), logging.Error(err))
+
+		return
+	}
+
+	m.requests.WithLabelValues(it.command, "mirrored").Inc()
+}
+
+// Describe implements [prometheus.Collector].
+func (m *Mirror) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.queued.Describe(ch)
+}
+
+// Collect implements [prometheus.Collector].
+func (m *Mirror) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.queued.Collect(ch)
+}
+
+// check interfaces
+var (
+	_ prometheus.Collector = (*Mirror)(nil)
+)
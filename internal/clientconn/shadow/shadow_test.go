@@ -0,0 +1,276 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadow
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	fdbtestutil "github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+// request returns a request header and body for the given command document.
+func request(t *testing.T, pairs ...any) (*wire.MsgHeader, *wire.OpMsg) {
+	t.Helper()
+
+	msg, err := wire.NewOpMsg(wirebson.MustDocument(pairs...))
+	require.NoError(t, err)
+
+	b, err := msg.MarshalBinary()
+	require.NoError(t, err)
+
+	header := &wire.MsgHeader{
+		MessageLength: int32(wire.MsgHeaderLen + len(b)),
+		RequestID:     1,
+		OpCode:        wire.OpCodeMsg,
+	}
+
+	return header, msg
+}
+
+func TestMirrorFilter(t *testing.T) {
+	t.Parallel()
+
+	ctx := fdbtestutil.Ctx(t)
+
+	requests := [][]any{
+		{"insert", "test", "$db", "db"},
+		{"find", "test", "$db", "db"},
+		{"aggregate", "test", "pipeline", wirebson.MustArray(wirebson.MustDocument("$out", "out")), "$db", "db"},
+		{"aggregate", "test", "pipeline", wirebson.MustArray(wirebson.MustDocument("$match", wirebson.MakeDocument(0))), "$db", "db"},
+		{"getMore", int64(1), "collection", "test", "$db", "db"},
+		{"saslStart", int32(1), "$db", "admin"},
+	}
+
+	for name, tc := range map[string]struct {
+		filter Filter
+		reads  []string
+		writes []string
+	}{
+		"All": {
+			filter: FilterAll,
+			reads:  []string{"find", "aggregate"},
+			writes: []string{"insert", "aggregate"},
+		},
+		"Reads": {
+			filter: FilterReads,
+			reads:  []string{"find", "aggregate"},
+		},
+		"Writes": {
+			filter: FilterWrites,
+			writes: []string{"insert", "aggregate"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := New(&NewOpts{
+				L:          fdbtestutil.Logger(t),
+				Handler:    func(context.Context, *middleware.Request) (*middleware.Response, error) { panic("not reached") },
+				QueueSize:  len(requests),
+				SampleRate: 1,
+				Filter:     tc.filter,
+			})
+			require.NoError(t, err)
+
+			for _, pairs := range requests {
+				header, body := request(t, pairs...)
+				m.Mirror(ctx, header, body)
+			}
+
+			var reads, writes []string
+
+			for len(m.queue) > 0 {
+				reads = append(reads, (<-m.queue).command)
+			}
+
+			for len(m.writes) > 0 {
+				writes = append(writes, (<-m.writes).command)
+			}
+
+			assert.Equal(t, tc.reads, reads)
+			assert.Equal(t, tc.writes, writes)
+		})
+	}
+}
+
+func TestMirrorRun(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(fdbtestutil.Ctx(t))
+
+	var mu sync.Mutex
+	var handled []*wirebson.Document
+
+	done := make(chan struct{})
+
+	m, err := New(&NewOpts{
+		L: fdbtestutil.Logger(t),
+		Handler: func(_ context.Context, req *middleware.Request) (*middleware.Response, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			doc, err := req.OpMsg.Document()
+			if err != nil {
+				return nil, err
+			}
+
+			handled = append(handled, doc)
+			if len(handled) == 2 {
+				close(done)
+			}
+
+			return middleware.ResponseMsg(wirebson.MustDocument("ok", float64(1)))
+		},
+		QueueSize:  2,
+		SampleRate: 1,
+	})
+	require.NoError(t, err)
+
+	header, body := request(t, "insert", "test", "$db", "db")
+	m.Mirror(ctx, header, body)
+	m.Mirror(ctx, header, body)
+	m.Mirror(ctx, header, body) // dropped
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.requests.WithLabelValues("insert", "dropped")))
+
+	runDone := make(chan struct{})
+
+	go func() {
+		defer close(runDone)
+		m.Run(ctx)
+	}()
+
+	<-done
+	cancel()
+	<-runDone
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Len(t, handled, 2)
+	assert.Equal(t, "test", handled[0].Get("insert"))
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.requests.WithLabelValues("insert", "mirrored")))
+}
+
+func TestMirrorWriteOrder(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(fdbtestutil.Ctx(t))
+
+	const n = 100
+
+	var mu sync.Mutex
+	var handled []int32
+
+	done := make(chan struct{})
+
+	m, err := New(&NewOpts{
+		L: fdbtestutil.Logger(t),
+		Handler: func(_ context.Context, req *middleware.Request) (*middleware.Response, error) {
+			doc, err := req.OpMsg.Document()
+			if err != nil {
+				return nil, err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			handled = append(handled, doc.Get("n").(int32))
+			if len(handled) == n {
+				close(done)
+			}
+
+			return middleware.ResponseMsg(wirebson.MustDocument("ok", float64(1)))
+		},
+		QueueSize:  n,
+		Workers:    8,
+		SampleRate: 1,
+	})
+	require.NoError(t, err)
+
+	runDone := make(chan struct{})
+
+	go func() {
+		defer close(runDone)
+		m.Run(ctx)
+	}()
+
+	expected := make([]int32, n)
+
+	for i := range int32(n) {
+		expected[i] = i
+
+		header, body := request(t, "update", "test", "n", i, "$db", "db")
+		m.Mirror(ctx, header, body)
+	}
+
+	<-done
+	cancel()
+	<-runDone
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, expected, handled)
+}
+
+func TestMirrorSampling(t *testing.T) {
+	t.Parallel()
+
+	m, err := New(&NewOpts{
+		L:          fdbtestutil.Logger(t),
+		Handler:    func(context.Context, *middleware.Request) (*middleware.Response, error) { panic("not reached") },
+		SampleRate: math.SmallestNonzeroFloat64,
+	})
+	require.NoError(t, err)
+
+	header, body := request(t, "find", "test", "$db", "db")
+	for range 100 {
+		m.Mirror(fdbtestutil.Ctx(t), header, body)
+	}
+
+	assert.Empty(t, m.queue)
+
+	// writes are never sampled
+	header, body = request(t, "insert", "test", "$db", "db")
+	for range 100 {
+		m.Mirror(fdbtestutil.Ctx(t), header, body)
+	}
+
+	assert.Len(t, m.writes, 100)
+
+	_, err = New(&NewOpts{
+		L:          fdbtestutil.Logger(t),
+		Handler:    m.handler,
+		SampleRate: 2,
+	})
+	assert.Error(t, err)
+
+	_, err = New(&NewOpts{
+		L:       fdbtestutil.Logger(t),
+		Handler: m.handler,
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,199 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+const (
+	// redactedValue replaces values in slow operation log entries.
+	redactedValue = "###"
+
+	// redactedArrayLen is the maximum number of array elements in slow operation log entries.
+	redactedArrayLen = 10
+)
+
+// logSlowOp logs the request that took longer than the slow operation threshold,
+// similarly to MongoDB's "Slow query" log entries.
+//
+// Only the command name, collection and database values are logged as is;
+// all other values in the command and filter are redacted, but field names are kept.
+// Plan summary is computed by [handler.Handler.PlanSummary] without executing the command again;
+// it is skipped if the pool is busy.
+// This method is called in a separate goroutine, so it does not delay the response.
+func (c *conn) logSlowOp(ctx context.Context, db string, doc *wirebson.Document, d time.Duration, resLen int, result string) {
+	command := doc.Command()
+
+	ns := db
+	if coll, _ := doc.Get(command).(string); coll != "" {
+		ns += "." + coll
+	}
+
+	attrs := []slog.Attr{
+		slog.String("type", "command"),
+		slog.String("ns", ns),
+		slog.Any("command", redactCommand(doc)),
+	}
+
+	if filter := slowOpFilter(doc); filter != nil {
+		attrs = append(attrs, slog.Any("filter", redact(filter)))
+	}
+
+	if !c.h.Pool.Busy() {
+		if summary := c.h.PlanSummary(ctx, doc); summary != "" {
+			attrs = append(attrs, slog.String("planSummary", summary))
+		}
+	}
+
+	attrs = append(attrs,
+		slog.Int64("durationMillis", d.Milliseconds()),
+		slog.Int("reslen", resLen),
+		slog.String("result", result),
+	)
+
+	c.l.LogAttrs(ctx, slog.LevelWarn, "Slow query", attrs...)
+}
+
+// slowOpFilter returns the query filter of the given command, or nil.
+//
+// For write commands, the filter of the first statement is returned.
+// For aggregations, the first `$match` stage is returned.
+func slowOpFilter(doc *wirebson.Document) wirebson.AnyDocument {
+	switch doc.Command() {
+	case "find":
+		filter, _ := doc.Get("filter").(wirebson.AnyDocument)
+		return filter
+
+	case "count", "distinct", "findAndModify", "findandmodify":
+		filter, _ := doc.Get("query").(wirebson.AnyDocument)
+		return filter
+
+	case "update", "delete":
+		key := "updates"
+		if doc.Command() == "delete" {
+			key = "deletes"
+		}
+
+		stmt := firstDocument(doc.Get(key))
+		if stmt == nil {
+			return nil
+		}
+
+		filter, _ := stmt.Get("q").(wirebson.AnyDocument)
+		return filter
+
+	case "aggregate":
+		stage := firstDocument(doc.Get("pipeline"))
+		if stage == nil {
+			return nil
+		}
+
+		filter, _ := stage.Get("$match").(wirebson.AnyDocument)
+		return filter
+
+	default:
+		return nil
+	}
+}
+
+// firstDocument returns the first element of the given array if it is a document, or nil.
+func firstDocument(v any) *wirebson.Document {
+	arr, _ := v.(wirebson.AnyArray)
+	if arr == nil {
+		return nil
+	}
+
+	a, err := arr.Decode()
+	if err != nil || a.Len() == 0 {
+		return nil
+	}
+
+	d, _ := a.Get(0).(wirebson.AnyDocument)
+	if d == nil {
+		return nil
+	}
+
+	doc, err := d.Decode()
+	if err != nil {
+		return nil
+	}
+
+	return doc
+}
+
+// redactCommand returns a copy of the command document with redacted values,
+// except the command name value (usually the collection name) and `$db`.
+func redactCommand(doc *wirebson.Document) *wirebson.Document {
+	res := wirebson.MakeDocument(doc.Len())
+
+	for i := range doc.Len() {
+		k, v := doc.GetByIndex(i)
+
+		if i > 0 && k != "$db" {
+			v = redact(v)
+		}
+
+		must.NoError(res.Add(k, v))
+	}
+
+	return res
+}
+
+// redact returns a copy of the given value with all scalar values replaced,
+// keeping field names and the structure of documents and arrays.
+// Long arrays are truncated.
+func redact(v any) any {
+	switch v := v.(type) {
+	case wirebson.AnyDocument:
+		doc, err := v.Decode()
+		if err != nil {
+			return redactedValue
+		}
+
+		res := wirebson.MakeDocument(doc.Len())
+		for k, fv := range doc.All() {
+			must.NoError(res.Add(k, redact(fv)))
+		}
+
+		return res
+
+	case wirebson.AnyArray:
+		arr, err := v.Decode()
+		if err != nil {
+			return redactedValue
+		}
+
+		res := wirebson.MakeArray(min(arr.Len(), redactedArrayLen))
+		for i, ev := range arr.All() {
+			if i == redactedArrayLen {
+				break
+			}
+
+			must.NoError(res.Add(redact(ev)))
+		}
+
+		return res
+
+	default:
+		return redactedValue
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlowOpRedact(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		doc    *wirebson.Document
+		cmd    *wirebson.Document
+		filter *wirebson.Document
+	}{
+		"Find": {
+			doc: wirebson.MustDocument(
+				"find", "values",
+				"filter", wirebson.MustDocument("v", wirebson.MustDocument("$gt", int32(42))),
+				"limit", int64(1),
+				"$db", "test",
+			),
+			cmd: wirebson.MustDocument(
+				"find", "values",
+				"filter", wirebson.MustDocument("v", wirebson.MustDocument("$gt", "###")),
+				"limit", "###",
+				"$db", "test",
+			),
+			filter: wirebson.MustDocument("v", wirebson.MustDocument("$gt", "###")),
+		},
+		"Delete": {
+			doc: wirebson.MustDocument(
+				"delete", "values",
+				"deletes", wirebson.MustArray(
+					wirebson.MustDocument("q", wirebson.MustDocument("v", "secret"), "limit", int32(1)),
+				),
+				"$db", "test",
+			),
+			cmd: wirebson.MustDocument(
+				"delete", "values",
+				"deletes", wirebson.MustArray(
+					wirebson.MustDocument("q", wirebson.MustDocument("v", "###"), "limit", "###"),
+				),
+				"$db", "test",
+			),
+			filter: wirebson.MustDocument("v", "###"),
+		},
+		"Aggregate": {
+			doc: wirebson.MustDocument(
+				"aggregate", "values",
+				"pipeline", wirebson.MustArray(
+					wirebson.MustDocument("$match", wirebson.MustDocument("v", wirebson.MustArray(int32(1), int32(2)))),
+				),
+				"$db", "test",
+			),
+			cmd: wirebson.MustDocument(
+				"aggregate", "values",
+				"pipeline", wirebson.MustArray(
+					wirebson.MustDocument("$match", wirebson.MustDocument("v", wirebson.MustArray("###", "###"))),
+				),
+				"$db", "test",
+			),
+			filter: wirebson.MustDocument("v", wirebson.MustArray("###", "###")),
+		},
+		"CreateUser": {
+			doc: wirebson.MustDocument(
+				"createUser", "user",
+				"pwd", "password",
+				"$db", "admin",
+			),
+			cmd: wirebson.MustDocument(
+				"createUser", "user",
+				"pwd", "###",
+				"$db", "admin",
+			),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.cmd, redactCommand(tc.doc))
+
+			filter := slowOpFilter(tc.doc)
+			if tc.filter == nil {
+				assert.Nil(t, filter)
+				return
+			}
+
+			require.NotNil(t, filter)
+			assert.Equal(t, tc.filter, redact(filter))
+		})
+	}
+
+	t.Run("LongArray", func(t *testing.T) {
+		t.Parallel()
+
+		arr := wirebson.MakeArray(20)
+		for i := range 20 {
+			require.NoError(t, arr.Add(int32(i)))
+		}
+
+		assert.Equal(t, redactedArrayLen, redact(arr).(*wirebson.Array).Len())
+	})
+}
@@ -257,7 +257,7 @@ func setupDataAPI(tb testing.TB, auth bool) (addr string, dbName string) {
 		Handler: h,
 		Metrics: connmetrics.NewListenerMetrics(),
 		Logger:  logging.WithName(l, "listener"),
-		TCP:     "127.0.0.1:0",
+		TCP:     []string{"127.0.0.1:0"},
 		Mode:    clientconn.NormalMode,
 	}
 
@@ -21,6 +21,7 @@
 
 	"github.com/FerretDB/wire/wirebson"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 	"github.com/FerretDB/FerretDB/v2/internal/util/resource"
@@ -31,22 +32,30 @@ type cursor struct {
 	// the order of fields is weird to make the struct smaller due to alignment
 
 	created      time.Time
+	lastUsed     time.Time
 	token        *resource.Token
-	conn         *pgx.Conn // only if persisted/hijacked
+	conn         *pgx.Conn     // only if persisted/hijacked
+	pool         *pgxpool.Pool // primary or replica pool the cursor was created from
 	continuation wirebson.RawDocument
+	maxIdle      time.Duration // if not zero, limits the idle timeout given to [Registry.CloseIdle]
+	noTimeout    bool          // not closed by [Registry.CloseIdle]
 }
 
-// newCursor creates a new cursor for the given continuation and connection (if any).
-func newCursor(continuation wirebson.RawDocument, conn *pgx.Conn) *cursor {
+// newCursor creates a new cursor for the given continuation, connection (if any), and pool.
+func newCursor(continuation wirebson.RawDocument, conn *pgx.Conn, pool *pgxpool.Pool, noTimeout bool) *cursor {
 	must.BeTrue(len(continuation) > 0)
 
 	res := &cursor{
 		continuation: continuation,
 		conn:         conn,
+		pool:         pool,
+		noTimeout:    noTimeout,
 		token:        resource.NewToken(),
 		created:      time.Now(),
 	}
 
+	res.lastUsed = res.created
+
 	resource.Track(res, res.token)
 
 	return res
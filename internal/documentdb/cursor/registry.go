@@ -22,6 +22,7 @@
 
 	"github.com/FerretDB/wire/wirebson"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
@@ -38,8 +39,9 @@
 //
 //nolint:vet // for readability
 type Registry struct {
-	rw      sync.RWMutex
-	cursors map[int64]*cursor
+	rw        sync.RWMutex
+	cursors   map[int64]*cursor
+	snapshots map[uint64]*snapshot // WAL location -> exported snapshot
 
 	l     *slog.Logger
 	token *resource.Token
@@ -51,9 +53,10 @@ type Registry struct {
 // NewRegistry creates a new cursor registry.
 func NewRegistry(l *slog.Logger) *Registry {
 	res := &Registry{
-		cursors: map[int64]*cursor{},
-		l:       l,
-		token:   resource.NewToken(),
+		cursors:   map[int64]*cursor{},
+		snapshots: map[uint64]*snapshot{},
+		l:         l,
+		token:     resource.NewToken(),
 
 		created: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
@@ -102,20 +105,88 @@ func (r *Registry) Close(ctx context.Context) {
 	r.rw.Lock()
 	defer r.rw.Unlock()
 
-	for id := range r.cursors {
-		r.closeCursor(ctx, id)
-	}
+	r.closeAll(ctx)
 
 	r.cursors = nil
+	r.snapshots = nil
 
 	resource.Untrack(r, r.token)
 }
 
-// NewCursor stores a cursor with given continuation and connection (if any).
+// CloseAll closes all cursors and snapshots in the registry and returns the number of closed cursors.
+// Unlike [Registry.Close], the registry remains usable.
+func (r *Registry) CloseAll(ctx context.Context) int {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	return r.closeAll(ctx)
+}
+
+// closeAll closes all cursors and snapshots without locking.
+func (r *Registry) closeAll(ctx context.Context) int {
+	r.closeSnapshots(ctx, -1)
+
+	var n int
+
+	for id := range r.cursors {
+		if r.closeCursor(ctx, id) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// CloseIdle closes cursors and snapshots that were not used for the given duration
+// and returns the number of closed cursors.
+// Cursors created with noTimeout are not closed.
+// Snapshot cursors are closed after a shorter duration if the given one is longer.
+func (r *Registry) CloseIdle(ctx context.Context, timeout time.Duration) int {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	r.closeSnapshots(ctx, timeout)
+
+	var n int
+
+	for id, c := range r.cursors {
+		t := timeout
+		if c.maxIdle > 0 {
+			t = min(t, c.maxIdle)
+		}
+
+		if c.noTimeout || time.Since(c.lastUsed) <= t {
+			continue
+		}
+
+		if r.closeCursor(ctx, id) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// NewCursor stores a cursor with given continuation, connection (if any),
+// and the pool it was created from, so the next pages are fetched from the same primary or replica.
+// If noTimeout is true, the cursor is not closed by [Registry.CloseIdle].
 //
 // As a special case, if continuation is empty, this method does nothing.
 // That simplifies the typical usage.
-func (r *Registry) NewCursor(id int64, continuation wirebson.RawDocument, conn *pgx.Conn) {
+func (r *Registry) NewCursor(id int64, continuation wirebson.RawDocument, conn *pgx.Conn, pool *pgxpool.Pool, noTimeout bool) { //nolint:lll // for readability
+	r.newCursor(id, continuation, conn, pool, noTimeout, 0)
+}
+
+// NewSnapshotCursor is like [Registry.NewCursor] for cursors that keep a snapshot transaction open on conn.
+//
+// Such cursors are always closed by [Registry.CloseIdle], after a shorter idle time,
+// because they prevent vacuum from removing old row versions.
+func (r *Registry) NewSnapshotCursor(id int64, continuation wirebson.RawDocument, conn *pgx.Conn, pool *pgxpool.Pool) {
+	r.newCursor(id, continuation, conn, pool, false, snapshotCursorTimeout)
+}
+
+// newCursor implements [Registry.NewCursor] and [Registry.NewSnapshotCursor].
+func (r *Registry) newCursor(id int64, continuation wirebson.RawDocument, conn *pgx.Conn, pool *pgxpool.Pool, noTimeout bool, maxIdle time.Duration) { //nolint:lll // for readability
 	// to have better logging for now
 	var cont *wirebson.Document
 	if len(continuation) > 0 {
@@ -159,7 +230,9 @@ func (r *Registry) NewCursor(id int64, continuation wirebson.RawDocument, conn *
 		slog.Int64("id", id), slog.Any("continuation", cont), slog.Bool("persist", persist),
 	)
 
-	r.cursors[id] = newCursor(continuation, conn)
+	c := newCursor(continuation, conn, pool, noTimeout)
+	c.maxIdle = maxIdle
+	r.cursors[id] = c
 
 	// TODO https://github.com/FerretDB/FerretDB-DocumentDB/issues/97
 	t := "normal"
@@ -170,16 +243,16 @@ func (r *Registry) NewCursor(id int64, continuation wirebson.RawDocument, conn *
 	r.created.WithLabelValues(t).Inc()
 }
 
-// GetCursor returns the continuation and the connection for the given cursor id.
-func (r *Registry) GetCursor(id int64) (wirebson.RawDocument, *pgx.Conn) {
+// GetCursor returns the continuation, the connection, and the pool for the given cursor id.
+func (r *Registry) GetCursor(id int64) (wirebson.RawDocument, *pgx.Conn, *pgxpool.Pool) {
 	r.rw.RLock()
 	defer r.rw.RUnlock()
 
 	if c := r.cursors[id]; c != nil {
-		return c.continuation, c.conn
+		return c.continuation, c.conn, c.pool
 	}
 
-	return nil, nil
+	return nil, nil, nil
 }
 
 // UpdateCursor updates existing cursor with given continuation.
@@ -217,6 +290,7 @@ func (r *Registry) UpdateCursor(id int64, continuation wirebson.RawDocument) {
 		slog.Int64("id", id), slog.Any("continuation", cont), slog.Bool("persist", persist),
 	)
 	c.continuation = continuation
+	c.lastUsed = time.Now()
 }
 
 // CloseCursor closes the cursor with the given id and removes it from the registry.
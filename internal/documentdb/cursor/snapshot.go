@@ -0,0 +1,193 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cursor
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+	"github.com/FerretDB/FerretDB/v2/internal/util/resource"
+)
+
+const (
+	// snapshotTimeout is the maximum idle time of exported snapshots.
+	// It is shorter than the cursor timeout, because each snapshot keeps a transaction open
+	// on a dedicated connection, preventing vacuum from removing old row versions.
+	snapshotTimeout = time.Minute
+
+	// snapshotCursorTimeout is the maximum idle time of snapshot cursors.
+	// Each of them keeps a transaction open like exported snapshots do,
+	// but for longer, similarly to MongoDB's minSnapshotHistoryWindowInSeconds.
+	snapshotCursorTimeout = 5 * time.Minute
+
+	// maxSnapshots is the maximum number of exported snapshots;
+	// the least recently used ones are closed first.
+	maxSnapshots = 16
+)
+
+// snapshot stores the connection with the open REPEATABLE READ transaction
+// that exported PostgreSQL snapshot with `pg_export_snapshot()`.
+//
+// The exported snapshot could be imported by other transactions only while that transaction is open.
+type snapshot struct {
+	created  time.Time
+	lastUsed time.Time
+	token    *resource.Token
+	conn     *pgx.Conn
+	id       string
+	refs     int // number of transactions that are importing the snapshot
+}
+
+// newSnapshot creates a new snapshot for the given exported snapshot ID and connection.
+func newSnapshot(id string, conn *pgx.Conn) *snapshot {
+	must.NotBeZero(id)
+	must.NotBeZero(conn)
+
+	res := &snapshot{
+		id:      id,
+		conn:    conn,
+		token:   resource.NewToken(),
+		created: time.Now(),
+	}
+
+	res.lastUsed = res.created
+
+	resource.Track(res, res.token)
+
+	return res
+}
+
+// close closes the connection, ending the transaction.
+func (s *snapshot) close(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_ = s.conn.Close(ctx)
+	s.conn = nil
+
+	resource.Untrack(s, s.token)
+}
+
+// NewSnapshot stores the exported snapshot ID for the given WAL location
+// and the connection with the transaction that exported it.
+// The registry takes over the connection.
+//
+// If there is a snapshot for the same WAL location already, it is kept, and the given connection is closed:
+// both snapshots see the same data, and the existing one could be imported concurrently.
+// If there are too many snapshots, the least recently used ones that are not being imported are closed.
+func (r *Registry) NewSnapshot(ctx context.Context, lsn uint64, id string, conn *pgx.Conn) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	if s := r.snapshots[lsn]; s != nil {
+		r.l.DebugContext(ctx, "Keeping existing snapshot", slog.Uint64("lsn", lsn), slog.String("snapshot", s.id))
+
+		closeCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+		defer cancel()
+
+		_ = conn.Close(closeCtx)
+
+		return
+	}
+
+	for len(r.snapshots) >= maxSnapshots {
+		var oldest *snapshot
+		var oldestLSN uint64
+
+		for l, s := range r.snapshots {
+			if s.refs == 0 && (oldest == nil || s.lastUsed.Before(oldest.lastUsed)) {
+				oldest, oldestLSN = s, l
+			}
+		}
+
+		if oldest == nil {
+			break
+		}
+
+		r.closeSnapshot(ctx, oldestLSN)
+	}
+
+	r.l.DebugContext(ctx, "Storing new snapshot", slog.Uint64("lsn", lsn), slog.String("snapshot", id))
+
+	r.snapshots[lsn] = newSnapshot(id, conn)
+}
+
+// GetSnapshot returns the exported snapshot ID for the given WAL location,
+// or empty string if there is no such snapshot.
+//
+// If the ID is returned, the snapshot is not closed
+// until [Registry.ReleaseSnapshot] is called for the same WAL location.
+// That should be done after the snapshot is imported with `SET TRANSACTION SNAPSHOT`.
+func (r *Registry) GetSnapshot(lsn uint64) string {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	s := r.snapshots[lsn]
+	if s == nil {
+		return ""
+	}
+
+	s.lastUsed = time.Now()
+	s.refs++
+
+	return s.id
+}
+
+// ReleaseSnapshot releases the snapshot for the given WAL location returned by [Registry.GetSnapshot].
+func (r *Registry) ReleaseSnapshot(lsn uint64) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	if s := r.snapshots[lsn]; s != nil {
+		s.lastUsed = time.Now()
+		s.refs--
+	}
+}
+
+// closeSnapshots closes snapshots that were not used for the given duration (or all if it is negative)
+// without locking.
+//
+// Snapshots that are being imported are not closed, unless all snapshots are closed.
+// Snapshot timeout is used instead of the given duration if it is shorter.
+func (r *Registry) closeSnapshots(ctx context.Context, timeout time.Duration) {
+	if timeout >= 0 {
+		timeout = min(timeout, snapshotTimeout)
+	}
+
+	for lsn, s := range r.snapshots {
+		if timeout >= 0 && (s.refs > 0 || time.Since(s.lastUsed) <= timeout) {
+			continue
+		}
+
+		r.closeSnapshot(ctx, lsn)
+	}
+}
+
+// closeSnapshot closes and removes the snapshot for the given WAL location without locking.
+func (r *Registry) closeSnapshot(ctx context.Context, lsn uint64) {
+	s := r.snapshots[lsn]
+
+	r.l.DebugContext(
+		ctx, "Closing and removing snapshot",
+		slog.Uint64("lsn", lsn), slog.String("snapshot", s.id), slog.Duration("duration", time.Since(s.created)),
+	)
+
+	s.close(ctx)
+	delete(r.snapshots, lsn)
+}
@@ -25,6 +25,7 @@
 	"github.com/stretchr/testify/require"
 
 	"github.com/FerretDB/FerretDB/v2/build/version"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/cursor"
 	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
 	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
@@ -37,7 +38,7 @@
 func testPool(t testing.TB, ctx context.Context, uri string, sp *state.Provider) (error, error) {
 	t.Helper()
 
-	pool, err := newPgxPool(uri, testutil.Logger(t), sp)
+	pool, err := newPgxPool(uri, false, testutil.Logger(t), sp)
 	if err != nil {
 		return err, nil
 	}
@@ -135,3 +136,70 @@ func TestError(t *testing.T) {
 		`DataTypeName:"", ConstraintName:"", File:"delete.c", Line:509, Routine:"BuildDeletionSpec"}}`
 	assert.Equal(t, expected, fmt.Sprintf("%#v", err))
 }
+
+func TestCursorPool(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+	l := testutil.Logger(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	// nothing is listening on those ports; no connections are established
+	primary, err := newPgxPool("postgres://127.0.0.1:56789/postgres", false, l, sp)
+	require.NoError(t, err)
+	t.Cleanup(primary.Close)
+
+	r, err := newReplica("postgres://127.0.0.1:56790/postgres", l, sp)
+	require.NoError(t, err)
+	t.Cleanup(r.p.Close)
+
+	r.healthy.Store(true)
+
+	p := &Pool{
+		p:        primary,
+		r:        cursor.NewRegistry(l),
+		l:        l,
+		replicas: []*replica{r},
+	}
+	t.Cleanup(func() { p.r.Close(ctx) })
+
+	rp := p.Replica(0)
+	require.NotNil(t, rp)
+
+	continuation := must.NotFail(wirebson.MustDocument("continuation", int64(1)).Encode())
+
+	p.newCursor(1, continuation, nil, false)
+	rp.newCursor(2, continuation, nil, false)
+
+	_, _, pool := p.r.GetCursor(1)
+	assert.Same(t, p, p.cursorPool(pool))
+
+	// getMore for the replica cursor uses the replica, even if called on the primary pool
+	_, _, pool = p.r.GetCursor(2)
+	actual := p.cursorPool(pool)
+	assert.Same(t, r.p, actual.p)
+	assert.True(t, actual.replica)
+}
+
+func TestCountStandbyNames(t *testing.T) {
+	t.Parallel()
+
+	for s, expected := range map[string]int32{
+		"":                         0,
+		"*":                        0,
+		"s1":                       1,
+		"s1, s2":                   2,
+		"FIRST 1 (s1, s2, s3)":     3,
+		"ANY 2 (s1, s2, s3)":       3,
+		"2 (s1, *)":                1,
+		`ANY 1 ("s,1", "s2")`:      2,
+		`first 1 ( "s1" , "s2" ) `: 2,
+	} {
+		t.Run(s, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, expected, countStandbyNames(s))
+		})
+	}
+}
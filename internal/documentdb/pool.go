@@ -15,7 +15,9 @@
 package documentdb
 
 import (
+	"context"
 	"log/slog"
+	"sync/atomic"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -41,14 +43,30 @@ type Pool struct {
 	r     *cursor.Registry
 	l     *slog.Logger
 	token *resource.Token
+
+	replicas       []*replica
+	nextReplica    atomic.Uint32
+	replicasCancel context.CancelFunc
+	replicasDone   chan struct{}
+
+	replica bool // true for pools returned by [Pool.Replica]
 }
 
 // NewPool creates a new pool of PostgreSQL connections.
 // No actual connections are established.
 func NewPool(uri string, l *slog.Logger, sp *state.Provider) (*Pool, error) {
+	return NewPoolWithReplicas(uri, nil, l, sp)
+}
+
+// NewPoolWithReplicas creates a new pool of PostgreSQL connections
+// with additional pools for PostgreSQL streaming replicas that could be used for reads.
+// See [Pool.Replica].
+//
+// No actual connections are established, but replicas health checks are started.
+func NewPoolWithReplicas(uri string, replicaURIs []string, l *slog.Logger, sp *state.Provider) (*Pool, error) {
 	must.NotBeZero(sp)
 
-	p, err := newPgxPool(uri, logging.WithName(l, "pgx"), sp)
+	p, err := newPgxPool(uri, false, logging.WithName(l, "pgx"), sp)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -59,15 +77,54 @@ func NewPool(uri string, l *slog.Logger, sp *state.Provider) (*Pool, error) {
 		l:     l,
 		token: resource.NewToken(),
 	}
+
+	for _, u := range replicaURIs {
+		var r *replica
+		if r, err = newReplica(u, l, sp); err != nil {
+			for _, r := range res.replicas {
+				r.p.Close()
+			}
+
+			p.Close()
+
+			return nil, lazyerrors.Error(err)
+		}
+
+		res.replicas = append(res.replicas, r)
+	}
+
+	if len(res.replicas) > 0 {
+		var ctx context.Context
+		ctx, res.replicasCancel = context.WithCancel(context.Background())
+		res.replicasDone = make(chan struct{})
+
+		go res.runReplicaChecks(ctx)
+	}
+
 	resource.Track(res, res.token)
 
 	return res, nil
 }
 
 // Close closes all connections in the pool.
+//
+// It should not be called on pools returned by [Pool.Replica].
 func (p *Pool) Close() {
+	if p.replica {
+		panic("replica pool should not be closed")
+	}
+
 	p.r.Close(todoCtx)
 
+	if p.replicasCancel != nil {
+		p.replicasCancel()
+		<-p.replicasDone
+	}
+
+	for _, r := range p.replicas {
+		r.p.Close()
+	}
+
 	p.p.Close()
 
 	resource.Untrack(p, p.token)
@@ -103,6 +160,13 @@ func (p *Pool) WithConn(f func(*pgx.Conn) error) error {
 	return nil
 }
 
+// Busy returns true if all connections are acquired and no new ones could be created,
+// so optional queries should be skipped.
+func (p *Pool) Busy() bool {
+	stats := p.p.Stat()
+	return stats.IdleConns() == 0 && stats.TotalConns() >= stats.MaxConns()
+}
+
 // Describe implements [prometheus.Collector].
 func (p *Pool) Describe(ch chan<- *prometheus.Desc) {
 	prometheus.DescribeByCollect(p, ch)
@@ -111,6 +175,7 @@ func (p *Pool) Describe(ch chan<- *prometheus.Desc) {
 // Collect implements [prometheus.Collector].
 func (p *Pool) Collect(ch chan<- prometheus.Metric) {
 	p.r.Collect(ch)
+	p.collectReplicas(ch)
 
 	stats := p.p.Stat()
 
@@ -0,0 +1,155 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// invalidDatabaseNameChars contains characters that are not allowed in database names.
+const invalidDatabaseNameChars = "/\\. \"$*<>:|?\x00"
+
+// maxDatabaseNameLength is the maximum length of the database name in bytes.
+const maxDatabaseNameLength = 63
+
+// MoveCollection moves the collection to another database, possibly with a new name.
+//
+// It changes the catalog entry only (like DocumentDB's rename_collection does),
+// so data and indexes are not copied, and the time does not depend on the collection size.
+// If dropTarget is true, the existing target collection is dropped in the same transaction.
+// Only plain collections could be moved; views and sharded collections are rejected.
+func (p *Pool) MoveCollection(ctx context.Context, fromDB, fromC, toDB, toC string, dropTarget bool) error {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.MoveCollection")
+	defer span.End()
+
+	err := p.WithConn(func(conn *pgx.Conn) error {
+		return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			return moveCollection(ctx, tx, p.l, fromDB, fromC, toDB, toC, dropTarget)
+		})
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// moveCollection implements [Pool.MoveCollection] inside the given transaction.
+func moveCollection(ctx context.Context, tx pgx.Tx, l *slog.Logger, fromDB, fromC, toDB, toC string, dropTarget bool) error {
+	if toDB == "" || len(toDB) > maxDatabaseNameLength ||
+		strings.ContainsAny(toDB, invalidDatabaseNameChars) || !utf8.ValidString(toDB) {
+		return mongoerrors.NewWithArgument(
+			mongoerrors.ErrInvalidNamespace,
+			fmt.Sprintf("Invalid target namespace: %s.%s", toDB, toC),
+			"renameCollection",
+		)
+	}
+
+	var id int64
+	var view, sharded bool
+
+	q := `SELECT collection_id, view_definition IS NOT NULL, shard_key IS NOT NULL ` +
+		`FROM documentdb_api_catalog.collections ` +
+		`WHERE database_name = $1 AND collection_name = $2 FOR UPDATE`
+
+	err := tx.QueryRow(ctx, q, fromDB, fromC).Scan(&id, &view, &sharded)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return mongoerrors.NewWithArgument(
+			mongoerrors.ErrNamespaceNotFound,
+			fmt.Sprintf("Source collection %s.%s does not exist", fromDB, fromC),
+			"renameCollection",
+		)
+	}
+
+	if err != nil {
+		return lazyerrors.Error(mongoerrors.Make(ctx, err, "", l))
+	}
+
+	if view {
+		return mongoerrors.NewWithArgument(
+			mongoerrors.ErrCommandNotSupportedOnView,
+			fmt.Sprintf("Namespace %s.%s is a view, not a collection", fromDB, fromC),
+			"renameCollection",
+		)
+	}
+
+	if sharded {
+		return mongoerrors.NewWithArgument(
+			mongoerrors.ErrIllegalOperation,
+			fmt.Sprintf("Sharded collection %s.%s could not be moved to another database", fromDB, fromC),
+			"renameCollection",
+		)
+	}
+
+	var existing string
+
+	q = `SELECT database_name FROM documentdb_api_catalog.collections ` +
+		`WHERE lower(database_name) = lower($1) AND database_name <> $1 LIMIT 1`
+
+	err = tx.QueryRow(ctx, q, toDB).Scan(&existing)
+
+	switch {
+	case err == nil:
+		return mongoerrors.NewWithArgument(
+			mongoerrors.ErrDbAlreadyExists,
+			fmt.Sprintf("db already exists with different case already have: [%s] trying to create [%s]", existing, toDB),
+			"renameCollection",
+		)
+	case errors.Is(err, pgx.ErrNoRows):
+	default:
+		return lazyerrors.Error(mongoerrors.Make(ctx, err, "", l))
+	}
+
+	q = `SELECT EXISTS(SELECT 1 FROM documentdb_api_catalog.collections WHERE database_name = $1 AND collection_name = $2)`
+
+	var exists bool
+	if err = tx.QueryRow(ctx, q, toDB, toC).Scan(&exists); err != nil {
+		return lazyerrors.Error(mongoerrors.Make(ctx, err, "", l))
+	}
+
+	if exists {
+		if !dropTarget {
+			return mongoerrors.NewWithArgument(
+				mongoerrors.ErrNamespaceExists,
+				"target namespace exists",
+				"renameCollection",
+			)
+		}
+
+		if _, err = documentdb_api.DropCollection(ctx, tx.Conn(), l, toDB, toC, nil, nil, false); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	q = `UPDATE documentdb_api_catalog.collections SET database_name = $1, collection_name = $2 WHERE collection_id = $3`
+
+	if _, err = tx.Exec(ctx, q, toDB, toC, id); err != nil {
+		return lazyerrors.Error(mongoerrors.Make(ctx, err, "", l))
+	}
+
+	return nil
+}
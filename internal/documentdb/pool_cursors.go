@@ -18,8 +18,11 @@
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/FerretDB/wire/wirebson"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.opentelemetry.io/otel"
 
 	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
@@ -33,7 +36,7 @@ func (p *Pool) GetMore(ctx context.Context, db string, spec wirebson.RawDocument
 	ctx, span := otel.Tracer("").Start(ctx, "pool.GetMore")
 	defer span.End()
 
-	continuation, conn := p.r.GetCursor(cursorID)
+	continuation, conn, pool := p.r.GetCursor(cursorID)
 	if continuation == nil {
 		return nil, mongoerrors.NewWithArgument(
 			mongoerrors.ErrCursorNotFound,
@@ -43,7 +46,8 @@ func (p *Pool) GetMore(ctx context.Context, db string, spec wirebson.RawDocument
 	}
 
 	if conn == nil {
-		poolConn, err := p.Acquire()
+		// fetch the next page from the same primary or replica
+		poolConn, err := p.cursorPool(pool).Acquire()
 		if err != nil {
 			return nil, lazyerrors.Error(err)
 		}
@@ -83,6 +87,24 @@ func (p *Pool) KillCursor(ctx context.Context, id int64) bool {
 	return p.r.CloseCursor(ctx, id)
 }
 
+// CloseCursors closes all cursors and returns their number.
+//
+// It is used during shutdown to release persisted connections before the pool is closed.
+func (p *Pool) CloseCursors(ctx context.Context) int {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.CloseCursors")
+	defer span.End()
+
+	return p.r.CloseAll(ctx)
+}
+
+// CloseIdleCursors closes cursors that were not used for the given duration and returns their number.
+func (p *Pool) CloseIdleCursors(ctx context.Context, timeout time.Duration) int {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.CloseIdleCursors")
+	defer span.End()
+
+	return p.r.CloseIdle(ctx, timeout)
+}
+
 // ListCollections returns the first page of the `listCollections` cursor and the cursor ID.
 func (p *Pool) ListCollections(ctx context.Context, db string, spec wirebson.RawDocument) (wirebson.RawDocument, int64, error) {
 	ctx, span := otel.Tracer("").Start(ctx, "pool.ListCollections")
@@ -113,7 +135,7 @@ func (p *Pool) ListCollections(ctx context.Context, db string, spec wirebson.Raw
 		conn = nil
 	}
 
-	p.r.NewCursor(cursorID, continuation, conn)
+	p.newCursor(cursorID, continuation, conn, false)
 
 	return page, cursorID, nil
 }
@@ -148,7 +170,7 @@ func (p *Pool) Find(ctx context.Context, db string, spec wirebson.RawDocument) (
 		conn = nil
 	}
 
-	p.r.NewCursor(cursorID, continuation, conn)
+	p.newCursor(cursorID, continuation, conn, noCursorTimeout(spec))
 
 	return page, cursorID, nil
 }
@@ -183,7 +205,7 @@ func (p *Pool) Aggregate(ctx context.Context, db string, spec wirebson.RawDocume
 		conn = nil
 	}
 
-	p.r.NewCursor(cursorID, continuation, conn)
+	p.newCursor(cursorID, continuation, conn, false)
 
 	return page, cursorID, nil
 }
@@ -219,7 +241,39 @@ func (p *Pool) ListIndexes(ctx context.Context, db string, spec wirebson.RawDocu
 		conn = nil
 	}
 
-	p.r.NewCursor(cursorID, continuation, conn)
+	p.newCursor(cursorID, continuation, conn, false)
 
 	return page, cursorID, nil
 }
+
+// newCursor stores the cursor created by p in the registry.
+func (p *Pool) newCursor(id int64, continuation wirebson.RawDocument, conn *pgx.Conn, noTimeout bool) {
+	p.r.NewCursor(id, continuation, conn, p.p, noTimeout)
+}
+
+// cursorPool returns the pool for the given pgx pool of the cursor:
+// p itself for primary cursors, or the replica pool for replica cursors.
+func (p *Pool) cursorPool(pool *pgxpool.Pool) *Pool {
+	if pool == nil || pool == p.p {
+		return p
+	}
+
+	return &Pool{
+		p:       pool,
+		r:       p.r,
+		l:       p.l,
+		replica: true,
+	}
+}
+
+// noCursorTimeout returns true if the given `find` command specification sets `noCursorTimeout` option.
+func noCursorTimeout(spec wirebson.RawDocument) bool {
+	doc, err := spec.Decode()
+	if err != nil {
+		return false
+	}
+
+	v, _ := doc.Get("noCursorTimeout").(bool)
+
+	return v
+}
@@ -0,0 +1,283 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api_internal"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// IndexUsage represents usage statistics of a single index.
+//
+//nolint:vet // for readability
+type IndexUsage struct {
+	Database   string
+	Collection string
+	Name       string
+	Key        wirebson.RawDocument
+	Unique     bool
+
+	// Spec is the full index specification as returned by `listIndexes`,
+	// including options like partialFilterExpression, sparse, collation, and expireAfterSeconds.
+	Spec wirebson.RawDocument
+
+	// Ops is the number of index scans on the primary and available streaming replicas since Since.
+	Ops int64
+
+	// Since is the earliest time statistics were last reset, or PostgreSQL was started,
+	// on the primary and available streaming replicas.
+	Since time.Time
+}
+
+// statsSinceExpr is the SQL expression for the time statistics were last reset, or PostgreSQL was started.
+const statsSinceExpr = `COALESCE(
+	(SELECT stats_reset FROM pg_stat_database WHERE datname = current_database()),
+	pg_postmaster_start_time()
+)`
+
+// indexUsageQuery returns index usage statistics from pg_stat_user_indexes
+// together with PostgreSQL index names.
+//
+// DocumentDB names the PostgreSQL index backing `_id_` after the collection,
+// and all other indexes after their index ID.
+const indexUsageQuery = `
+	SELECT
+		c.database_name,
+		c.collection_name,
+		(i.index_spec).index_name,
+		(i.index_spec).index_key::bytea,
+		COALESCE((i.index_spec).index_is_unique, false),
+		documentdb_api_internal.index_spec_as_bson(i.index_spec, true)::bytea,
+		COALESCE(s.idx_scan, 0),
+		` + statsSinceExpr + `,
+		r.relname
+	FROM documentdb_api_catalog.collection_indexes i
+	JOIN documentdb_api_catalog.collections c ON c.collection_id = i.collection_id
+	CROSS JOIN LATERAL (SELECT CASE
+		WHEN (i.index_spec).index_name = '_id_' THEN 'collection_pk_' || c.collection_id
+		ELSE 'documents_rum_index_' || i.index_id
+	END AS relname) r
+	LEFT JOIN pg_stat_user_indexes s ON s.schemaname = 'documentdb_data' AND s.indexrelname = r.relname
+	WHERE ($1 = '' OR c.database_name = $1) AND ($2 = '' OR c.collection_name = $2)
+	ORDER BY c.database_name, c.collection_name, i.index_id`
+
+// replicaIndexUsageQuery returns index usage statistics of all DocumentDB indexes from pg_stat_user_indexes.
+//
+// Replicas have the same catalog as the primary, so PostgreSQL index names match.
+const replicaIndexUsageQuery = `
+	SELECT indexrelname, idx_scan, ` + statsSinceExpr + `
+	FROM pg_stat_user_indexes
+	WHERE schemaname = 'documentdb_data'`
+
+// IndexUsage returns usage statistics of indexes of the given collection.
+//
+// Statistics of the primary and healthy streaming replicas are summed,
+// as reads with non-primary read preference use indexes on replicas.
+// Replicas that fail to report statistics are skipped.
+//
+// Empty database or collection name matches all databases or collections.
+func (p *Pool) IndexUsage(ctx context.Context, db, collection string) ([]IndexUsage, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.IndexUsage")
+	defer span.End()
+
+	var res []IndexUsage
+
+	// indexes of res entries by PostgreSQL index name
+	byRelname := map[string]int{}
+
+	err := p.WithConn(func(conn *pgx.Conn) error {
+		rows, err := conn.Query(ctx, indexUsageQuery, db, collection)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		var u IndexUsage
+		var relname string
+
+		dest := []any{&u.Database, &u.Collection, &u.Name, &u.Key, &u.Unique, &u.Spec, &u.Ops, &u.Since, &relname}
+
+		_, err = pgx.ForEachRow(rows, dest, func() error {
+			byRelname[relname] = len(res)
+			res = append(res, u)
+
+			return nil
+		})
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	for _, r := range p.replicas {
+		if !r.healthy.Load() {
+			continue
+		}
+
+		if err = r.addIndexUsage(ctx, res, byRelname); err != nil {
+			r.l.WarnContext(ctx, "Failed to get index usage", logging.Error(err))
+		}
+	}
+
+	return res, nil
+}
+
+// addIndexUsage adds replica's index usage statistics to res entries
+// found by PostgreSQL index name.
+func (r *replica) addIndexUsage(ctx context.Context, res []IndexUsage, byRelname map[string]int) error {
+	rows, err := r.p.Query(ctx, replicaIndexUsageQuery)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	var relname string
+	var ops int64
+	var since time.Time
+
+	_, err = pgx.ForEachRow(rows, []any{&relname, &ops, &since}, func() error {
+		i, ok := byRelname[relname]
+		if !ok {
+			return nil
+		}
+
+		res[i].Ops += ops
+
+		if since.Before(res[i].Since) {
+			res[i].Since = since
+		}
+
+		return nil
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// IndexBuild represents the build state of the index.
+type IndexBuild struct {
+	// ID is DocumentDB's index ID.
+	ID int64
+
+	// Complete is true if there is no pending or in-progress build of the index.
+	Complete bool
+
+	// Failed is true if the index build failed.
+	Failed bool
+}
+
+// IndexBuilds returns the build state of all indexes of the given collection by index name.
+func (p *Pool) IndexBuilds(ctx context.Context, db, collection string) (map[string]IndexBuild, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.IndexBuilds")
+	defer span.End()
+
+	res := map[string]IndexBuild{}
+
+	err := p.WithConn(func(conn *pgx.Conn) error {
+		q := `
+			SELECT (i.index_spec).index_name, i.index_id
+			FROM documentdb_api_catalog.collection_indexes i
+			JOIN documentdb_api_catalog.collections c ON c.collection_id = i.collection_id
+			WHERE c.database_name = $1 AND c.collection_name = $2`
+
+		rows, err := conn.Query(ctx, q, db, collection)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		var name string
+		var id int64
+
+		_, err = pgx.ForEachRow(rows, []any{&name, &id}, func() error {
+			res[name] = IndexBuild{ID: id}
+			return nil
+		})
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		for name, b := range res {
+			// the same format as `requests` returned by create_indexes_background
+			req := must.NotFail(wirebson.MustDocument(
+				"indexRequest", wirebson.MustDocument(
+					"cmdType", "C",
+					"ids", wirebson.MustArray(b.ID),
+				),
+			).Encode())
+
+			var ok bool
+			if _, ok, b.Complete, err = documentdb_api_internal.CheckBuildIndexStatus(ctx, conn, p.l, req); err != nil {
+				return lazyerrors.Error(err)
+			}
+
+			b.Failed = b.Complete && !ok
+			res[name] = b
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// IndexSpec returns the specification of the index with the given name as returned by `listIndexes`.
+// It returns nil if the index does not exist.
+func (p *Pool) IndexSpec(ctx context.Context, db, collection, name string) (wirebson.RawDocument, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.IndexSpec")
+	defer span.End()
+
+	var res wirebson.RawDocument
+
+	err := p.WithConn(func(conn *pgx.Conn) error {
+		q := `
+			SELECT documentdb_api_internal.index_spec_as_bson(i.index_spec, true)::bytea
+			FROM documentdb_api_catalog.collection_indexes i
+			JOIN documentdb_api_catalog.collections c ON c.collection_id = i.collection_id
+			WHERE c.database_name = $1 AND c.collection_name = $2 AND (i.index_spec).index_name = $3`
+
+		err := conn.QueryRow(ctx, q, db, collection, name).Scan(&res)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
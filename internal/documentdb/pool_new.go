@@ -35,7 +35,10 @@
 // No actual connections are established immediately.
 // State's version fields will be set only after a connection is established
 // by some query or ping.
-func newPgxPool(uri string, l *slog.Logger, sp *state.Provider) (*pgxpool.Pool, error) {
+//
+// If replica is true, backend PIDs are not reported to query observers,
+// because they can't be used to cancel queries on the primary.
+func newPgxPool(uri string, replica bool, l *slog.Logger, sp *state.Provider) (*pgxpool.Pool, error) {
 	must.NotBeZero(sp)
 
 	u, err := url.Parse(uri)
@@ -69,9 +72,12 @@ func newPgxPool(uri string, l *slog.Logger, sp *state.Provider) (*pgxpool.Pool,
 	// TODO https://github.com/FerretDB/FerretDB/issues/3554
 
 	// try to log everything; logger's configuration will skip extra levels if needed
-	config.ConnConfig.Tracer = &tracelog.TraceLog{
-		Logger:   logging.NewPgxLogger(l),
-		LogLevel: tracelog.LogLevelTrace,
+	config.ConnConfig.Tracer = &tracer{
+		TraceLog: &tracelog.TraceLog{
+			Logger:   logging.NewPgxLogger(l),
+			LogLevel: tracelog.LogLevelTrace,
+		},
+		replica: replica,
 	}
 
 	config.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeCacheStatement
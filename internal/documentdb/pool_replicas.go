@@ -0,0 +1,213 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/state"
+)
+
+const (
+	// replicaCheckInterval is the interval between replica health checks.
+	replicaCheckInterval = 5 * time.Second
+
+	// replicaCheckTimeout is the timeout of a single replica health check.
+	replicaCheckTimeout = 3 * time.Second
+)
+
+// replica represents a pool of connections to a single PostgreSQL streaming replica.
+type replica struct {
+	p       *pgxpool.Pool
+	l       *slog.Logger
+	host    string
+	healthy atomic.Bool
+	lag     atomic.Int64 // replication lag in nanoseconds
+}
+
+// newReplica creates a new replica pool.
+// No actual connections are established.
+func newReplica(uri string, l *slog.Logger, sp *state.Provider) (*replica, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	l = logging.WithName(l, "replica-"+u.Host)
+
+	p, err := newPgxPool(uri, true, logging.WithName(l, "pgx"), sp)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &replica{
+		p:    p,
+		l:    l,
+		host: u.Host,
+	}, nil
+}
+
+// check updates replica's health and replication lag.
+func (r *replica) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, replicaCheckTimeout)
+	defer cancel()
+
+	// replay timestamp is not updated when the primary is idle,
+	// so there is no lag if all received WAL was replayed
+	q := `SELECT pg_is_in_recovery(), ` +
+		`CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0 ` +
+		`ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0) END::float8`
+
+	var recovery bool
+	var lag float64
+
+	if err := r.p.QueryRow(ctx, q).Scan(&recovery, &lag); err != nil {
+		if r.healthy.Swap(false) {
+			r.l.WarnContext(ctx, "Replica is unavailable", logging.Error(err))
+		}
+
+		return
+	}
+
+	if !recovery {
+		if r.healthy.Swap(false) {
+			r.l.WarnContext(ctx, "Replica is not in recovery mode")
+		}
+
+		return
+	}
+
+	r.lag.Store(int64(lag * float64(time.Second)))
+
+	if !r.healthy.Swap(true) {
+		r.l.InfoContext(ctx, "Replica is available", slog.Duration("lag", time.Duration(r.lag.Load())))
+	}
+}
+
+// runReplicaChecks checks replicas health until ctx is canceled.
+func (p *Pool) runReplicaChecks(ctx context.Context) {
+	defer close(p.replicasDone)
+
+	t := time.NewTicker(replicaCheckInterval)
+	defer t.Stop()
+
+	for {
+		for _, r := range p.replicas {
+			r.check(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Replica returns a pool for reading from a healthy replica
+// with replication lag not exceeding maxStaleness (if it is not zero).
+// It returns nil if there is no such replica.
+//
+// The returned pool shares cursors with p;
+// `getMore` for cursors created by it fetches the next pages from the same replica.
+// It should not be closed.
+func (p *Pool) Replica(maxStaleness time.Duration) *Pool {
+	n := uint32(len(p.replicas))
+	start := p.nextReplica.Add(1)
+
+	for i := range n {
+		r := p.replicas[(start+i)%n]
+
+		if !r.healthy.Load() {
+			continue
+		}
+
+		if maxStaleness > 0 && time.Duration(r.lag.Load()) > maxStaleness {
+			continue
+		}
+
+		return &Pool{
+			p:       r.p,
+			r:       p.r,
+			l:       p.l,
+			replica: true,
+		}
+	}
+
+	return nil
+}
+
+// IsPrimary returns true if the pool is connected to the PostgreSQL primary
+// (that is, not to the streaming replica).
+func (p *Pool) IsPrimary(ctx context.Context) (bool, error) {
+	var recovery bool
+	if err := p.p.QueryRow(ctx, `SELECT pg_is_in_recovery()`).Scan(&recovery); err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	return !recovery, nil
+}
+
+// Timeline returns the PostgreSQL timeline ID of the pool's connection.
+// It is incremented each time a standby is promoted.
+func (p *Pool) Timeline(ctx context.Context) (int64, error) {
+	var timeline int64
+	if err := p.p.QueryRow(ctx, `SELECT timeline_id FROM pg_control_checkpoint()`).Scan(&timeline); err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	return timeline, nil
+}
+
+// collectReplicas collects replicas metrics.
+func (p *Pool) collectReplicas(ch chan<- prometheus.Metric) {
+	for _, r := range p.replicas {
+		var healthy float64
+		if r.healthy.Load() {
+			healthy = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName(namespace, subsystem, "replica_healthy"),
+				"Whether the replica is available for reads.",
+				[]string{"replica"}, nil,
+			),
+			prometheus.GaugeValue,
+			healthy,
+			r.host,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName(namespace, subsystem, "replica_lag_seconds"),
+				"The replication lag of the replica.",
+				[]string{"replica"}, nil,
+			),
+			prometheus.GaugeValue,
+			time.Duration(r.lag.Load()).Seconds(),
+			r.host,
+		)
+	}
+}
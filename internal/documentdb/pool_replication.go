@@ -0,0 +1,189 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// replicationPollInterval is the interval between replication status checks.
+const replicationPollInterval = 10 * time.Millisecond
+
+// ErrNotEnoughReplicas is returned by [Pool.WaitForReplication]
+// when the requested number of members exceeds the number of configured members.
+var ErrNotEnoughReplicas = errors.New("not enough replicas")
+
+// replayedQuery returns the number of physical streaming replicas that replayed WAL up to the given location,
+// and whether that location is flushed on the primary.
+//
+// Logical replication walsenders (that always use logical slots) and base backups are not counted.
+const replayedQuery = `
+	SELECT
+		(
+			SELECT count(*) FROM pg_stat_replication r
+			WHERE r.state = 'streaming' AND r.replay_lsn >= $1::pg_lsn AND NOT EXISTS (
+				SELECT 1 FROM pg_replication_slots s WHERE s.active_pid = r.pid AND s.slot_type = 'logical'
+			)
+		),
+		pg_current_wal_flush_lsn() >= $1::pg_lsn`
+
+// WaitForReplication waits until all WAL written by the PostgreSQL primary so far
+// is replayed by enough streaming replicas.
+//
+// The primary counts as one member, so w-1 replicas are required;
+// if majority is true, w is ignored and a majority of all members is required.
+// Members are configured, not connected, ones:
+// the largest of the given number (for example, replica set hosts), 1 + the number of replicas configured for reads,
+// and 1 + the number of standby names in `synchronous_standby_names`.
+// Replicas that are disconnected or catching up are waited for;
+// [ErrNotEnoughReplicas] is returned only if the required number exceeds the number of members.
+// If journal is true, it also waits until WAL is flushed on the primary.
+// A pool connection is acquired only for each status check, not for the whole wait.
+//
+// It returns ctx's error when ctx is done before that.
+func (p *Pool) WaitForReplication(ctx context.Context, w int32, majority, journal bool, members int32) error {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.WaitForReplication")
+	defer span.End()
+
+	var lsn, standbyNames string
+
+	q := `SELECT pg_current_wal_lsn()::text, current_setting('synchronous_standby_names')`
+	if err := p.p.QueryRow(ctx, q).Scan(&lsn, &standbyNames); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	members = max(members, 1+int32(len(p.replicas)), 1+countStandbyNames(standbyNames))
+
+	required := w
+	if majority {
+		required = members/2 + 1
+	}
+
+	if required > members {
+		return ErrNotEnoughReplicas
+	}
+
+	t := time.NewTicker(replicationPollInterval)
+	defer t.Stop()
+
+	for {
+		var replayed int32
+		var flushed bool
+
+		if err := p.p.QueryRow(ctx, replayedQuery, lsn).Scan(&replayed, &flushed); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if 1+replayed >= required && (flushed || !journal) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-t.C:
+		}
+	}
+}
+
+// countStandbyNames returns the number of standby names in the `synchronous_standby_names` setting value
+// in any of `FIRST n (names)`, `ANY n (names)`, `n (names)`, or `names` forms.
+// The `*` wildcard is not counted, as it does not configure any particular standby.
+func countStandbyNames(s string) int32 {
+	if i := strings.IndexByte(s, '('); i >= 0 {
+		s = strings.TrimSuffix(strings.TrimSpace(s[i+1:]), ")")
+	}
+
+	var res int32
+	var quoted bool
+	var name strings.Builder
+
+	add := func() {
+		if n := strings.TrimSpace(name.String()); n != "" && n != "*" {
+			res++
+		}
+
+		name.Reset()
+	}
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			name.WriteRune(r)
+		case r == ',' && !quoted:
+			add()
+		default:
+			name.WriteRune(r)
+		}
+	}
+
+	add()
+
+	return res
+}
+
+// LSN returns the current WAL location of the backend as a number of bytes.
+//
+// For the primary, that is the current WAL write location;
+// for pools returned by [Pool.Replica], that is the last WAL location replayed by the replica.
+func (p *Pool) LSN(ctx context.Context) (uint64, error) {
+	q := `SELECT (pg_current_wal_lsn() - '0/0'::pg_lsn)::bigint`
+	if p.replica {
+		q = `SELECT COALESCE(pg_last_wal_replay_lsn() - '0/0'::pg_lsn, 0)::bigint`
+	}
+
+	var lsn int64
+	if err := p.p.QueryRow(ctx, q).Scan(&lsn); err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	return uint64(lsn), nil
+}
+
+// WaitForLSN waits until the backend reaches the given WAL location (see [Pool.LSN]).
+//
+// It returns ctx's error when ctx is done before that.
+func (p *Pool) WaitForLSN(ctx context.Context, lsn uint64) error {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.WaitForLSN")
+	defer span.End()
+
+	t := time.NewTicker(replicationPollInterval)
+	defer t.Stop()
+
+	for {
+		current, err := p.LSN(ctx)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if current >= lsn {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// RolePrivileges represents privileges of a PostgreSQL role
+// that are checked by administrative commands.
+type RolePrivileges struct {
+	// Superuser is true if the role is a superuser.
+	Superuser bool
+
+	// SignalBackend is true if the role is a superuser or a member of `pg_signal_backend`.
+	SignalBackend bool
+}
+
+// RolePrivileges returns privileges of the PostgreSQL role with the given name.
+//
+// Unknown roles have no privileges.
+func (p *Pool) RolePrivileges(ctx context.Context, role string) (*RolePrivileges, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.RolePrivileges")
+	defer span.End()
+
+	var res RolePrivileges
+
+	err := p.WithConn(func(conn *pgx.Conn) error {
+		q := `
+			SELECT rolsuper, rolsuper OR pg_has_role(oid, 'pg_signal_backend', 'MEMBER')
+			FROM pg_roles
+			WHERE rolname = $1`
+
+		err := conn.QueryRow(ctx, q, role).Scan(&res.Superuser, &res.SignalBackend)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+
+		return err
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &res, nil
+}
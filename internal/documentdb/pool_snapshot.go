@@ -0,0 +1,180 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// ErrSnapshotTooOld is returned by [Pool.FindSnapshot] and [Pool.AggregateSnapshot]
+// when there is no snapshot for the requested WAL location.
+var ErrSnapshotTooOld = errors.New("snapshot is too old")
+
+// firstPageFunc returns the first page of the cursor using the given connection.
+type firstPageFunc func(conn *pgx.Conn) (page, continuation wirebson.RawDocument, persist bool, cursorID int64, err error)
+
+// FindSnapshot is like [Pool.Find], but reads data at the PostgreSQL snapshot
+// taken at the given WAL location (see [Pool.LSN]), or at a new snapshot if it is zero.
+// It returns the WAL location of the used snapshot.
+//
+// The first page and all `getMore`s of the cursor are executed in a single REPEATABLE READ transaction
+// on the connection kept by the cursor.
+// The `noCursorTimeout` option is ignored: the cursor is closed when idle,
+// after a shorter time than other cursors, to end that transaction.
+//
+// Snapshots could not be exported by PostgreSQL replicas, so it should be called on the primary pool.
+func (p *Pool) FindSnapshot(ctx context.Context, db string, spec wirebson.RawDocument, lsn uint64) (wirebson.RawDocument, int64, uint64, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.FindSnapshot")
+	defer span.End()
+
+	return p.firstPageSnapshot(ctx, lsn, func(conn *pgx.Conn) (wirebson.RawDocument, wirebson.RawDocument, bool, int64, error) {
+		return documentdb_api.FindCursorFirstPage(ctx, conn, p.l, db, spec, 0)
+	})
+}
+
+// AggregateSnapshot is like [Pool.Aggregate], but reads data at the PostgreSQL snapshot.
+// See [Pool.FindSnapshot].
+func (p *Pool) AggregateSnapshot(ctx context.Context, db string, spec wirebson.RawDocument, lsn uint64) (wirebson.RawDocument, int64, uint64, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "pool.AggregateSnapshot")
+	defer span.End()
+
+	return p.firstPageSnapshot(ctx, lsn, func(conn *pgx.Conn) (wirebson.RawDocument, wirebson.RawDocument, bool, int64, error) {
+		return documentdb_api.AggregateCursorFirstPage(ctx, conn, p.l, db, spec, 0)
+	})
+}
+
+// firstPageSnapshot returns the first page of the cursor, the cursor ID, and the snapshot's WAL location.
+//
+// It imports the exported snapshot for the given WAL location (or exports a new one if it is zero)
+// into a new transaction, and calls f.
+// If the cursor is not exhausted, the connection with the open transaction is stored in the snapshot cursor.
+func (p *Pool) firstPageSnapshot(ctx context.Context, lsn uint64, f firstPageFunc) (wirebson.RawDocument, int64, uint64, error) {
+	var err error
+
+	if lsn == 0 {
+		if lsn, err = p.exportSnapshot(ctx); err != nil {
+			return nil, 0, 0, lazyerrors.Error(err)
+		}
+	}
+
+	poolConn, err := p.Acquire()
+	if err != nil {
+		return nil, 0, 0, lazyerrors.Error(err)
+	}
+
+	// connections with open transactions are not returned to the pool
+	defer poolConn.Release()
+
+	conn := poolConn.Conn()
+
+	if err = p.importSnapshot(ctx, conn, lsn); err != nil {
+		return nil, 0, 0, lazyerrors.Error(err)
+	}
+
+	page, continuation, persist, cursorID, err := f(conn)
+	if err != nil {
+		return nil, 0, 0, lazyerrors.Error(err)
+	}
+
+	p.l.DebugContext(
+		ctx, "Snapshot result",
+		slog.Any("page", page), slog.Any("continuation", continuation),
+		slog.Bool("persist", persist), slog.Int64("cursor", cursorID), slog.Uint64("lsn", lsn),
+	)
+
+	if cursorID == 0 {
+		if _, err = conn.Exec(ctx, `ROLLBACK`); err != nil {
+			return nil, 0, 0, lazyerrors.Error(err)
+		}
+
+		return page, 0, lsn, nil
+	}
+
+	// keep the transaction open for getMore
+	p.r.NewSnapshotCursor(cursorID, continuation, poolConn.hijack(), p.p)
+
+	return page, cursorID, lsn, nil
+}
+
+// importSnapshot starts a REPEATABLE READ transaction on the given connection
+// and imports the exported snapshot for the given WAL location.
+//
+// The snapshot is held in the cursor registry until it is imported, so it is not closed concurrently.
+func (p *Pool) importSnapshot(ctx context.Context, conn *pgx.Conn, lsn uint64) error {
+	id := p.r.GetSnapshot(lsn)
+	if id == "" {
+		return ErrSnapshotTooOld
+	}
+
+	defer p.r.ReleaseSnapshot(lsn)
+
+	if _, err := conn.Exec(ctx, `BEGIN ISOLATION LEVEL REPEATABLE READ`); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	// SET TRANSACTION does not support parameters
+	q := `SET TRANSACTION SNAPSHOT '` + strings.ReplaceAll(id, `'`, `''`) + `'`
+	if _, err := conn.Exec(ctx, q); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// exportSnapshot exports a new snapshot, stores it in the cursor registry,
+// and returns its WAL location.
+//
+// The exporting transaction is kept open on a dedicated connection until the snapshot is closed
+// by the registry after a short idle timeout, or when there are too many snapshots.
+func (p *Pool) exportSnapshot(ctx context.Context) (uint64, error) {
+	poolConn, err := p.Acquire()
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	// connections with open transactions are not returned to the pool
+	defer poolConn.Release()
+
+	conn := poolConn.Conn()
+
+	if _, err = conn.Exec(ctx, `BEGIN ISOLATION LEVEL REPEATABLE READ READ ONLY`); err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	// the snapshot is taken at the start of the first statement,
+	// so the returned LSN includes all transactions visible in it
+	q := `SELECT pg_export_snapshot(), (pg_current_wal_lsn() - '0/0'::pg_lsn)::bigint`
+
+	var id string
+	var lsn int64
+
+	if err = conn.QueryRow(ctx, q).Scan(&id, &lsn); err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	p.r.NewSnapshot(ctx, uint64(lsn), id, poolConn.hijack())
+
+	return uint64(lsn), nil
+}
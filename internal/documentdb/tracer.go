@@ -0,0 +1,80 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package documentdb
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/tracelog"
+)
+
+// QueryObserver is notified about PostgreSQL queries executed with a context
+// returned by [WithQueryObserver].
+//
+// Queries executed with the same context are not executed concurrently.
+type QueryObserver interface {
+	// QueryStarted is called before the query is sent to the backend with the given PID.
+	// PID is 0 for queries sent to streaming replicas.
+	QueryStarted(pid uint32)
+
+	// QueryFinished is called after the query is completed (successfully or not).
+	QueryFinished()
+}
+
+// contextKey is a named unexported type for the safe use of [context.WithValue].
+type contextKey struct{}
+
+// Context key for [WithQueryObserver].
+var queryObserverKey = contextKey{}
+
+// WithQueryObserver returns a derived context with the given query observer.
+func WithQueryObserver(ctx context.Context, o QueryObserver) context.Context {
+	return context.WithValue(ctx, queryObserverKey, o)
+}
+
+// tracer is a pgx tracer that logs queries and notifies query observers.
+type tracer struct {
+	*tracelog.TraceLog
+	replica bool
+}
+
+// TraceQueryStart implements [pgx.QueryTracer].
+func (t *tracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if o, _ := ctx.Value(queryObserverKey).(QueryObserver); o != nil {
+		var pid uint32
+		if !t.replica {
+			pid = conn.PgConn().PID()
+		}
+
+		o.QueryStarted(pid)
+	}
+
+	return t.TraceLog.TraceQueryStart(ctx, conn, data)
+}
+
+// TraceQueryEnd implements [pgx.QueryTracer].
+func (t *tracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	t.TraceLog.TraceQueryEnd(ctx, conn, data)
+
+	if o, _ := ctx.Value(queryObserverKey).(QueryObserver); o != nil {
+		o.QueryFinished()
+	}
+}
+
+// check interfaces
+var (
+	_ pgx.QueryTracer = (*tracer)(nil)
+)
@@ -0,0 +1,98 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/audit"
+)
+
+// auditTypes maps command names to audit event types recorded by [auditCommand].
+//
+// Authentication events are recorded by saslContinue itself.
+var auditTypes = map[string]audit.Type{
+	"createIndexes":            audit.CreateIndex,
+	"createUser":               audit.CreateUser,
+	"drop":                     audit.DropCollection,
+	"dropAllUsersFromDatabase": audit.DropAllUsersFromDatabase,
+	"dropDatabase":             audit.DropDatabase,
+	"dropUser":                 audit.DropUser,
+	"renameCollection":         audit.RenameCollection,
+	"updateUser":               audit.UpdateUser,
+}
+
+// auditCommand is a middleware that records the command and its outcome with the given auditor.
+//
+// Context must contain [*conninfo.ConnInfo].
+func auditCommand(next middleware.HandleFunc, a *audit.Auditor, l *slog.Logger, t audit.Type, command string) middleware.HandleFunc { //nolint:lll // for readability
+	return func(ctx context.Context, req *middleware.Request) (*middleware.Response, error) {
+		r := newAuditRecord(ctx, t, command)
+
+		// extract fields before the handler runs, as it could modify the document
+		if doc, err := req.OpMsg.Section0(); err == nil {
+			r.DB, _ = doc.Get("$db").(string)
+			r.Target, _ = doc.Get(doc.Command()).(string)
+
+			if to, _ := doc.Get("to").(string); to != "" {
+				r.Target += " -> " + to
+			}
+		}
+
+		resp, err := next(ctx, req)
+
+		setAuditResult(ctx, r, err, l)
+		a.Record(ctx, r)
+
+		return resp, err
+	}
+}
+
+// newAuditRecord returns a new audit record with connection information filled.
+//
+// Context must contain [*conninfo.ConnInfo].
+func newAuditRecord(ctx context.Context, t audit.Type, command string) *audit.Record {
+	r := &audit.Record{
+		Type:    t,
+		Command: command,
+	}
+
+	connInfo := conninfo.Get(ctx)
+
+	if connInfo.Peer.IsValid() {
+		r.Remote = connInfo.Peer.String()
+	}
+
+	if conv := connInfo.Conv(); conv.Succeed() {
+		r.User = conv.Username()
+	}
+
+	return r
+}
+
+// setAuditResult sets audit record's result fields for the given error (that can be nil).
+func setAuditResult(ctx context.Context, r *audit.Record, err error, l *slog.Logger) {
+	if err == nil {
+		return
+	}
+
+	e := mongoerrors.Make(ctx, err, "", l)
+	r.Result = e.Code
+	r.Error = e.Message
+}
@@ -0,0 +1,171 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// Cluster time is derived from PostgreSQL WAL locations (LSNs).
+// LSN is used as a whole 64-bit timestamp value, so the increment part is the lower half
+// and the seconds part is the upper half; only the order of values is meaningful.
+//
+// Client-supplied `$clusterTime` values are not signed by us, so they are never used
+// to advance the cluster time; they are only compared with it.
+
+// advanceClusterTime sets the cluster time to ts if it is later than the current one,
+// and returns the resulting cluster time.
+func (h *Handler) advanceClusterTime(ts wirebson.Timestamp) wirebson.Timestamp {
+	for {
+		current := h.clusterTime.Load()
+		if uint64(ts) <= current {
+			return wirebson.Timestamp(current)
+		}
+
+		if h.clusterTime.CompareAndSwap(current, uint64(ts)) {
+			return ts
+		}
+	}
+}
+
+// refreshClusterTime advances the cluster time to the current PostgreSQL primary LSN
+// and returns the resulting cluster time.
+func (h *Handler) refreshClusterTime(ctx context.Context) (wirebson.Timestamp, error) {
+	lsn, err := h.Pool.LSN(ctx)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	return h.advanceClusterTime(wirebson.Timestamp(lsn)), nil
+}
+
+// addClusterTime returns the response with added `$clusterTime` and `operationTime` fields,
+// and records the operation time in the command's session.
+//
+// For writes, the operation time is the primary LSN after the write;
+// for other commands, that is the latest known cluster time.
+// If cluster time can't be determined, the response is returned unchanged.
+func (h *Handler) addClusterTime(ctx context.Context, doc *wirebson.Document, res *middleware.Response) (*middleware.Response, error) {
+	if res.OpMsg == nil {
+		return res, nil
+	}
+
+	command := doc.Command()
+	write := slices.Contains(writeConcernCommands, command) || middleware.AggregateWrites(doc)
+
+	opTime := wirebson.Timestamp(h.clusterTime.Load())
+
+	if write || opTime == 0 {
+		var err error
+		if opTime, err = h.refreshClusterTime(ctx); err != nil {
+			h.L.DebugContext(ctx, "Failed to get cluster time", logging.Error(err))
+			return res, nil
+		}
+	}
+
+	if err := h.s.AdvanceOperationTime(ctx, doc, opTime); err != nil {
+		return nil, err
+	}
+
+	resDoc, err := res.OpMsg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	must.NoError(resDoc.Add("$clusterTime", clusterTimeDocument(wirebson.Timestamp(h.clusterTime.Load()))))
+	must.NoError(resDoc.Add("operationTime", opTime))
+
+	return middleware.ResponseMsg(resDoc)
+}
+
+// clusterTimeDocument returns `$clusterTime` document with a dummy signature.
+func clusterTimeDocument(ts wirebson.Timestamp) *wirebson.Document {
+	return must.NotFail(wirebson.NewDocument(
+		"clusterTime", ts,
+		"signature", must.NotFail(wirebson.NewDocument(
+			"hash", wirebson.Binary{B: make([]byte, 20), Subtype: wirebson.BinaryGeneric},
+			"keyId", int64(0),
+		)),
+	))
+}
+
+// checkClusterTime returns an error if the given `readConcern` field value is later than the current cluster time.
+func (h *Handler) checkClusterTime(ctx context.Context, field string, ts wirebson.Timestamp, command string) error {
+	if ts <= wirebson.Timestamp(h.clusterTime.Load()) {
+		return nil
+	}
+
+	current, err := h.refreshClusterTime(ctx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if ts <= current {
+		return nil
+	}
+
+	msg := fmt.Sprintf(
+		"readConcern %s value must not be greater than the current clusterTime. "+
+			"Requested clusterTime: %d; current clusterTime: %d",
+		field, uint64(ts), uint64(current),
+	)
+
+	return mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, command)
+}
+
+// waitForClusterTime waits until the pool selected for the read command
+// reaches `readConcern.afterClusterTime` and the operation time of the command's session.
+//
+// The primary is always up-to-date, so only replicas are waited for.
+func (h *Handler) waitForClusterTime(ctx context.Context, pool *documentdb.Pool, doc *wirebson.Document) error {
+	rc, err := getReadConcern(doc)
+	if err != nil {
+		return err
+	}
+
+	after := rc.afterClusterTime
+
+	if err = h.checkClusterTime(ctx, "afterClusterTime", after, doc.Command()); err != nil {
+		return err
+	}
+
+	if pool == h.Pool {
+		return nil
+	}
+
+	opTime, err := h.s.OperationTime(ctx, doc)
+	if err != nil {
+		return err
+	}
+
+	if target := max(after, opTime); target != 0 {
+		if err = pool.WaitForLSN(ctx, uint64(target)); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+)
+
+func TestAdvanceClusterTime(t *testing.T) {
+	t.Parallel()
+
+	h := new(Handler)
+
+	assert.Equal(t, wirebson.Timestamp(42), h.advanceClusterTime(42))
+	assert.Equal(t, wirebson.Timestamp(42), h.advanceClusterTime(41))
+	assert.Equal(t, wirebson.Timestamp(43), h.advanceClusterTime(43))
+	assert.Equal(t, uint64(43), h.clusterTime.Load())
+}
+
+func TestWaitForClusterTimePrimary(t *testing.T) {
+	t.Parallel()
+
+	// pool without replicas; no connections are used
+	h := &Handler{
+		NewOpts: &NewOpts{
+			Pool: new(documentdb.Pool),
+		},
+	}
+	h.advanceClusterTime(42)
+
+	ctx := t.Context()
+
+	doc := wirebson.MustDocument("find", "test", "$db", "test")
+	require.NoError(t, h.waitForClusterTime(ctx, h.Pool, doc))
+
+	require.NoError(t, doc.Add("readConcern", wirebson.MustDocument("afterClusterTime", wirebson.Timestamp(42))))
+	require.NoError(t, h.waitForClusterTime(ctx, h.Pool, doc))
+}
@@ -60,7 +60,7 @@ func (h *Handler) CmdQuery(connCtx context.Context, query *middleware.Request) (
 
 	switch cmd {
 	case "hello", "ismaster", "isMaster":
-		reply, err := h.hello(connCtx, q, h.TCPHost, h.ReplSetName)
+		reply, err := h.hello(connCtx, q, h.Host, h.ReplSetName)
 		if err != nil {
 			return nil, lazyerrors.Error(err)
 		}
@@ -20,8 +20,10 @@
 	"log/slog"
 
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
 	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
 	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
 )
 
@@ -105,6 +107,10 @@ func (h *Handler) initCommands() {
 			handler: h.msgCreateIndexes,
 			Help:    "Creates indexes on a collection.",
 		},
+		"createSearchIndexes": {
+			handler: h.msgCreateSearchIndexes,
+			Help:    "Creates Atlas Search and Vector Search indexes on a collection.",
+		},
 		"createUser": {
 			handler: h.msgCreateUser,
 			Help:    "Creates a new user.",
@@ -149,6 +155,10 @@ func (h *Handler) initCommands() {
 			handler: h.msgDropIndexes,
 			Help:    "Drops indexes on a collection.",
 		},
+		"dropSearchIndex": {
+			handler: h.msgDropSearchIndex,
+			Help:    "Drops an Atlas Search or Vector Search index.",
+		},
 		"dropUser": {
 			handler: h.msgDropUser,
 			Help:    "Drops user.",
@@ -232,6 +242,10 @@ func (h *Handler) initCommands() {
 			handler: h.msgKillCursors,
 			Help:    "Closes server cursors.",
 		},
+		"killOp": {
+			handler: h.msgKillOp,
+			Help:    "Terminates an operation as specified by the operation ID.",
+		},
 		"killSessions": {
 			handler: h.msgKillSessions,
 			Help:    "Kills sessions.",
@@ -262,6 +276,10 @@ func (h *Handler) initCommands() {
 			anonymous: true,
 			Help:      "Returns a pong response.",
 		},
+		"profile": {
+			handler: h.msgProfile,
+			Help:    "Returns or sets the profiling level, threshold, and sample rate of the database.",
+		},
 		"refreshSessions": {
 			handler: h.msgRefreshSessions,
 			Help:    "Updates the last used time of sessions.",
@@ -292,14 +310,26 @@ func (h *Handler) initCommands() {
 			handler: h.msgSetFreeMonitoring,
 			Help:    "Toggles free monitoring.",
 		},
+		"setParameter": {
+			handler: h.msgSetParameter,
+			Help:    "Sets the value of the parameter.",
+		},
 		"startSession": {
 			handler: h.msgStartSession,
 			Help:    "Returns a session.",
 		},
+		"top": {
+			handler: h.msgTop,
+			Help:    "Returns usage statistics for each collection.",
+		},
 		"update": {
 			handler: h.msgUpdate,
 			Help:    "Updates documents that are matched by the query.",
 		},
+		"updateSearchIndex": {
+			handler: h.msgUpdateSearchIndex,
+			Help:    "Updates the definition of an Atlas Search or Vector Search index.",
+		},
 		"updateUser": {
 			handler: h.msgUpdateUser,
 			Help:    "Updates user.",
@@ -331,6 +361,10 @@ func (h *Handler) initCommands() {
 			cmd.handler = auth(cmd.handler, logging.WithName(h.L, "auth"), name)
 		}
 
+		if t, ok := auditTypes[name]; ok && h.Audit != nil && h.Audit.Enabled(t) {
+			cmd.handler = auditCommand(cmd.handler, h.Audit, logging.WithName(h.L, "audit"), t, name)
+		}
+
 		h.commands[name] = cmd
 	}
 }
@@ -364,6 +398,23 @@ func auth(next middleware.HandleFunc, l *slog.Logger, command string) middleware
 	}
 }
 
+// userPrivileges returns PostgreSQL privileges of the authenticated user.
+// All privileges are granted when authentication is disabled.
+//
+// Context must contain [*conninfo.ConnInfo].
+func (h *Handler) userPrivileges(ctx context.Context) (*documentdb.RolePrivileges, error) {
+	if !h.Auth {
+		return &documentdb.RolePrivileges{Superuser: true, SignalBackend: true}, nil
+	}
+
+	p, err := h.Pool.RolePrivileges(ctx, conninfo.Get(ctx).Conv().Username())
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return p, nil
+}
+
 // notImplemented returns a handler that returns an error indicating that the command is not implemented.
 func notImplemented(command string) middleware.HandleFunc {
 	return func(context.Context, *middleware.Request) (*middleware.Response, error) {
@@ -0,0 +1,282 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// explainVerbosity represents `explain` command verbosity mode.
+type explainVerbosity string
+
+// Supported verbosity modes.
+const (
+	explainQueryPlanner      explainVerbosity = "queryPlanner"
+	explainExecutionStats    explainVerbosity = "executionStats"
+	explainAllPlansExecution explainVerbosity = "allPlansExecution"
+)
+
+// analyze returns true if the query should be executed to collect statistics.
+func (v explainVerbosity) analyze() bool {
+	return v == explainExecutionStats || v == explainAllPlansExecution
+}
+
+// PostgreSQL index name prefixes used by DocumentDB.
+const (
+	pgPrimaryKeyPrefix = "collection_pk_"
+	pgIndexPrefix      = "documents_rum_index_"
+)
+
+// explainStats accumulates execution statistics while translating the plan.
+type explainStats struct {
+	keysExamined int32
+	docsExamined int32
+}
+
+// planTranslator translates PostgreSQL plan nodes into MongoDB-style query plan stages.
+type planTranslator struct {
+	// indexNames maps PostgreSQL index names to MongoDB index names; may be nil
+	indexNames map[string]string
+
+	// analyze adds execution statistics to stages
+	analyze bool
+
+	stats explainStats
+}
+
+// translate converts a single PostgreSQL plan node (with children) into a MongoDB stage.
+//
+// Nodes that do not have a MongoDB equivalent and have a single child are skipped.
+func (t *planTranslator) translate(node map[string]any) *wirebson.Document {
+	nodeType, _ := node["Node Type"].(string)
+
+	var children []map[string]any
+
+	if plans, ok := node["Plans"].([]any); ok {
+		for _, p := range plans {
+			if child, ok := p.(map[string]any); ok {
+				children = append(children, child)
+			}
+		}
+	}
+
+	var stage *wirebson.Document
+
+	switch nodeType {
+	case "Seq Scan":
+		stage = t.stage(node, "COLLSCAN", "direction", "forward")
+		t.addExamined(stage, node, "docsExamined", &t.stats.docsExamined)
+
+	case "Index Only Scan":
+		stage = t.indexScan(node)
+
+	case "Index Scan":
+		// DocumentDB indexes do not cover documents, so they are always fetched
+		ixscan := t.indexScan(node)
+		stage = t.stage(node, "FETCH")
+		t.addExamined(stage, node, "docsExamined", &t.stats.docsExamined)
+		must.NoError(stage.Add("inputStage", ixscan))
+
+		return stage
+
+	case "Bitmap Index Scan":
+		return t.indexScan(node)
+
+	case "Bitmap Heap Scan":
+		stage = t.stage(node, "FETCH")
+		t.addExamined(stage, node, "docsExamined", &t.stats.docsExamined)
+
+	case "Limit":
+		stage = t.stage(node, "LIMIT")
+
+	case "Sort", "Incremental Sort":
+		stage = t.stage(node, "SORT")
+
+	case "BitmapAnd":
+		stage = t.stage(node, "AND_SORTED")
+
+	case "BitmapOr", "Append", "Merge Append":
+		stage = t.stage(node, "OR")
+
+	case "Aggregate":
+		stage = t.stage(node, "GROUP")
+
+	case "Result":
+		if len(children) == 0 {
+			return t.stage(node, "EOF")
+		}
+
+		fallthrough
+
+	default:
+		if len(children) == 1 {
+			return t.translate(children[0])
+		}
+
+		stage = t.stage(node, strings.ToUpper(strings.ReplaceAll(nodeType, " ", "_")))
+	}
+
+	switch len(children) {
+	case 0:
+	case 1:
+		must.NoError(stage.Add("inputStage", t.translate(children[0])))
+	default:
+		inputStages := wirebson.MakeArray(len(children))
+		for _, child := range children {
+			must.NoError(inputStages.Add(t.translate(child)))
+		}
+
+		must.NoError(stage.Add("inputStages", inputStages))
+	}
+
+	return stage
+}
+
+// indexScan returns IXSCAN stage for the given index scan node.
+func (t *planTranslator) indexScan(node map[string]any) *wirebson.Document {
+	pgName, _ := node["Index Name"].(string)
+
+	direction := "forward"
+	if d, _ := node["Scan Direction"].(string); d == "Backward" {
+		direction = "backward"
+	}
+
+	stage := t.stage(node, "IXSCAN", "indexName", t.indexName(pgName), "direction", direction)
+	t.addExamined(stage, node, "keysExamined", &t.stats.keysExamined)
+
+	return stage
+}
+
+// indexName returns MongoDB index name for the given PostgreSQL index name.
+//
+// If the name is unknown, the PostgreSQL name is returned as is.
+func (t *planTranslator) indexName(pgName string) string {
+	if strings.HasPrefix(pgName, pgPrimaryKeyPrefix) {
+		return "_id_"
+	}
+
+	if name, ok := t.indexNames[pgName]; ok {
+		return name
+	}
+
+	return pgName
+}
+
+// stage returns a new stage document with the given name, extra fields, and execution statistics if needed.
+func (t *planTranslator) stage(node map[string]any, name string, pairs ...any) *wirebson.Document {
+	stage := must.NotFail(wirebson.NewDocument(append([]any{"stage", name}, pairs...)...))
+
+	if !t.analyze {
+		return stage
+	}
+
+	must.NoError(stage.Add("nReturned", planRows(node)))
+	must.NoError(stage.Add("executionTimeMillisEstimate", planInt(node, "Actual Total Time")))
+
+	return stage
+}
+
+// addExamined adds the number of examined keys or documents to the stage and to the total.
+func (t *planTranslator) addExamined(stage *wirebson.Document, node map[string]any, field string, total *int32) {
+	if !t.analyze {
+		return
+	}
+
+	n := planRows(node) + planInt(node, "Rows Removed by Filter") + planInt(node, "Rows Removed by Index Recheck")
+	*total += n
+
+	must.NoError(stage.Add(field, n))
+}
+
+// planRows returns the total number of rows returned by the node over all loops.
+func planRows(node map[string]any) int32 {
+	loops := planInt(node, "Actual Loops")
+	if loops == 0 {
+		loops = 1
+	}
+
+	return planInt(node, "Actual Rows") * loops
+}
+
+// planInt returns the numeric field of the plan node as int32, or 0 if it is not present.
+func planInt(node map[string]any, field string) int32 {
+	f, _ := node[field].(float64)
+	return int32(f)
+}
+
+// planSummary returns MongoDB-style plan summary for the translated plan, like "COLLSCAN" or "IXSCAN a_1".
+//
+// Unlike MongoDB, index names are used instead of index keys.
+// Empty string is returned if the plan does not contain scans.
+func planSummary(stage *wirebson.Document) string {
+	var res []string
+
+	var walk func(*wirebson.Document)
+	walk = func(stage *wirebson.Document) {
+		var s string
+
+		switch name, _ := stage.Get("stage").(string); name {
+		case "COLLSCAN", "EOF":
+			s = name
+		case "IXSCAN":
+			indexName, _ := stage.Get("indexName").(string)
+			s = name + " " + indexName
+		}
+
+		if s != "" && !slices.Contains(res, s) {
+			res = append(res, s)
+		}
+
+		if input, ok := stage.Get("inputStage").(*wirebson.Document); ok {
+			walk(input)
+		}
+
+		if inputs, ok := stage.Get("inputStages").(*wirebson.Array); ok {
+			for v := range inputs.Values() {
+				if input, ok := v.(*wirebson.Document); ok {
+					walk(input)
+				}
+			}
+		}
+	}
+
+	walk(stage)
+
+	return strings.Join(res, ", ")
+}
+
+// pgIndexNames returns PostgreSQL names of all indexes used by the plan node and its children.
+func pgIndexNames(node map[string]any) []string {
+	var res []string
+
+	if name, ok := node["Index Name"].(string); ok && !strings.HasPrefix(name, pgPrimaryKeyPrefix) {
+		res = append(res, name)
+	}
+
+	if plans, ok := node["Plans"].([]any); ok {
+		for _, p := range plans {
+			if child, ok := p.(map[string]any); ok {
+				res = append(res, pgIndexNames(child)...)
+			}
+		}
+	}
+
+	return res
+}
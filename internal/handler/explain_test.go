@@ -0,0 +1,253 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestPlanTranslator(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		plan       string
+		indexNames map[string]string
+		analyze    bool
+
+		expected     *wirebson.Document
+		keysExamined int32
+		docsExamined int32
+		summary      string
+	}{
+		"CollScan": {
+			plan: `{"Node Type": "Custom Scan", "Plans": [{"Node Type": "Seq Scan", "Relation Name": "documents_1"}]}`,
+			expected: wirebson.MustDocument(
+				"stage", "COLLSCAN",
+				"direction", "forward",
+			),
+			summary: "COLLSCAN",
+		},
+		"CollScanAnalyze": {
+			plan: `{
+				"Node Type": "Seq Scan",
+				"Actual Total Time": 1.5,
+				"Actual Rows": 3,
+				"Actual Loops": 1,
+				"Rows Removed by Filter": 7
+			}`,
+			analyze: true,
+			expected: wirebson.MustDocument(
+				"stage", "COLLSCAN",
+				"direction", "forward",
+				"nReturned", int32(3),
+				"executionTimeMillisEstimate", int32(1),
+				"docsExamined", int32(10),
+			),
+			docsExamined: 10,
+			summary:      "COLLSCAN",
+		},
+		"IDIndex": {
+			plan: `{"Node Type": "Limit", "Plans": [
+				{"Node Type": "Index Scan", "Index Name": "collection_pk_1", "Scan Direction": "Forward"}
+			]}`,
+			expected: wirebson.MustDocument(
+				"stage", "LIMIT",
+				"inputStage", wirebson.MustDocument(
+					"stage", "FETCH",
+					"inputStage", wirebson.MustDocument(
+						"stage", "IXSCAN",
+						"indexName", "_id_",
+						"direction", "forward",
+					),
+				),
+			),
+			summary: "IXSCAN _id_",
+		},
+		"BitmapIndex": {
+			plan: `{"Node Type": "Bitmap Heap Scan", "Actual Rows": 2, "Actual Loops": 1, "Plans": [
+				{"Node Type": "Bitmap Index Scan", "Index Name": "documents_rum_index_3", "Actual Rows": 4, "Actual Loops": 1}
+			]}`,
+			indexNames: map[string]string{"documents_rum_index_3": "v_1"},
+			analyze:    true,
+			expected: wirebson.MustDocument(
+				"stage", "FETCH",
+				"nReturned", int32(2),
+				"executionTimeMillisEstimate", int32(0),
+				"docsExamined", int32(2),
+				"inputStage", wirebson.MustDocument(
+					"stage", "IXSCAN",
+					"indexName", "v_1",
+					"direction", "forward",
+					"nReturned", int32(4),
+					"executionTimeMillisEstimate", int32(0),
+					"keysExamined", int32(4),
+				),
+			),
+			keysExamined: 4,
+			docsExamined: 2,
+			summary:      "IXSCAN v_1",
+		},
+		"UnknownIndexName": {
+			plan: `{"Node Type": "Index Only Scan", "Index Name": "documents_rum_index_5", "Scan Direction": "Backward"}`,
+			expected: wirebson.MustDocument(
+				"stage", "IXSCAN",
+				"indexName", "documents_rum_index_5",
+				"direction", "backward",
+			),
+			summary: "IXSCAN documents_rum_index_5",
+		},
+		"Or": {
+			plan: `{"Node Type": "BitmapOr", "Plans": [
+				{"Node Type": "Bitmap Index Scan", "Index Name": "collection_pk_1"},
+				{"Node Type": "Bitmap Index Scan", "Index Name": "collection_pk_1"}
+			]}`,
+			expected: wirebson.MustDocument(
+				"stage", "OR",
+				"inputStages", wirebson.MustArray(
+					wirebson.MustDocument("stage", "IXSCAN", "indexName", "_id_", "direction", "forward"),
+					wirebson.MustDocument("stage", "IXSCAN", "indexName", "_id_", "direction", "forward"),
+				),
+			),
+			summary: "IXSCAN _id_",
+		},
+		"EOF": {
+			plan:     `{"Node Type": "Result"}`,
+			expected: wirebson.MustDocument("stage", "EOF"),
+			summary:  "EOF",
+		},
+		"Unknown": {
+			plan:     `{"Node Type": "Function Scan"}`,
+			expected: wirebson.MustDocument("stage", "FUNCTION_SCAN"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var node map[string]any
+			require.NoError(t, json.Unmarshal([]byte(tc.plan), &node))
+
+			tr := &planTranslator{indexNames: tc.indexNames, analyze: tc.analyze}
+			testutil.AssertEqual(t, tc.expected, tr.translate(node))
+			assert.Equal(t, tc.keysExamined, tr.stats.keysExamined)
+			assert.Equal(t, tc.docsExamined, tr.stats.docsExamined)
+			assert.Equal(t, tc.summary, planSummary(tr.translate(node)))
+		})
+	}
+}
+
+func TestPGIndexNames(t *testing.T) {
+	t.Parallel()
+
+	var node map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{"Node Type": "BitmapOr", "Plans": [
+		{"Node Type": "Bitmap Index Scan", "Index Name": "collection_pk_1"},
+		{"Node Type": "Bitmap Index Scan", "Index Name": "documents_rum_index_2"}
+	]}`), &node))
+
+	assert.Equal(t, []string{"documents_rum_index_2"}, pgIndexNames(node))
+}
+
+func TestExplainWriteFindSpec(t *testing.T) {
+	t.Parallel()
+
+	filter := wirebson.MustDocument("v", int32(42))
+
+	for name, tc := range map[string]struct {
+		doc *wirebson.Document
+
+		expected *wirebson.Document
+		stage    string
+		err      bool
+	}{
+		"UpdateOne": {
+			doc: wirebson.MustDocument(
+				"update", "c",
+				"updates", wirebson.MustArray(wirebson.MustDocument("q", filter, "u", wirebson.MustDocument())),
+			),
+			expected: wirebson.MustDocument("find", "c", "filter", filter, "limit", int64(1)),
+			stage:    "UPDATE",
+		},
+		"UpdateMany": {
+			doc: wirebson.MustDocument(
+				"update", "c",
+				"updates", wirebson.MustArray(wirebson.MustDocument("q", filter, "multi", true)),
+			),
+			expected: wirebson.MustDocument("find", "c", "filter", filter),
+			stage:    "UPDATE",
+		},
+		"UpdateBatch": {
+			doc: wirebson.MustDocument(
+				"update", "c",
+				"updates", wirebson.MustArray(wirebson.MustDocument("q", filter), wirebson.MustDocument("q", filter)),
+			),
+			err: true,
+		},
+		"DeleteOne": {
+			doc: wirebson.MustDocument(
+				"delete", "c",
+				"deletes", wirebson.MustArray(wirebson.MustDocument("q", filter, "limit", int32(1))),
+			),
+			expected: wirebson.MustDocument("find", "c", "filter", filter, "limit", int64(1)),
+			stage:    "DELETE",
+		},
+		"DeleteMany": {
+			doc: wirebson.MustDocument(
+				"delete", "c",
+				"deletes", wirebson.MustArray(wirebson.MustDocument("q", filter, "limit", int32(0))),
+			),
+			expected: wirebson.MustDocument("find", "c", "filter", filter),
+			stage:    "DELETE",
+		},
+		"FindAndModify": {
+			doc: wirebson.MustDocument(
+				"findAndModify", "c",
+				"query", filter,
+				"sort", wirebson.MustDocument("v", int32(-1)),
+				"remove", true,
+			),
+			expected: wirebson.MustDocument(
+				"find", "c",
+				"filter", filter,
+				"sort", wirebson.MustDocument("v", int32(-1)),
+				"limit", int64(1),
+			),
+			stage: "DELETE",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			raw, stage, err := explainWriteFindSpec("c", tc.doc)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.stage, stage)
+
+			actual, err := raw.Decode()
+			require.NoError(t, err)
+			testutil.AssertEqual(t, tc.expected, actual)
+		})
+	}
+}
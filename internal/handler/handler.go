@@ -16,8 +16,11 @@
 package handler
 
 import (
+	"cmp"
 	"context"
 	"log/slog"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -25,7 +28,13 @@
 	"github.com/FerretDB/FerretDB/v2/internal/clientconn/connmetrics"
 	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
 	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/operation"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/profiler"
 	"github.com/FerretDB/FerretDB/v2/internal/handler/session"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/topology"
+	"github.com/FerretDB/FerretDB/v2/internal/util/audit"
+	"github.com/FerretDB/FerretDB/v2/internal/util/ctxutil"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
 	"github.com/FerretDB/FerretDB/v2/internal/util/state"
 )
@@ -59,6 +68,12 @@ type Handler struct {
 	*NewOpts
 	commands map[string]*command
 	s        *session.Registry
+	ops      *operation.Registry
+	profiler *profiler.Profiler
+	params   *parameters
+	draining atomic.Bool
+
+	clusterTime atomic.Uint64 // see cluster_time.go
 }
 
 // NewOpts represents handler configuration.
@@ -68,31 +83,48 @@ type NewOpts struct {
 	Pool *documentdb.Pool
 	Auth bool
 
-	TCPHost     string
+	Host        string // advertised address reported by `hello` with ReplSetName
 	ReplSetName string
+	Topology    *topology.Topology // nil disables replica set topology emulation; requires ReplSetName
 
 	L             *slog.Logger
 	ConnMetrics   *connmetrics.ConnMetrics
 	StateProvider *state.Provider
+	Audit         *audit.Auditor // nil disables auditing
+
+	// Startup values of server parameters that could be changed by `setParameter`.
+	LogLevel        *slog.LevelVar // nil disables changing log level
+	SlowOpThreshold time.Duration  // zero value disables slow operations logging
+	CursorTimeout   time.Duration  // zero value means 10 minutes
+	SessionTimeout  time.Duration  // zero value means 30 minutes
+	MaxConnections  int32          // zero value means no limit
 
 	SessionCleanupInterval time.Duration
 }
 
 // New returns a new handler.
-// It takes over the passed pool.
+// It takes over the passed pool and auditor.
 // [Handler.Run] must be called on the returned value.
 func New(opts *NewOpts) (*Handler, error) {
-	sessionTimeout := time.Duration(session.LogicalSessionTimeoutMinutes) * time.Minute
-
 	// we rely on on it in the `getLog` implementation
 	// TODO https://github.com/FerretDB/FerretDB/issues/4750
 	_ = opts.L.Handler().(*logging.Handler)
 
+	if opts.SessionTimeout < 0 || opts.SessionTimeout%time.Minute != 0 {
+		return nil, lazyerrors.Errorf("session timeout %s is not a whole number of minutes", opts.SessionTimeout)
+	}
+
+	s := session.NewRegistry(cmp.Or(opts.SessionTimeout, defaultSessionTimeout), opts.L)
+
 	h := &Handler{
 		NewOpts: opts,
-		s:       session.NewRegistry(sessionTimeout, opts.L),
+		s:       s,
+		ops:     operation.NewRegistry(),
+		params:  newParameters(opts, s),
 	}
 
+	h.profiler = profiler.New(opts.Pool, logging.WithName(opts.L, "profiler"), h.PlanSummary)
+
 	h.initCommands()
 
 	return h, nil
@@ -100,11 +132,45 @@ func New(opts *NewOpts) (*Handler, error) {
 
 // Run runs the handler until ctx is canceled.
 //
-// When this method returns, handler is stopped and pool is closed.
+// When this method returns, handler is stopped, and pool and auditor are closed.
 func (h *Handler) Run(ctx context.Context) {
+	// background goroutines use the pool, so they should exit before it is closed
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		h.profiler.Run(ctx)
+	}()
+
+	if h.Topology != nil {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			h.Topology.Run(ctx)
+		}()
+	}
+
 	defer func() {
+		wg.Wait()
+
 		h.s.Stop()
+
+		// ctx is already canceled, but we want to inherit its values
+		closeCtx, closeCancel := ctxutil.WithDelay(ctx, ctxutil.DefaultDelay)
+		n := h.Pool.CloseCursors(closeCtx)
+		closeCancel(nil)
+
+		h.L.InfoContext(ctx, "Cursors closed", slog.Int("cursors", n))
+
 		h.Pool.Close()
+
+		if h.Audit != nil {
+			h.Audit.Close()
+		}
+
 		h.L.InfoContext(ctx, "Handler stopped")
 	}()
 
@@ -129,10 +195,50 @@ func (h *Handler) Run(ctx context.Context) {
 			for _, cursorID := range cursorIDs {
 				_ = h.Pool.KillCursor(ctx, cursorID)
 			}
+
+			if n := h.Pool.CloseIdleCursors(ctx, h.params.cursorTimeout.get()); n > 0 {
+				h.L.InfoContext(ctx, "Idle cursors closed", slog.Int("cursors", n))
+			}
 		}
 	}
 }
 
+// Drain marks handler as shutting down.
+// After that, `hello` and similar commands return an error
+// that makes drivers select another server.
+func (h *Handler) Drain() {
+	h.draining.Store(true)
+}
+
+// Draining returns true if [Handler.Drain] was called.
+func (h *Handler) Draining() bool {
+	return h.draining.Load()
+}
+
+// SlowOpThreshold returns the current slow operation threshold.
+// Zero value disables slow operations logging.
+func (h *Handler) SlowOpThreshold() time.Duration {
+	return h.params.slowOpThreshold.get()
+}
+
+// MaxConnections returns the current maximum number of client connections.
+// Zero value means no limit.
+func (h *Handler) MaxConnections() int {
+	return int(h.params.maxConnections.get())
+}
+
+// Operations returns the registry of in-progress operations.
+// Client connections register operations there; `currentOp` and `killOp` commands use it.
+func (h *Handler) Operations() *operation.Registry {
+	return h.ops
+}
+
+// IsCommand returns true if the command with the given name is known to the handler.
+func (h *Handler) IsCommand(name string) bool {
+	_, ok := h.commands[name]
+	return ok
+}
+
 // Handle processes a request.
 func (h *Handler) Handle(ctx context.Context, req *middleware.Request) (*middleware.Response, error) {
 	switch {
@@ -146,7 +252,28 @@ func (h *Handler) Handle(ctx context.Context, req *middleware.Request) (*middlew
 
 		cmd, ok := h.commands[msgCmd]
 		if ok && cmd.handler != nil {
-			return cmd.handler(ctx, req)
+			wc, err := getWriteConcern(doc)
+			if err != nil {
+				return nil, err
+			}
+
+			start := time.Now()
+
+			res, err := cmd.handler(ctx, req)
+			if err == nil && wc != nil {
+				res, err = h.waitForWriteConcern(ctx, wc, res)
+			}
+
+			if err == nil {
+				res, err = h.addClusterTime(ctx, doc, res)
+			}
+
+			d := time.Since(start)
+
+			h.recordLatency(doc, d, err)
+			h.profile(ctx, doc, res, err, d)
+
+			return res, err
 		}
 
 		return notFound(msgCmd)(ctx, req)
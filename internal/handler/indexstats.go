@@ -0,0 +1,390 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// rewriteIndexStats replaces the leading `$indexStats` stage of the aggregation pipeline
+// with `$documents` stage containing index usage statistics, so the rest of the pipeline
+// is executed by DocumentDB as usual.
+//
+// If the pipeline does not start with `$indexStats`, the spec is returned as is.
+func (h *Handler) rewriteIndexStats(ctx context.Context, dbName string, doc *wirebson.Document, spec wirebson.RawDocument) (wirebson.RawDocument, error) {
+	collection, pipeline, stage := firstStage(doc)
+	if stage == nil || stage.Command() != "$indexStats" {
+		return spec, nil
+	}
+
+	if arg, _ := stage.Get("$indexStats").(wirebson.AnyDocument); arg == nil || must.NotFail(arg.Decode()).Len() != 0 {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrLocation28803,
+			"The $indexStats stage specification must be an empty object",
+			"aggregate",
+		)
+	}
+
+	stats, err := h.indexStats(ctx, dbName, collection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return replaceFirstStage(doc, pipeline, stats)
+}
+
+// firstStage returns the collection name, the pipeline, and the decoded first stage of the `aggregate` command.
+// The returned stage is nil if the command is not a collection aggregation or the pipeline is empty or invalid.
+func firstStage(doc *wirebson.Document) (string, *wirebson.Array, *wirebson.Document) {
+	collection, ok := doc.Get("aggregate").(string)
+	if !ok {
+		return "", nil, nil
+	}
+
+	pipelineV, _ := doc.Get("pipeline").(wirebson.AnyArray)
+	if pipelineV == nil {
+		return "", nil, nil
+	}
+
+	pipeline, err := pipelineV.Decode()
+	if err != nil || pipeline.Len() == 0 {
+		return "", nil, nil
+	}
+
+	stageV, _ := pipeline.Get(0).(wirebson.AnyDocument)
+	if stageV == nil {
+		return "", nil, nil
+	}
+
+	stage, err := stageV.Decode()
+	if err != nil {
+		return "", nil, nil
+	}
+
+	return collection, pipeline, stage
+}
+
+// replaceFirstStage returns the `aggregate` command spec with the first stage of the pipeline
+// replaced by `$documents` stage with the given documents.
+func replaceFirstStage(doc *wirebson.Document, pipeline, docs *wirebson.Array) (wirebson.RawDocument, error) {
+	newPipeline := wirebson.MakeArray(pipeline.Len())
+	must.NoError(newPipeline.Add(must.NotFail(wirebson.NewDocument("$documents", docs))))
+
+	for i := 1; i < pipeline.Len(); i++ {
+		must.NoError(newPipeline.Add(pipeline.Get(i)))
+	}
+
+	return replacePipeline(doc, int32(1), newPipeline)
+}
+
+// replacePipeline returns the `aggregate` command spec with the given `aggregate` field value and pipeline.
+func replacePipeline(doc *wirebson.Document, aggregate any, pipeline *wirebson.Array) (wirebson.RawDocument, error) {
+	newDoc := wirebson.MakeDocument(doc.Len())
+
+	for k, v := range doc.All() {
+		switch k {
+		case "aggregate":
+			v = aggregate
+		case "pipeline":
+			v = pipeline
+		}
+
+		must.NoError(newDoc.Add(k, v))
+	}
+
+	res, err := newDoc.Encode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// indexStats returns `$indexStats` documents for the given collection.
+func (h *Handler) indexStats(ctx context.Context, dbName, collection string) (*wirebson.Array, error) {
+	usage, err := h.Pool.IndexUsage(ctx, dbName, collection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, port, e := net.SplitHostPort(h.Host); e == nil {
+		host = net.JoinHostPort(host, port)
+	}
+
+	res := wirebson.MakeArray(len(usage))
+
+	for _, u := range usage {
+		must.NoError(res.Add(must.NotFail(wirebson.NewDocument(
+			"name", u.Name,
+			"key", u.Key,
+			"host", host,
+			"accesses", indexAccesses(&u),
+			"spec", u.Spec,
+		))))
+	}
+
+	return res, nil
+}
+
+// indexAccesses returns `accesses` document for the given index usage.
+func indexAccesses(u *documentdb.IndexUsage) *wirebson.Document {
+	return must.NotFail(wirebson.NewDocument(
+		"ops", u.Ops,
+		"since", u.Since,
+	))
+}
+
+// addIndexAccesses adds `accesses` field to each index in `collStats.indexDetails`.
+func (h *Handler) addIndexAccesses(ctx context.Context, dbName, collection string, res *wirebson.Document) error {
+	detailsV, _ := res.Get("indexDetails").(wirebson.AnyDocument)
+	if detailsV == nil {
+		return nil
+	}
+
+	details, err := detailsV.Decode()
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if details.Len() == 0 {
+		return nil
+	}
+
+	usage, err := h.Pool.IndexUsage(ctx, dbName, collection)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	for _, u := range usage {
+		var index *wirebson.Document
+
+		switch v := details.Get(u.Name).(type) {
+		case wirebson.AnyDocument:
+			if index, err = v.Decode(); err != nil {
+				return lazyerrors.Error(err)
+			}
+		default:
+			continue
+		}
+
+		index.Remove("accesses")
+		must.NoError(index.Add("accesses", indexAccesses(&u)))
+		must.NoError(details.Replace(u.Name, index))
+	}
+
+	if err = res.Replace("indexDetails", details); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// redundantIndexes returns a map from the index of each redundant entry in usage
+// (that should be sorted by database and collection) to the index of the entry that makes it redundant.
+//
+// An index is redundant if another index of the same collection has the same key,
+// or a key that starts with all fields of its key in the same order and directions,
+// and the same options (partial filter, sparse, collation, TTL, text, vector search, etc).
+// The `_id_` index and unique indexes are never redundant.
+func redundantIndexes(usage []documentdb.IndexUsage) map[int]int {
+	res := map[int]int{}
+
+	keys := make([]*wirebson.Document, len(usage))
+	options := make([]string, len(usage))
+
+	for i, u := range usage {
+		keys[i], _ = u.Key.Decode()
+		options[i] = indexOptions(u.Spec)
+	}
+
+	for i, u := range usage {
+		if u.Name == "_id_" || u.Unique || keys[i] == nil {
+			continue
+		}
+
+		for j, other := range usage {
+			if i == j || other.Database != u.Database || other.Collection != u.Collection || keys[j] == nil {
+				continue
+			}
+
+			if options[i] != options[j] {
+				continue
+			}
+
+			if keys[j].Len() < keys[i].Len() || !indexKeyPrefix(keys[i], keys[j]) {
+				continue
+			}
+
+			// of two identical indexes, only the later one is redundant
+			if keys[j].Len() == keys[i].Len() && !other.Unique && j > i {
+				continue
+			}
+
+			res[i] = j
+
+			break
+		}
+	}
+
+	return res
+}
+
+// indexOptions returns encoded index options in the given specification
+// that affect which documents are indexed and how, for comparison.
+//
+// Index version, key, name, and uniqueness are not included.
+func indexOptions(spec wirebson.RawDocument) string {
+	doc, err := spec.Decode()
+	if err != nil {
+		return ""
+	}
+
+	res := wirebson.MakeDocument(doc.Len())
+
+	for k, v := range doc.All() {
+		switch k {
+		case "v", "key", "name", "ns", "unique":
+			continue
+		}
+
+		must.NoError(res.Add(k, v))
+	}
+
+	raw, err := res.Encode()
+	if err != nil {
+		return ""
+	}
+
+	return string(raw)
+}
+
+// indexKeyPrefix returns true if all fields of prefix key are the first fields of key.
+func indexKeyPrefix(prefix, key *wirebson.Document) bool {
+	for i := range prefix.Len() {
+		pk, pv := prefix.GetByIndex(i)
+		k, v := key.GetByIndex(i)
+
+		if pk != k || fmt.Sprint(pv) != fmt.Sprint(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// indexUsagePageTemplate is the index usage page template.
+var indexUsagePageTemplate = template.Must(template.New("indexes").Parse(`
+	<html>
+	<body>
+	<p>Index scans are counted on the PostgreSQL primary and available streaming replicas.</p>
+	<h2>Unused indexes</h2>
+	<table border="1">
+	<tr><th>Namespace</th><th>Index</th><th>Key</th><th>Since</th></tr>
+	{{range .Unused}}
+		<tr><td>{{.Namespace}}</td><td>{{.Name}}</td><td>{{.Key}}</td><td>{{.Since}}</td></tr>
+	{{end}}
+	</table>
+	<h2>Duplicate indexes</h2>
+	<table border="1">
+	<tr><th>Namespace</th><th>Index</th><th>Key</th><th>Covered by</th><th>Accesses</th></tr>
+	{{range .Duplicate}}
+		<tr><td>{{.Namespace}}</td><td>{{.Name}}</td><td>{{.Key}}</td><td>{{.CoveredBy}}</td><td>{{.Ops}}</td></tr>
+	{{end}}
+	</table>
+	</body>
+	</html>
+`))
+
+// indexUsageRow represents a single row of the index usage page.
+type indexUsageRow struct {
+	Namespace string
+	Name      string
+	Key       string
+	Since     string
+	CoveredBy string
+	Ops       int64
+}
+
+// ServeIndexUsage renders the debug page with unused and duplicate indexes of all collections.
+func (h *Handler) ServeIndexUsage(rw http.ResponseWriter, req *http.Request) {
+	usage, err := h.Pool.IndexUsage(req.Context(), "", "")
+	if err != nil {
+		h.L.WarnContext(req.Context(), "Failed to get index usage", logging.Error(err))
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	row := func(u *documentdb.IndexUsage) indexUsageRow {
+		key := "{}"
+		if doc, e := u.Key.Decode(); e == nil {
+			key = doc.LogMessage()
+		}
+
+		return indexUsageRow{
+			Namespace: u.Database + "." + u.Collection,
+			Name:      u.Name,
+			Key:       key,
+			Since:     u.Since.Format("2006-01-02T15:04:05Z07:00"),
+			Ops:       u.Ops,
+		}
+	}
+
+	var data struct {
+		Unused    []indexUsageRow
+		Duplicate []indexUsageRow
+	}
+
+	for _, u := range usage {
+		if u.Ops == 0 && u.Name != "_id_" {
+			data.Unused = append(data.Unused, row(&u))
+		}
+	}
+
+	redundant := redundantIndexes(usage)
+
+	for i, u := range usage {
+		j, ok := redundant[i]
+		if !ok {
+			continue
+		}
+
+		r := row(&u)
+		r.CoveredBy = usage[j].Name
+		data.Duplicate = append(data.Duplicate, r)
+	}
+
+	if err = indexUsagePageTemplate.Execute(rw, data); err != nil {
+		h.L.WarnContext(req.Context(), "Failed to render index usage", logging.Error(err))
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+func TestRedundantIndexes(t *testing.T) {
+	t.Parallel()
+
+	index := func(collection, name string, unique bool, key ...any) documentdb.IndexUsage {
+		keyDoc := must.NotFail(wirebson.MustDocument(key...).Encode())
+
+		return documentdb.IndexUsage{
+			Database:   "db",
+			Collection: collection,
+			Name:       name,
+			Key:        keyDoc,
+			Unique:     unique,
+			Spec:       must.NotFail(wirebson.MustDocument("v", int32(2), "key", keyDoc, "name", name).Encode()),
+		}
+	}
+
+	withOptions := func(u documentdb.IndexUsage, options ...any) documentdb.IndexUsage {
+		spec := must.NotFail(u.Spec.Decode())
+
+		for i := 0; i < len(options); i += 2 {
+			must.NoError(spec.Add(options[i].(string), options[i+1]))
+		}
+
+		u.Spec = must.NotFail(spec.Encode())
+
+		return u
+	}
+
+	usage := []documentdb.IndexUsage{
+		index("c1", "_id_", false, "_id", int32(1)),
+		index("c1", "a_1", false, "a", int32(1)),
+		index("c1", "a_1_b_1", false, "a", int32(1), "b", int32(1)),
+		index("c1", "a_-1", false, "a", int32(-1)),
+		index("c1", "b_1", true, "b", int32(1)),
+		index("c1", "b_1_c_1", false, "b", int32(1), "c", int32(1)),
+		index("c1", "c_1", false, "c", int32(1)),
+		index("c1", "c_1_copy", false, "c", float64(1)),
+		index("c1", "_id_1_d_1", false, "_id", int32(1), "d", int32(1)),
+		index("c2", "a_1", false, "a", int32(1)),
+		withOptions(index("c2", "a_1_partial", false, "a", int32(1)), "partialFilterExpression", wirebson.MustDocument("b", int32(1))),
+		withOptions(index("c2", "a_1_sparse", false, "a", int32(1)), "sparse", true),
+		withOptions(index("c2", "a_1_collation", false, "a", int32(1)), "collation", wirebson.MustDocument("locale", "fr")),
+		withOptions(index("c2", "a_1_ttl", false, "a", int32(1)), "expireAfterSeconds", int32(60)),
+		withOptions(index("c2", "a_1_sparse_b_1", false, "a", int32(1), "b", int32(1)), "sparse", true),
+	}
+
+	expected := map[int]int{
+		1:  2,  // a_1 is covered by a_1_b_1
+		7:  6,  // c_1_copy is the same as c_1
+		11: 14, // a_1_sparse is covered by a_1_sparse_b_1
+	}
+
+	assert.Equal(t, expected, redundantIndexes(usage))
+}
@@ -19,6 +19,7 @@
 	"sync/atomic"
 
 	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
 )
 
 // Request represents incoming command from the client.
@@ -111,3 +112,39 @@ func init() {
 	// so generated IDs are noticeably different from IDs from typical clients
 	lastRequestID.Store(1_000_000_000)
 }
+
+// AggregateWrites returns true if the given document is an `aggregate` command
+// with the last stage of the pipeline being $out or $merge.
+// Such pipelines write data.
+func AggregateWrites(doc *wirebson.Document) bool {
+	if doc.Command() != "aggregate" {
+		return false
+	}
+
+	pipeline, _ := doc.Get("pipeline").(wirebson.AnyArray)
+	if pipeline == nil {
+		return false
+	}
+
+	arr, err := pipeline.Decode()
+	if err != nil || arr.Len() == 0 {
+		return false
+	}
+
+	stage, _ := arr.Get(arr.Len() - 1).(wirebson.AnyDocument)
+	if stage == nil {
+		return false
+	}
+
+	stageDoc, err := stage.Decode()
+	if err != nil {
+		return false
+	}
+
+	switch stageDoc.Command() {
+	case "$out", "$merge":
+		return true
+	default:
+		return false
+	}
+}
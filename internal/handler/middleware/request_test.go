@@ -0,0 +1,41 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateWrites(t *testing.T) {
+	t.Parallel()
+
+	pipeline := func(stages ...any) *wirebson.Document {
+		return wirebson.MustDocument("aggregate", "test", "pipeline", wirebson.MustArray(stages...), "$db", "test")
+	}
+
+	assert.False(t, AggregateWrites(pipeline()))
+	assert.False(t, AggregateWrites(pipeline(wirebson.MustDocument("$match", wirebson.MakeDocument(0)))))
+	assert.True(t, AggregateWrites(pipeline(wirebson.MustDocument("$out", "out"))))
+	assert.True(t, AggregateWrites(pipeline(
+		wirebson.MustDocument("$match", wirebson.MakeDocument(0)),
+		wirebson.MustDocument("$merge", wirebson.MustDocument("into", "out")),
+	)))
+	assert.False(t, AggregateWrites(wirebson.MustDocument(
+		"find", "test", "pipeline", wirebson.MustArray(wirebson.MustDocument("$out", "out")),
+	)))
+}
@@ -17,7 +17,10 @@
 import (
 	"context"
 
+	"github.com/FerretDB/wire/wirebson"
+
 	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 )
 
@@ -45,9 +48,48 @@ func (h *Handler) msgAggregate(connCtx context.Context, req *middleware.Request)
 		return nil, err
 	}
 
-	page, cursorID, err := h.Pool.Aggregate(connCtx, dbName, spec)
+	if spec, err = h.rewriteIndexStats(connCtx, dbName, doc, spec); err != nil {
+		return nil, err
+	}
+
+	if spec, err = h.rewriteListSearchIndexes(connCtx, dbName, doc, spec); err != nil {
+		return nil, err
+	}
+
+	if spec, err = h.rewriteSearch(connCtx, dbName, doc, spec); err != nil {
+		return nil, err
+	}
+
+	rc, err := getReadConcern(doc)
 	if err != nil {
-		return nil, lazyerrors.Error(err)
+		return nil, err
+	}
+
+	var page wirebson.RawDocument
+	var cursorID int64
+
+	if rc.snapshot() {
+		if middleware.AggregateWrites(doc) {
+			msg := "$out and $merge stages cannot be used with readConcern level snapshot"
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, "aggregate")
+		}
+
+		if page, cursorID, err = h.readSnapshot(connCtx, dbName, spec, doc, rc, h.Pool.AggregateSnapshot); err != nil {
+			return nil, err
+		}
+	} else {
+		// pipelines with $out and $merge stages write data, so they are always executed on the primary
+		pool := h.Pool
+
+		if !middleware.AggregateWrites(doc) {
+			if pool, err = h.readPool(connCtx, doc); err != nil {
+				return nil, err
+			}
+		}
+
+		if page, cursorID, err = pool.Aggregate(connCtx, dbName, spec); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
 	}
 
 	h.s.AddCursor(connCtx, userID, sessionID, cursorID)
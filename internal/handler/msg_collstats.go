@@ -89,5 +89,17 @@ func (h *Handler) msgCollStats(connCtx context.Context, req *middleware.Request)
 		return nil, lazyerrors.Error(err)
 	}
 
-	return middleware.ResponseMsg(page)
+	// index usage is fetched using another connection
+	conn.Release()
+
+	res, err := page.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = h.addIndexAccesses(connCtx, dbName, collection, res); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return middleware.ResponseMsg(res)
 }
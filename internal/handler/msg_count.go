@@ -48,9 +48,14 @@ func (h *Handler) msgCount(connCtx context.Context, req *middleware.Request) (*m
 		return nil, lazyerrors.Error(err)
 	}
 
+	pool, err := h.readPool(connCtx, doc)
+	if err != nil {
+		return nil, err
+	}
+
 	var res wirebson.RawDocument
 
-	err = h.Pool.WithConn(func(conn *pgx.Conn) error {
+	err = pool.WithConn(func(conn *pgx.Conn) error {
 		res, err = documentdb_api.CountQuery(connCtx, conn, h.L, dbName, spec)
 		return err
 	})
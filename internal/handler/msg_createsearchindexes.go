@@ -0,0 +1,157 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// msgCreateSearchIndexes implements `createSearchIndexes` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) msgCreateSearchIndexes(connCtx context.Context, req *middleware.Request) (*middleware.Response, error) {
+	doc, err := req.OpMsg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, doc); err != nil {
+		return nil, err
+	}
+
+	command := doc.Command()
+
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := getRequiredParam[string](doc, command)
+	if err != nil {
+		return nil, err
+	}
+
+	indexesV, ok := doc.Get("indexes").(wirebson.AnyArray)
+	if !ok {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrLocation40414,
+			"BSON field 'createSearchIndexes.indexes' is missing but a required field",
+			command,
+		)
+	}
+
+	indexes, err := indexesV.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	specs := wirebson.MakeArray(indexes.Len())
+	names := make([]string, 0, indexes.Len())
+
+	for v := range indexes.Values() {
+		indexV, ok := v.(wirebson.AnyDocument)
+		if !ok {
+			msg := fmt.Sprintf("BSON field 'createSearchIndexes.indexes' element has type %s (expected object)", aliasFromType(v))
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, command)
+		}
+
+		var index *wirebson.Document
+		if index, err = indexV.Decode(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		var spec *wirebson.Document
+		if spec, err = searchIndexSpecFromCommand(command, index); err != nil {
+			return nil, err
+		}
+
+		must.NoError(specs.Add(spec))
+		names = append(names, spec.Get("name").(string))
+	}
+
+	createSpec := must.NotFail(must.NotFail(wirebson.NewDocument(
+		"createIndexes", collection,
+		"indexes", specs,
+		"$db", dbName,
+	)).Encode())
+
+	conn, err := h.Pool.Acquire()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	_, err = h.createIndexes(connCtx, conn, command, dbName, createSpec)
+
+	conn.Release()
+
+	if err != nil {
+		return nil, err
+	}
+
+	builds, err := h.Pool.IndexBuilds(connCtx, dbName, collection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	created := wirebson.MakeArray(len(names))
+
+	for _, name := range names {
+		must.NoError(created.Add(must.NotFail(wirebson.NewDocument(
+			"id", strconv.FormatInt(builds[name].ID, 10),
+			"name", name,
+		))))
+	}
+
+	return middleware.ResponseMsg(wirebson.MustDocument(
+		"indexesCreated", created,
+		"ok", float64(1),
+	))
+}
+
+// searchIndexSpecFromCommand returns DocumentDB index specification
+// for the search index document of `createSearchIndexes` or `updateSearchIndex` command.
+func searchIndexSpecFromCommand(command string, index *wirebson.Document) (*wirebson.Document, error) {
+	name, err := getOptionalParam(index, "name", searchIndexDefaultName)
+	if err != nil {
+		return nil, err
+	}
+
+	typ, err := getOptionalParam(index, "type", searchIndexTypeSearch)
+	if err != nil {
+		return nil, err
+	}
+
+	defV, ok := index.Get("definition").(wirebson.AnyDocument)
+	if !ok {
+		msg := fmt.Sprintf("BSON field '%s.definition' is missing but a required field", command)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrLocation40414, msg, command)
+	}
+
+	def, err := defV.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return searchIndexSpec(command, name, typ, def)
+}
@@ -16,17 +16,56 @@
 
 import (
 	"context"
+	"slices"
+	"time"
 
 	"github.com/FerretDB/wire/wirebson"
 	"github.com/jackc/pgx/v5"
 
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api_catalog"
 	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/operation"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 )
 
+// redactedCommands contains commands with sensitive arguments that are not reported by `currentOp`.
+var redactedCommands = []string{
+	"authenticate",
+	"createUser",
+	"saslContinue",
+	"saslStart",
+	"updateUser",
+}
+
+// opType returns operation type for the given command name
+// as reported by `currentOp` and the profiler.
+func opType(command string) string {
+	switch command {
+	case "find":
+		return "query"
+	case "getMore":
+		return "getmore"
+	case "insert", "update":
+		return command
+	case "delete":
+		return "remove"
+	default:
+		return "command"
+	}
+}
+
 // msgCurrentOp implements `currentOp` command.
 //
+// Operations reported by DocumentDB are merged with operations in progress in FerretDB.
+// Filters and `$ownOps` are applied to both.
+//
+// Users other than superusers see only their own FerretDB operations;
+// DocumentDB operations are not reported to them
+// because they could not be attributed to FerretDB users.
+//
 // The passed context is canceled when the client connection is closed.
 //
 // TODO https://github.com/FerretDB/FerretDB/issues/3974
@@ -40,15 +79,202 @@ func (h *Handler) msgCurrentOp(connCtx context.Context, req *middleware.Request)
 		return nil, err
 	}
 
-	var res wirebson.RawDocument
+	p, err := h.userPrivileges(connCtx)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	doc := wirebson.MustDocument("inprog", wirebson.MakeArray(0), "ok", float64(1))
+
+	if p.Superuser {
+		var res wirebson.RawDocument
+
+		err = h.Pool.WithConn(func(conn *pgx.Conn) error {
+			res, err = documentdb_api.CurrentOpCommand(connCtx, conn, h.L, spec)
+			return err
+		})
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if doc, err = res.Decode(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	var inprog *wirebson.Array
+
+	switch v := doc.Get("inprog").(type) {
+	case wirebson.AnyArray:
+		if inprog, err = v.Decode(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	default:
+		inprog = wirebson.MakeArray(0)
+	}
+
+	specDoc, err := spec.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	ownOps, err := getOptionalParamAny(specDoc, "$ownOps", false)
+	if err != nil {
+		return nil, err
+	}
+
+	own, err := getBoolParam("$ownOps", ownOps)
+	if err != nil {
+		return nil, err
+	}
+
+	own = own || !p.Superuser
+
+	username := conninfo.Get(connCtx).Conv().Username()
+
+	var entries []*wirebson.Document
+
+	for _, op := range h.ops.All() {
+		if own && op.Conn.Conv().Username() != username {
+			continue
+		}
+
+		var entry *wirebson.Document
+		if entry, err = currentOpEntry(op); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if entries, err = h.filterCurrentOp(connCtx, specDoc, entries); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		must.NoError(inprog.Add(entry))
+	}
+
+	if doc.Get("inprog") == nil {
+		must.NoError(doc.Add("inprog", inprog))
+	} else {
+		must.NoError(doc.Replace("inprog", inprog))
+	}
+
+	return middleware.ResponseMsg(doc)
+}
+
+// filterCurrentOp returns `currentOp` entries of FerretDB operations that match the command's filter.
+//
+// The filter is evaluated by DocumentDB, like the filter for its own operations.
+func (h *Handler) filterCurrentOp(ctx context.Context, spec *wirebson.Document, entries []*wirebson.Document) ([]*wirebson.Document, error) {
+	filter := wirebson.MakeDocument(0)
+
+	for k, v := range spec.All() {
+		switch k {
+		case spec.Command(), "$ownOps", "$all", "$db", "lsid", "comment", "$clusterTime", "$readPreference", "apiVersion", "apiStrict", "apiDeprecationErrors":
+			continue
+		}
+
+		must.NoError(filter.Add(k, v))
+	}
+
+	if filter.Len() == 0 || len(entries) == 0 {
+		return entries, nil
+	}
+
+	f, err := filter.Encode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := make([]*wirebson.Document, 0, len(entries))
 
 	err = h.Pool.WithConn(func(conn *pgx.Conn) error {
-		res, err = documentdb_api.CurrentOpCommand(connCtx, conn, h.L, spec)
-		return err
+		for _, entry := range entries {
+			var e wirebson.RawDocument
+			if e, err = entry.Encode(); err != nil {
+				return lazyerrors.Error(err)
+			}
+
+			var match bool
+			if match, err = documentdb_api_catalog.BsonQueryMatch(ctx, conn, h.L, e, f); err != nil {
+				return err
+			}
+
+			if match {
+				res = append(res, entry)
+			}
+		}
+
+		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// currentOpEntry returns `currentOp`'s `inprog` array element for the given operation.
+func currentOpEntry(op *operation.Operation) (*wirebson.Document, error) {
+	command, err := op.Command.Decode()
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	return middleware.ResponseMsg(res)
+	name := command.Command()
+
+	ns := op.DB + ".$cmd"
+	if collection, ok := command.Get(name).(string); ok && collection != "" {
+		ns = op.DB + "." + collection
+	}
+
+	if slices.Contains(redactedCommands, name) {
+		command = wirebson.MustDocument(name, "###")
+	}
+
+	total, pg := op.Timings()
+
+	res := wirebson.MustDocument(
+		"type", "op",
+		"opid", op.ID,
+		"active", true,
+		"currentOpTime", time.Now().UTC().Format(time.RFC3339Nano),
+		"op", opType(name),
+		"ns", ns,
+		"command", command,
+	)
+
+	if op.Conn.Peer.IsValid() {
+		must.NoError(res.Add("client", op.Conn.Peer.String()))
+	}
+
+	if appName := op.Conn.AppName(); appName != "" {
+		must.NoError(res.Add("appName", appName))
+	}
+
+	if metadata := op.Conn.Metadata(); metadata != nil {
+		must.NoError(res.Add("clientMetadata", metadata))
+	}
+
+	users := wirebson.MakeArray(1)
+
+	if u := op.Conn.Conv().Username(); u != "" {
+		must.NoError(users.Add(wirebson.MustDocument("user", u)))
+	}
+
+	must.NoError(res.Add("effectiveUsers", users))
+	must.NoError(res.Add("secs_running", int64(total/time.Second)))
+	must.NoError(res.Add("microsecs_running", total.Microseconds()))
+	must.NoError(res.Add("handlerMicros", (total - pg).Microseconds()))
+	must.NoError(res.Add("postgresqlMicros", pg.Microseconds()))
+
+	if pid := op.PID(); pid != 0 {
+		must.NoError(res.Add("postgresqlPid", int64(pid)))
+	}
+
+	must.NoError(res.Add("killPending", op.Killed()))
+
+	return res, nil
 }
@@ -64,7 +64,12 @@ func (h *Handler) msgDistinct(connCtx context.Context, req *middleware.Request)
 		)
 	}
 
-	conn, err := h.Pool.Acquire()
+	pool, err := h.readPool(connCtx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := pool.Acquire()
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -0,0 +1,86 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// msgDropSearchIndex implements `dropSearchIndex` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) msgDropSearchIndex(connCtx context.Context, req *middleware.Request) (*middleware.Response, error) {
+	doc, err := req.OpMsg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, doc); err != nil {
+		return nil, err
+	}
+
+	command := doc.Command()
+
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := getRequiredParam[string](doc, command)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := h.findSearchIndex(connCtx, command, dbName, collection, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := h.Pool.Acquire()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+	defer conn.Release()
+
+	if err = h.dropSearchIndex(connCtx, conn, dbName, collection, index.Get("name").(string)); err != nil {
+		return nil, err
+	}
+
+	return middleware.ResponseMsg(wirebson.MustDocument(
+		"ok", float64(1),
+	))
+}
+
+// dropSearchIndex drops the index with the given name using the given connection.
+func (h *Handler) dropSearchIndex(ctx context.Context, conn *documentdb.Conn, dbName, collection, name string) error {
+	dropSpec := must.NotFail(wirebson.MustDocument(
+		"dropIndexes", collection,
+		"index", name,
+	).Encode())
+
+	if _, err := documentdb_api.DropIndexes(ctx, conn.Conn(), h.L, dbName, dropSpec, nil); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
@@ -23,13 +23,17 @@
 	"net"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/FerretDB/wire/wirebson"
+	"github.com/jackc/pgx/v5"
 
 	"github.com/FerretDB/FerretDB/v2/build/version"
 	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
 	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
 	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 )
 
@@ -79,7 +83,8 @@ func (h *Handler) msgExplain(connCtx context.Context, req *middleware.Request) (
 
 	cmd := explainDoc.Command()
 
-	if _, ok = explainDoc.Get(cmd).(string); !ok {
+	collection, ok := explainDoc.Get(cmd).(string)
+	if !ok {
 		return nil, mongoerrors.NewWithArgument(
 			mongoerrors.ErrInvalidNamespace,
 			"Failed to parse namespace element",
@@ -87,14 +92,53 @@ func (h *Handler) msgExplain(connCtx context.Context, req *middleware.Request) (
 		)
 	}
 
-	var f string
+	verbosityV, err := getOptionalParam(doc, "verbosity", string(explainAllPlansExecution))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	verbosity := explainVerbosity(verbosityV)
+
+	switch verbosity {
+	case explainQueryPlanner, explainExecutionStats, explainAllPlansExecution:
+	default:
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrFailedToParse,
+			"verbosity string must be one of {'queryPlanner', 'executionStats', 'allPlansExecution'}",
+			"explain",
+		)
+	}
+
+	// write commands are explained using the plan of the equivalent find query;
+	// when execution statistics are requested, the write itself is executed and rolled back
+	var f, writeF, writeStage string
+	planSpec := explainSpec
+
 	switch cmd {
 	case "aggregate":
 		f = "documentdb_api_catalog.bson_aggregation_pipeline"
 	case "count":
 		f = "documentdb_api_catalog.bson_aggregation_count"
+	case "distinct":
+		f = "documentdb_api_catalog.bson_aggregation_distinct"
 	case "find":
 		f = "documentdb_api_catalog.bson_aggregation_find"
+	case "update", "delete", "findAndModify":
+		f = "documentdb_api_catalog.bson_aggregation_find"
+
+		if planSpec, writeStage, err = explainWriteFindSpec(collection, explainDoc); err != nil {
+			return nil, err
+		}
+
+		switch cmd {
+		case "update":
+			writeF = "documentdb_api.update($1, $2::bytea, NULL)"
+		case "delete":
+			writeF = "documentdb_api.delete($1, $2::bytea, NULL)"
+		default:
+			writeF = "documentdb_api.find_and_modify($1, $2::bytea)"
+		}
+
 	default:
 		return nil, mongoerrors.NewWithArgument(
 			mongoerrors.ErrNotImplemented,
@@ -103,12 +147,10 @@ func (h *Handler) msgExplain(connCtx context.Context, req *middleware.Request) (
 		)
 	}
 
-	q := fmt.Sprintf(`
-		EXPLAIN (FORMAT JSON)
-			SELECT document
-		FROM %s($1, $2::bytea)`,
-		f,
-	)
+	options := "FORMAT JSON"
+	if verbosity.analyze() {
+		options = "ANALYZE, BUFFERS, FORMAT JSON"
+	}
 
 	conn, err := h.Pool.Acquire()
 	if err != nil {
@@ -116,22 +158,98 @@ func (h *Handler) msgExplain(connCtx context.Context, req *middleware.Request) (
 	}
 	defer conn.Release()
 
-	var dest []byte
-	if err = conn.Conn().QueryRow(connCtx, q, dbName, explainSpec).Scan(&dest); err != nil {
-		return nil, lazyerrors.Error(mongoerrors.Make(connCtx, err, "", h.L))
+	// always use a transaction so that nothing executed by EXPLAIN ANALYZE is committed
+	tx, err := conn.Conn().Begin(connCtx)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
 	}
 
-	queryPlan, err := unmarshalExplain(dest)
+	defer func() {
+		// rollback is a no-op if the transaction was already rolled back below
+		_ = tx.Rollback(connCtx)
+	}()
+
+	q := fmt.Sprintf(`EXPLAIN (%s) SELECT document FROM %s($1, $2::bytea)`, options, f)
+
+	plan, err := h.explainQuery(connCtx, tx, q, dbName, planSpec)
 	if err != nil {
+		return nil, err
+	}
+
+	var writePlan map[string]any
+
+	if writeF != "" && verbosity.analyze() {
+		q = fmt.Sprintf(`EXPLAIN (%s) SELECT p_result FROM %s`, options, writeF)
+
+		if writePlan, err = h.explainQuery(connCtx, tx, q, dbName, explainSpec); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Rollback(connCtx); err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
+	root, _ := plan["Plan"].(map[string]any)
+	indexNames := h.explainIndexNames(connCtx, conn.Conn(), pgIndexNames(root))
+
+	winningPlan := (&planTranslator{indexNames: indexNames}).translate(root)
+
+	queryPlanner := must.NotFail(wirebson.NewDocument(
+		"namespace", dbName+"."+collection,
+		"winningPlan", winningPlan,
+		"rejectedPlans", wirebson.MakeArray(0),
+
+		// our extensions
+		"postgresqlPlan", convertJSON(plan),
+	))
+
+	var executionStats *wirebson.Document
+
+	if verbosity.analyze() {
+		t := &planTranslator{indexNames: indexNames, analyze: true}
+		executionStages := t.translate(root)
+
+		nReturned := planRows(root)
+		executionTime := planInt(plan, "Execution Time")
+
+		if writePlan != nil {
+			executionTime = planInt(writePlan, "Execution Time")
+
+			executionStages = must.NotFail(wirebson.NewDocument(
+				"stage", writeStage,
+				"nReturned", int32(0),
+				"executionTimeMillisEstimate", executionTime,
+				"inputStage", executionStages,
+			))
+			nReturned = 0
+		}
+
+		executionStats = must.NotFail(wirebson.NewDocument(
+			"executionSuccess", true,
+			"nReturned", nReturned,
+			"executionTimeMillis", executionTime,
+			"totalKeysExamined", t.stats.keysExamined,
+			"totalDocsExamined", t.stats.docsExamined,
+			"executionStages", executionStages,
+		))
+
+		if verbosity == explainAllPlansExecution {
+			must.NoError(executionStats.Add("allPlansExecution", wirebson.MakeArray(0)))
+		}
+
+		if writePlan != nil {
+			// our extension
+			must.NoError(executionStats.Add("postgresqlPlan", convertJSON(writePlan)))
+		}
+	}
+
 	hostname, err := os.Hostname()
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	_, portV, _ := net.SplitHostPort(h.TCPHost)
+	_, portV, _ := net.SplitHostPort(h.Host)
 
 	var port int
 
@@ -154,22 +272,259 @@ func (h *Handler) msgExplain(connCtx context.Context, req *middleware.Request) (
 		)),
 	))
 
-	res, err := wirebson.NewDocument(
-		"queryPlanner", queryPlan,
-		"explainVersion", "1",
-		"command", must.NotFail(explainDoc.Encode()),
-		"serverInfo", serverInfo,
-		"ok", float64(1),
-	)
+	res := must.NotFail(wirebson.NewDocument("queryPlanner", queryPlanner))
+
+	if executionStats != nil {
+		must.NoError(res.Add("executionStats", executionStats))
+	}
+
+	must.NoError(res.Add("explainVersion", "1"))
+	must.NoError(res.Add("command", must.NotFail(explainDoc.Encode())))
+	must.NoError(res.Add("serverInfo", serverInfo))
+	must.NoError(res.Add("ok", float64(1)))
+
+	return middleware.ResponseMsg(res)
+}
+
+// explainQuery runs the given EXPLAIN query and returns the decoded plan.
+func (h *Handler) explainQuery(ctx context.Context, tx pgx.Tx, q, dbName string, spec wirebson.RawDocument) (map[string]any, error) {
+	var dest []byte
+	if err := tx.QueryRow(ctx, q, dbName, spec).Scan(&dest); err != nil {
+		return nil, lazyerrors.Error(mongoerrors.Make(ctx, err, "", h.L))
+	}
+
+	plan, err := unmarshalExplain(dest)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	return middleware.ResponseMsg(res)
+	return plan, nil
+}
+
+// explainIndexNames returns a mapping of the given PostgreSQL index names to MongoDB index names.
+//
+// Errors are logged and ignored, PostgreSQL names are used in that case.
+func (h *Handler) explainIndexNames(ctx context.Context, conn *pgx.Conn, pgNames []string) map[string]string {
+	ids := make([]int64, 0, len(pgNames))
+
+	for _, pgName := range pgNames {
+		id, err := strconv.ParseInt(strings.TrimPrefix(pgName, pgIndexPrefix), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	q := `SELECT index_id, (index_spec).index_name FROM documentdb_api_catalog.collection_indexes WHERE index_id = ANY($1)`
+
+	rows, err := conn.Query(ctx, q, ids)
+	if err != nil {
+		h.L.DebugContext(ctx, "Failed to query index names", logging.Error(err))
+		return nil
+	}
+
+	res := make(map[string]string, len(ids))
+
+	var id int64
+	var name string
+
+	_, err = pgx.ForEachRow(rows, []any{&id, &name}, func() error {
+		res[pgIndexPrefix+strconv.FormatInt(id, 10)] = name
+		return nil
+	})
+	if err != nil {
+		h.L.DebugContext(ctx, "Failed to query index names", logging.Error(err))
+		return nil
+	}
+
+	return res
+}
+
+// planSummaryTimeout is the timeout for the query used by [Handler.PlanSummary].
+const planSummaryTimeout = 5 * time.Second
+
+// PlanSummary returns MongoDB-style plan summary for the given command,
+// or an empty string if it is not available.
+//
+// The plan is computed again without executing the command,
+// so it could differ from the plan that was actually used.
+// Only find, count, distinct, and aggregate commands without `$out` or `$merge` stages are supported.
+// The given context could be already canceled; errors are logged and ignored.
+func (h *Handler) PlanSummary(ctx context.Context, doc *wirebson.Document) string {
+	var f string
+
+	switch doc.Command() {
+	case "aggregate":
+		if middleware.AggregateWrites(doc) {
+			return ""
+		}
+
+		f = "documentdb_api_catalog.bson_aggregation_pipeline"
+	case "count":
+		f = "documentdb_api_catalog.bson_aggregation_count"
+	case "distinct":
+		f = "documentdb_api_catalog.bson_aggregation_distinct"
+	case "find":
+		f = "documentdb_api_catalog.bson_aggregation_find"
+	default:
+		return ""
+	}
+
+	dbName, _ := doc.Get("$db").(string)
+	if dbName == "" {
+		return ""
+	}
+
+	spec, err := doc.Encode()
+	if err != nil {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), planSummaryTimeout)
+	defer cancel()
+
+	var res string
+
+	err = h.Pool.WithConn(func(conn *pgx.Conn) error {
+		q := fmt.Sprintf(`EXPLAIN (FORMAT JSON) SELECT document FROM %s($1, $2::bytea)`, f)
+
+		var dest []byte
+		if err := conn.QueryRow(ctx, q, dbName, spec).Scan(&dest); err != nil {
+			return err
+		}
+
+		plan, err := unmarshalExplain(dest)
+		if err != nil {
+			return err
+		}
+
+		root, _ := plan["Plan"].(map[string]any)
+		indexNames := h.explainIndexNames(ctx, conn, pgIndexNames(root))
+		res = planSummary((&planTranslator{indexNames: indexNames}).translate(root))
+
+		return nil
+	})
+	if err != nil {
+		h.L.DebugContext(ctx, "Failed to get plan summary", logging.Error(err))
+		return ""
+	}
+
+	return res
+}
+
+// explainWriteFindSpec returns find command specification that selects the same documents
+// as the given update, delete, or findAndModify command, and MongoDB write stage name.
+func explainWriteFindSpec(collection string, explainDoc *wirebson.Document) (wirebson.RawDocument, string, error) {
+	cmd := explainDoc.Command()
+
+	var filter any = wirebson.MustDocument()
+	var sort any
+	var single bool
+	stage := "UPDATE"
+
+	switch cmd {
+	case "update", "delete":
+		field := "updates"
+		if cmd == "delete" {
+			field = "deletes"
+			stage = "DELETE"
+		}
+
+		statements, ok := explainDoc.Get(field).(wirebson.AnyArray)
+		if !ok {
+			return nil, "", mongoerrors.NewWithArgument(
+				mongoerrors.ErrBadValue,
+				fmt.Sprintf("BSON field '%s.%s' is missing but a required field", cmd, field),
+				"explain",
+			)
+		}
+
+		arr, err := statements.Decode()
+		if err != nil {
+			return nil, "", lazyerrors.Error(err)
+		}
+
+		if arr.Len() != 1 {
+			return nil, "", mongoerrors.NewWithArgument(
+				mongoerrors.ErrInvalidLength,
+				"explained write batches must be of size 1",
+				"explain",
+			)
+		}
+
+		statementV, ok := arr.Get(0).(wirebson.AnyDocument)
+		if !ok {
+			return nil, "", mongoerrors.NewWithArgument(
+				mongoerrors.ErrTypeMismatch,
+				fmt.Sprintf("BSON field '%s.%s' is the wrong type, expected type 'object'", cmd, field),
+				"explain",
+			)
+		}
+
+		statement, err := statementV.Decode()
+		if err != nil {
+			return nil, "", lazyerrors.Error(err)
+		}
+
+		if q := statement.Get("q"); q != nil {
+			filter = q
+		}
+
+		if cmd == "update" {
+			single = true
+			if multi, _ := statement.Get("multi").(bool); multi {
+				single = false
+			}
+		} else {
+			limit, err := getOptionalParam(statement, "limit", int32(0))
+			if err != nil {
+				return nil, "", lazyerrors.Error(err)
+			}
+
+			single = limit == 1
+		}
+
+	case "findAndModify":
+		if q := explainDoc.Get("query"); q != nil {
+			filter = q
+		}
+
+		sort = explainDoc.Get("sort")
+		single = true
+
+		if remove, _ := explainDoc.Get("remove").(bool); remove {
+			stage = "DELETE"
+		}
+
+	default:
+		panic(fmt.Sprintf("unexpected command %q", cmd))
+	}
+
+	spec := must.NotFail(wirebson.NewDocument("find", collection, "filter", filter))
+
+	if sort != nil {
+		must.NoError(spec.Add("sort", sort))
+	}
+
+	if single {
+		must.NoError(spec.Add("limit", int64(1)))
+	}
+
+	raw, err := spec.Encode()
+	if err != nil {
+		return nil, "", lazyerrors.Error(err)
+	}
+
+	return raw, stage, nil
 }
 
 // unmarshalExplain unmarshalls the plan from EXPLAIN postgreSQL command.
-func unmarshalExplain(b []byte) (*wirebson.Document, error) {
+func unmarshalExplain(b []byte) (map[string]any, error) {
 	var plans []map[string]any
 
 	if err := json.Unmarshal(b, &plans); err != nil {
@@ -180,7 +535,7 @@ func unmarshalExplain(b []byte) (*wirebson.Document, error) {
 		return nil, lazyerrors.Error(errors.New("no execution plan returned"))
 	}
 
-	return convertJSON(plans[0]).(*wirebson.Document), nil
+	return plans[0], nil
 }
 
 // convertJSON transforms decoded JSON map[string]any value into [*wirebson.Document].
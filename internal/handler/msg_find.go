@@ -17,6 +17,9 @@
 import (
 	"context"
 
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
 	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 )
@@ -45,9 +48,27 @@ func (h *Handler) msgFind(connCtx context.Context, req *middleware.Request) (*mi
 		return nil, lazyerrors.Error(err)
 	}
 
-	page, cursorID, err := h.Pool.Find(connCtx, dbName, spec)
+	rc, err := getReadConcern(doc)
 	if err != nil {
-		return nil, lazyerrors.Error(err)
+		return nil, err
+	}
+
+	var page wirebson.RawDocument
+	var cursorID int64
+
+	if rc.snapshot() {
+		if page, cursorID, err = h.readSnapshot(connCtx, dbName, spec, doc, rc, h.Pool.FindSnapshot); err != nil {
+			return nil, err
+		}
+	} else {
+		var pool *documentdb.Pool
+		if pool, err = h.readPool(connCtx, doc); err != nil {
+			return nil, err
+		}
+
+		if page, cursorID, err = pool.Find(connCtx, dbName, spec); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
 	}
 
 	h.s.AddCursor(connCtx, userID, sessionID, cursorID)
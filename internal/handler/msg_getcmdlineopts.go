@@ -26,6 +26,9 @@
 
 // msgGetCmdLineOpts implements `getCmdLineOpts` command.
 //
+// Startup values of server parameters are returned in the `parsed.setParameter` document,
+// even if they were changed later by the `setParameter` command.
+//
 // The passed context is canceled when the client connection is closed.
 func (h *Handler) msgGetCmdLineOpts(connCtx context.Context, req *middleware.Request) (*middleware.Response, error) {
 	spec, err := req.OpMsg.RawDocument()
@@ -37,9 +40,17 @@ func (h *Handler) msgGetCmdLineOpts(connCtx context.Context, req *middleware.Req
 		return nil, err
 	}
 
+	params := wirebson.MakeDocument(len(h.params.all))
+
+	for _, name := range h.params.names() {
+		if p := h.params.all[name]; p.settableAtStartup() {
+			must.NoError(params.Add(name, p.startupValue()))
+		}
+	}
+
 	return middleware.ResponseMsg(wirebson.MustDocument(
 		"argv", must.NotFail(wirebson.NewArray("ferretdb")),
-		"parsed", must.NotFail(wirebson.NewDocument()),
+		"parsed", wirebson.MustDocument("setParameter", params),
 		"ok", float64(1),
 	))
 }
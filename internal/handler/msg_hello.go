@@ -17,14 +17,13 @@
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/FerretDB/wire"
 	"github.com/FerretDB/wire/wirebson"
 
 	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
-	"github.com/FerretDB/FerretDB/v2/internal/handler/session"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/topology"
 	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
@@ -49,7 +48,7 @@ func (h *Handler) msgHello(connCtx context.Context, req *middleware.Request) (*m
 		return nil, err
 	}
 
-	res, err := h.hello(connCtx, doc, h.TCPHost, h.ReplSetName)
+	res, err := h.hello(connCtx, doc, h.Host, h.ReplSetName)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -59,7 +58,11 @@ func (h *Handler) msgHello(connCtx context.Context, req *middleware.Request) (*m
 
 // hello checks client metadata and returns hello's document fields.
 // It also returns response for deprecated `isMaster` and `ismaster` commands.
-func (h *Handler) hello(ctx context.Context, spec wirebson.AnyDocument, tcpHost, name string) (*wirebson.Document, error) {
+func (h *Handler) hello(ctx context.Context, spec wirebson.AnyDocument, host, name string) (*wirebson.Document, error) {
+	if h.Draining() {
+		return nil, mongoerrors.New(mongoerrors.ErrShutdownInProgress, "The server is in quiesce mode and will shut down")
+	}
+
 	doc, err := spec.Decode()
 	if err != nil {
 		return nil, lazyerrors.Error(err)
@@ -71,38 +74,73 @@ func (h *Handler) hello(ctx context.Context, spec wirebson.AnyDocument, tcpHost,
 
 	res := must.NotFail(wirebson.NewDocument())
 
+	writable := true
+
+	var hosts []string
+	if host != "" {
+		hosts = []string{host}
+	}
+
+	var status topology.Status
+	if name != "" && h.Topology != nil {
+		status = h.Topology.Status()
+		writable = status.Writable
+		hosts = status.Hosts
+	}
+
 	switch doc.Command() {
 	case "hello":
-		must.NoError(res.Add("isWritablePrimary", true))
+		must.NoError(res.Add("isWritablePrimary", writable))
 
 	case "isMaster", "ismaster":
 		if helloOk := doc.Get("helloOk"); helloOk != nil {
 			must.NoError(res.Add("helloOk", true))
 		}
 
-		must.NoError(res.Add("ismaster", true))
+		must.NoError(res.Add("ismaster", writable))
 
 	default:
 		panic(fmt.Sprintf("unexpected command: %q", doc.Command()))
 	}
 
 	if name != "" {
-		// That does not work for TLS-only setups, IPv6 addresses, etc.
 		// The proper solution is to support `replSetInitiate` command.
 		// TODO https://github.com/FerretDB/FerretDB/issues/3936
-		if strings.HasPrefix(tcpHost, ":") {
-			tcpHost = "localhost" + tcpHost
+		hostsArr := wirebson.MakeArray(len(hosts))
+
+		for _, h := range hosts {
+			must.NoError(hostsArr.Add(h))
 		}
 
 		must.NoError(res.Add("setName", name))
-		must.NoError(res.Add("hosts", must.NotFail(wirebson.NewArray(tcpHost))))
+		must.NoError(res.Add("setVersion", topology.SetVersion))
+		must.NoError(res.Add("hosts", hostsArr))
+
+		if h.Topology != nil {
+			must.NoError(res.Add("secondary", status.Secondary))
+
+			if status.Primary != "" {
+				must.NoError(res.Add("primary", status.Primary))
+			}
+
+			must.NoError(res.Add("me", status.Me))
+		}
+
+		if writable {
+			electionID := status.ElectionID
+			if h.Topology == nil {
+				electionID = topology.ElectionID(1)
+			}
+
+			must.NoError(res.Add("electionId", electionID))
+		}
 	}
 
 	must.NoError(res.Add("maxBsonObjectSize", maxBsonObjectSize))
 	must.NoError(res.Add("maxMessageSizeBytes", int32(wire.MaxMsgLen)))
 	must.NoError(res.Add("maxWriteBatchSize", maxWriteBatchSize))
 	must.NoError(res.Add("localTime", time.Now()))
-	must.NoError(res.Add("logicalSessionTimeoutMinutes", session.LogicalSessionTimeoutMinutes))
+	must.NoError(res.Add("logicalSessionTimeoutMinutes", h.params.sessionTimeout.value()))
 	must.NoError(res.Add("connectionId", connectionID))
 	must.NoError(res.Add("minWireVersion", minWireVersion))
 	must.NoError(res.Add("maxWireVersion", maxWireVersion))
@@ -43,7 +43,7 @@ func (h *Handler) msgIsMaster(connCtx context.Context, req *middleware.Request)
 		return nil, err
 	}
 
-	res, err := h.hello(connCtx, doc, h.TCPHost, h.ReplSetName)
+	res, err := h.hello(connCtx, doc, h.Host, h.ReplSetName)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -66,7 +66,12 @@ func checkClientMetadata(ctx context.Context, doc *wirebson.Document) error {
 		)
 	}
 
-	connInfo.SetMetadataRecv()
+	var metadata *wirebson.Document
+	if d, ok := c.(wirebson.AnyDocument); ok {
+		metadata, _ = d.Decode()
+	}
+
+	connInfo.SetMetadataRecv(metadata)
 
 	return nil
 }
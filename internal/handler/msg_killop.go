@@ -0,0 +1,136 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// msgKillOp implements `killOp` command.
+//
+// It cancels the operation's context and the running PostgreSQL query, if any.
+// Only superusers could kill operations of other users.
+// Like MongoDB, it reports the same `info` for unknown operations,
+// including finished ones.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) msgKillOp(connCtx context.Context, req *middleware.Request) (*middleware.Response, error) {
+	doc, err := req.OpMsg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, doc); err != nil {
+		return nil, err
+	}
+
+	command := doc.Command()
+
+	db, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	if db != "admin" {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrUnauthorized,
+			command+" may only be run against the admin database.",
+			command,
+		)
+	}
+
+	v, err := getRequiredParamAny(doc, "op")
+	if err != nil {
+		return nil, err
+	}
+
+	var id int64
+
+	switch v := v.(type) {
+	case int32:
+		id = int64(v)
+	case int64:
+		id = v
+	case float64:
+		if v != math.Trunc(v) {
+			msg := fmt.Sprintf("Expected field \"op\" to have a whole number value, but found %v", v)
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+		}
+
+		id = int64(v)
+	default:
+		msg := fmt.Sprintf("Expected field \"op\" to have numeric type, but found %s", aliasFromType(v))
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, command)
+	}
+
+	if id < math.MinInt32 || id > math.MaxInt32 {
+		msg := fmt.Sprintf("Invalid op ID %d", id)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+	}
+
+	res := must.NotFail(wirebson.NewDocument(
+		"info", "attempting to kill op",
+		"ok", float64(1),
+	))
+
+	op := h.ops.Get(int32(id))
+	if op == nil {
+		h.L.DebugContext(connCtx, "Operation to kill not found", slog.Int64("opid", id))
+		return middleware.ResponseMsg(res)
+	}
+
+	if op.Conn.Conv().Username() != conninfo.Get(connCtx).Conv().Username() {
+		var p *documentdb.RolePrivileges
+		if p, err = h.userPrivileges(connCtx); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if !p.Superuser {
+			return nil, mongoerrors.NewWithArgument(
+				mongoerrors.ErrUnauthorized,
+				fmt.Sprintf("not authorized to kill operation %d of another user", id),
+				command,
+			)
+		}
+	}
+
+	h.L.InfoContext(connCtx, "Killing operation", slog.Int64("opid", id))
+
+	op.Kill(func(pid uint32) {
+		err := h.Pool.WithConn(func(conn *pgx.Conn) error {
+			_, err := conn.Exec(connCtx, "SELECT pg_cancel_backend($1)", int32(pid))
+			return err
+		})
+		if err != nil {
+			h.L.WarnContext(connCtx, "Failed to cancel PostgreSQL query", slog.Int64("opid", id), logging.Error(err))
+		}
+	})
+
+	return middleware.ResponseMsg(res)
+}
@@ -0,0 +1,131 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/profiler"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// msgProfile implements `profile` command.
+//
+// Unlike MongoDB, `slowms` and `sampleRate` are set per database.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) msgProfile(connCtx context.Context, req *middleware.Request) (*middleware.Response, error) {
+	doc, err := req.OpMsg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, doc); err != nil {
+		return nil, err
+	}
+
+	command := doc.Command()
+
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	level, err := getProfileNumberParam(command, doc.Get(command))
+	if err != nil {
+		return nil, err
+	}
+
+	if level != math.Trunc(level) || level < -1 || level > float64(profiler.LevelAll) {
+		msg := fmt.Sprintf("Invalid profiling level: %v", level)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+	}
+
+	prev := h.profiler.Settings(dbName)
+
+	if level != -1 {
+		s := prev
+		s.Level = profiler.Level(level)
+
+		var v any
+		if v, err = getOptionalParamAny(doc, "slowms", prev.SlowMS); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		var slowMS float64
+		if slowMS, err = getProfileNumberParam("slowms", v); err != nil {
+			return nil, err
+		}
+
+		if slowMS != math.Trunc(slowMS) || slowMS < math.MinInt32 || slowMS > math.MaxInt32 {
+			msg := fmt.Sprintf("Invalid value for 'slowms': %v", slowMS)
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+		}
+
+		s.SlowMS = int32(slowMS)
+
+		if v, err = getOptionalParamAny(doc, "sampleRate", prev.SampleRate); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if s.SampleRate, err = getProfileNumberParam("sampleRate", v); err != nil {
+			return nil, err
+		}
+
+		if s.SampleRate < 0 || s.SampleRate > 1 {
+			msg := "'sampleRate' must be between 0.0 and 1.0 inclusive"
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+		}
+
+		if doc.Get("filter") != nil {
+			msg := "profile command does not support 'filter' yet"
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrNotImplemented, msg, command)
+		}
+
+		prev = h.profiler.SetSettings(dbName, s)
+	}
+
+	return middleware.ResponseMsg(wirebson.MustDocument(
+		"was", int32(prev.Level),
+		"slowms", prev.SlowMS,
+		"sampleRate", prev.SampleRate,
+		"ok", float64(1),
+	))
+}
+
+// getProfileNumberParam returns the numeric value v of the `profile` command's parameter as float64.
+func getProfileNumberParam(key string, v any) (float64, error) {
+	switch v := v.(type) {
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	default:
+		msg := fmt.Sprintf(
+			"BSON field 'profile.%s' is the wrong type '%s', expected types '[long, int, decimal, double]'",
+			key, aliasFromType(v),
+		)
+
+		return 0, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, key)
+	}
+}
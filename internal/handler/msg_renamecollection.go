@@ -114,14 +114,14 @@ func (h *Handler) msgRenameCollection(connCtx context.Context, req *middleware.R
 		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidNamespace, msg, "renameCollection")
 	}
 
-	// support cross-database rename
-	// TODO https://github.com/FerretDB/FerretDB/issues/2563
 	if oldDBName != newDBName {
-		return nil, mongoerrors.NewWithArgument(
-			mongoerrors.ErrNotImplemented,
-			"Command renameCollection does not support cross-database rename",
-			command,
-		)
+		if err = h.Pool.MoveCollection(connCtx, oldDBName, oldCName, newDBName, newCName, dropTarget); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return middleware.ResponseMsg(wirebson.MustDocument(
+			"ok", float64(1),
+		))
 	}
 
 	if oldCName == newCName {
@@ -25,6 +25,7 @@
 	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api_internal"
 	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
 	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/audit"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
 )
@@ -57,7 +58,9 @@ func (h *Handler) msgSASLContinue(connCtx context.Context, req *middleware.Reque
 
 // saslContinue continues and finishes SCRAM conversation.
 // It returns the document containing authentication payload used for the response.
-func (h *Handler) saslContinue(ctx context.Context, doc *wirebson.Document) (*wirebson.Document, error) {
+//
+// Authentication success or failure is recorded by the auditor, if any.
+func (h *Handler) saslContinue(ctx context.Context, doc *wirebson.Document) (_ *wirebson.Document, err error) {
 	if !h.Auth {
 		h.L.WarnContext(ctx, "saslContinue is called when authentication is disabled")
 	}
@@ -70,6 +73,17 @@ func (h *Handler) saslContinue(ctx context.Context, doc *wirebson.Document) (*wi
 	conv := conninfo.Get(ctx).Conv()
 	steps := conninfo.Get(ctx).DecrementSteps()
 
+	// the final empty round trip after successful authentication is not recorded
+	if h.Audit != nil && !(conv.Succeed() && steps == 0) {
+		defer func() {
+			r := newAuditRecord(ctx, audit.Authenticate, "saslContinue")
+			r.DB, _ = doc.Get("$db").(string)
+			r.User = conv.Username()
+			setAuditResult(ctx, r, err, h.L)
+			h.Audit.Record(ctx, r)
+		}()
+	}
+
 	if conv == nil || steps < 0 {
 		h.L.WarnContext(ctx, "saslContinue: no conversation to continue")
 
@@ -0,0 +1,120 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// msgSetParameter implements `setParameter` command.
+//
+// Only PostgreSQL superusers and members of `pg_signal_backend` role could change parameters.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) msgSetParameter(connCtx context.Context, req *middleware.Request) (*middleware.Response, error) {
+	doc, err := req.OpMsg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, doc); err != nil {
+		return nil, err
+	}
+
+	command := doc.Command()
+
+	db, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	if db != "admin" {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrUnauthorized,
+			command+" may only be run against the admin database.",
+			command,
+		)
+	}
+
+	priv, err := h.userPrivileges(connCtx)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if !priv.SignalBackend {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrUnauthorized,
+			"not authorized on admin to execute command "+command,
+			command,
+		)
+	}
+
+	res := wirebson.MakeDocument(2)
+
+	for name, v := range doc.All() {
+		switch name {
+		case command, "$db", "lsid", "comment", "$clusterTime", "$readPreference", "apiVersion", "apiStrict", "apiDeprecationErrors":
+			continue
+		}
+
+		p := h.params.all[name]
+		if p == nil {
+			msg := fmt.Sprintf("attempted to set unrecognized parameter [%s], use help:true to see options ", name)
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, command)
+		}
+
+		if !p.settableAtRuntime() {
+			msg := fmt.Sprintf("not allowed to change [%s] at runtime", name)
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrIllegalOperation, msg, command)
+		}
+
+		was := p.value()
+
+		if err = p.set(name, v); err != nil {
+			return nil, err
+		}
+
+		h.L.InfoContext(
+			connCtx, "Server parameter changed",
+			slog.String("parameter", name), slog.Any("was", was), slog.Any("value", p.value()),
+		)
+
+		// only the first previous value is returned, like in MongoDB
+		if res.Len() == 0 {
+			must.NoError(res.Add("was", was))
+		}
+	}
+
+	if res.Len() == 0 {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrInvalidOptions,
+			"no option found to set, use help:true to see options ",
+			command,
+		)
+	}
+
+	must.NoError(res.Add("ok", float64(1)))
+
+	return middleware.ResponseMsg(res)
+}
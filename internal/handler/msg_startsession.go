@@ -20,7 +20,6 @@
 	"github.com/FerretDB/wire/wirebson"
 
 	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
-	"github.com/FerretDB/FerretDB/v2/internal/handler/session"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
 )
 
@@ -43,7 +42,7 @@ func (h *Handler) msgStartSession(connCtx context.Context, req *middleware.Reque
 		"id", wirebson.MustDocument(
 			"id", wirebson.Binary{Subtype: wirebson.BinaryUUID, B: sessionID[:]},
 		),
-		"timeoutMinutes", session.LogicalSessionTimeoutMinutes,
+		"timeoutMinutes", h.params.sessionTimeout.value(),
 		"ok", float64(1),
 	))
 }
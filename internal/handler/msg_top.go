@@ -0,0 +1,155 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// topTypes contains operation types reported by `top` for each namespace, in order.
+var topTypes = []string{"queries", "getmore", "insert", "update", "remove", "commands"}
+
+// topStat represents cumulative latency and count of operations.
+type topStat struct {
+	time  time.Duration
+	count int64
+}
+
+// add adds the given latency and count.
+func (s *topStat) add(t time.Duration, count int64) {
+	s.time += t
+	s.count += count
+}
+
+// document returns `top` representation of stat.
+func (s *topStat) document() *wirebson.Document {
+	return wirebson.MustDocument(
+		"time", s.time.Microseconds(),
+		"count", s.count,
+	)
+}
+
+// msgTop implements `top` command.
+//
+// Only collection-level commands handled since the start are accounted.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) msgTop(connCtx context.Context, req *middleware.Request) (*middleware.Response, error) {
+	doc, err := req.OpMsg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, doc); err != nil {
+		return nil, err
+	}
+
+	command := doc.Command()
+
+	db, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	if db != "admin" {
+		return nil, mongoerrors.NewWithArgument(
+			mongoerrors.ErrUnauthorized,
+			command+" may only be run against the admin database.",
+			command,
+		)
+	}
+
+	type nsStats struct {
+		total, read, write topStat
+		types              map[string]*topStat
+	}
+
+	stats := map[string]*nsStats{}
+
+	for _, l := range h.ConnMetrics.GetLatencies() {
+		if l.Count == 0 {
+			continue
+		}
+
+		ns := l.DB + "." + l.Collection
+
+		s := stats[ns]
+		if s == nil {
+			s = &nsStats{types: map[string]*topStat{}}
+			stats[ns] = s
+		}
+
+		s.total.add(l.Time, l.Count)
+
+		if l.Lock == "write" {
+			s.write.add(l.Time, l.Count)
+		} else {
+			s.read.add(l.Time, l.Count)
+		}
+
+		t := s.types[l.Type]
+		if t == nil {
+			t = new(topStat)
+			s.types[l.Type] = t
+		}
+
+		t.add(l.Time, l.Count)
+	}
+
+	namespaces := make([]string, 0, len(stats))
+	for ns := range stats {
+		namespaces = append(namespaces, ns)
+	}
+
+	slices.Sort(namespaces)
+
+	totals := wirebson.MakeDocument(len(namespaces) + 1)
+	must.NoError(totals.Add("note", "all times in microseconds"))
+
+	for _, ns := range namespaces {
+		s := stats[ns]
+
+		nsDoc := wirebson.MustDocument(
+			"total", s.total.document(),
+			"readLock", s.read.document(),
+			"writeLock", s.write.document(),
+		)
+
+		for _, typ := range topTypes {
+			t := s.types[typ]
+			if t == nil {
+				t = new(topStat)
+			}
+
+			must.NoError(nsDoc.Add(typ, t.document()))
+		}
+
+		must.NoError(totals.Add(ns, nsDoc))
+	}
+
+	return middleware.ResponseMsg(wirebson.MustDocument(
+		"totals", totals,
+		"ok", float64(1),
+	))
+}
@@ -0,0 +1,108 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// msgUpdateSearchIndex implements `updateSearchIndex` command.
+//
+// DocumentDB indexes can't be changed, so the index is dropped and created again with the new definition
+// in a single transaction; if the new index can't be created, the old one is kept.
+// The index type and name are preserved.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) msgUpdateSearchIndex(connCtx context.Context, req *middleware.Request) (*middleware.Response, error) {
+	doc, err := req.OpMsg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, _, err = h.s.CreateOrUpdateByLSID(connCtx, doc); err != nil {
+		return nil, err
+	}
+
+	command := doc.Command()
+
+	dbName, err := getRequiredParam[string](doc, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := getRequiredParam[string](doc, command)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := h.findSearchIndex(connCtx, command, dbName, collection, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	name := index.Get("name").(string)
+
+	newIndex := must.NotFail(wirebson.NewDocument(
+		"name", name,
+		"type", index.Get("type"),
+	))
+
+	if def := doc.Get("definition"); def != nil {
+		must.NoError(newIndex.Add("definition", def))
+	}
+
+	// validate the new definition before dropping the index
+	spec, err := searchIndexSpecFromCommand(command, newIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	createSpec := must.NotFail(must.NotFail(wirebson.NewDocument(
+		"createIndexes", collection,
+		"indexes", wirebson.MustArray(spec),
+		"$db", dbName,
+	)).Encode())
+
+	conn, err := h.Pool.Acquire()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+	defer conn.Release()
+
+	// drop and create in one transaction, so the old index is kept if the new one can't be created
+	err = pgx.BeginFunc(connCtx, conn.Conn(), func(pgx.Tx) error {
+		if dropErr := h.dropSearchIndex(connCtx, conn, dbName, collection, name); dropErr != nil {
+			return dropErr
+		}
+
+		_, createErr := h.createIndexes(connCtx, conn, command, dbName, createSpec)
+
+		return createErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return middleware.ResponseMsg(wirebson.MustDocument(
+		"ok", float64(1),
+	))
+}
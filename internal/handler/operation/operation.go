@@ -0,0 +1,212 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package operation tracks in-progress operations for `currentOp` and `killOp` commands.
+package operation
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// ErrKilled is the cause of the context cancellation for killed operations.
+var ErrKilled = errors.New("operation was interrupted")
+
+// Registry stores in-progress operations.
+type Registry struct {
+	rw  sync.RWMutex
+	ops map[int32]*Operation
+
+	lastID atomic.Int32
+}
+
+// NewRegistry returns a new registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		ops: map[int32]*Operation{},
+	}
+}
+
+// Operation represents a single in-progress operation.
+//
+// It implements [documentdb.QueryObserver] to track time spent in PostgreSQL
+// and the backend PID of the running query.
+//
+//nolint:vet // for readability
+type Operation struct {
+	ID      int32
+	DB      string
+	Command wirebson.RawDocument // encoded copy; the original document may be modified by the handler
+	Conn    *conninfo.ConnInfo
+	Start   time.Time
+
+	r      *Registry
+	cancel context.CancelCauseFunc
+
+	// held by Kill while the running query is canceled,
+	// so QueryFinished waits for it without blocking other methods
+	killM sync.Mutex
+
+	m          sync.Mutex
+	queryStart time.Time     // start of the running query; zero if there is no running query
+	pgTime     time.Duration // time spent in completed queries
+	pid        uint32        // backend PID of the running query; 0 if unknown
+	killed     bool
+}
+
+// Start registers a new operation.
+// The context must contain [conninfo.ConnInfo].
+// The command is encoded, so it must be valid.
+//
+// The returned context is canceled when the operation is killed or finished;
+// it should be used for the operation execution.
+// [Operation.Finish] must be called when the operation is done.
+func (r *Registry) Start(ctx context.Context, db string, command wirebson.AnyDocument) (context.Context, *Operation) {
+	op := &Operation{
+		ID:      r.lastID.Add(1),
+		DB:      db,
+		Command: must.NotFail(command.Encode()),
+		Conn:    conninfo.Get(ctx),
+		Start:   time.Now(),
+		r:       r,
+	}
+
+	ctx, op.cancel = context.WithCancelCause(ctx)
+	ctx = documentdb.WithQueryObserver(ctx, op)
+
+	r.rw.Lock()
+	r.ops[op.ID] = op
+	r.rw.Unlock()
+
+	return ctx, op
+}
+
+// Get returns the operation with the given ID, or nil if there is no such operation.
+func (r *Registry) Get(id int32) *Operation {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	return r.ops[id]
+}
+
+// All returns all in-progress operations sorted by ID.
+func (r *Registry) All() []*Operation {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	return slices.SortedFunc(maps.Values(r.ops), func(a, b *Operation) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
+// Finish unregisters the operation and cancels its context.
+func (op *Operation) Finish() {
+	op.r.rw.Lock()
+	delete(op.r.ops, op.ID)
+	op.r.rw.Unlock()
+
+	op.cancel(context.Canceled)
+}
+
+// Kill marks the operation as killed and cancels its context with [ErrKilled].
+//
+// If there is a running PostgreSQL query on the primary, cancelQuery is called with its backend PID.
+// It is called before that query is finished,
+// so the backend connection is not returned to the pool and reused by another operation.
+func (op *Operation) Kill(cancelQuery func(pid uint32)) {
+	op.killM.Lock()
+	defer op.killM.Unlock()
+
+	op.m.Lock()
+	op.killed = true
+	pid := op.pid
+	op.m.Unlock()
+
+	op.cancel(ErrKilled)
+
+	if pid != 0 {
+		cancelQuery(pid)
+	}
+}
+
+// Killed returns true if [Operation.Kill] was called.
+func (op *Operation) Killed() bool {
+	op.m.Lock()
+	defer op.m.Unlock()
+
+	return op.killed
+}
+
+// Timings returns the total time the operation is running, and the time spent in PostgreSQL queries.
+func (op *Operation) Timings() (total, postgreSQL time.Duration) {
+	op.m.Lock()
+	defer op.m.Unlock()
+
+	postgreSQL = op.pgTime
+	if !op.queryStart.IsZero() {
+		postgreSQL += time.Since(op.queryStart)
+	}
+
+	return time.Since(op.Start), postgreSQL
+}
+
+// PID returns the backend PID of the running PostgreSQL query on the primary, or 0 if there is no such query.
+func (op *Operation) PID() uint32 {
+	op.m.Lock()
+	defer op.m.Unlock()
+
+	return op.pid
+}
+
+// QueryStarted implements [documentdb.QueryObserver].
+func (op *Operation) QueryStarted(pid uint32) {
+	op.m.Lock()
+	defer op.m.Unlock()
+
+	op.pid = pid
+	op.queryStart = time.Now()
+}
+
+// QueryFinished implements [documentdb.QueryObserver].
+func (op *Operation) QueryFinished() {
+	op.killM.Lock()
+	defer op.killM.Unlock()
+
+	op.m.Lock()
+	defer op.m.Unlock()
+
+	if !op.queryStart.IsZero() {
+		op.pgTime += time.Since(op.queryStart)
+	}
+
+	op.queryStart = time.Time{}
+	op.pid = 0
+}
+
+// check interfaces
+var (
+	_ documentdb.QueryObserver = (*Operation)(nil)
+)
@@ -0,0 +1,88 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	connInfo := conninfo.New()
+	t.Cleanup(connInfo.Close)
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), connInfo)
+	r := NewRegistry()
+
+	find := wirebson.MustDocument("find", "coll")
+	ctx1, op1 := r.Start(ctx, "test", find)
+	require.NoError(t, find.Replace("find", "other"))
+	_, op2 := r.Start(ctx, "admin", wirebson.MustDocument("ping", int32(1)))
+
+	assert.Equal(t, []*Operation{op1, op2}, r.All())
+	assert.Same(t, op2, r.Get(op2.ID))
+	assert.Same(t, connInfo, op1.Conn)
+
+	command, err := op1.Command.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, "coll", command.Get("find"))
+
+	op1.QueryStarted(42)
+	assert.Equal(t, uint32(42), op1.PID())
+
+	time.Sleep(time.Millisecond)
+
+	op1.QueryFinished()
+	assert.Zero(t, op1.PID())
+
+	total, pg := op1.Timings()
+	assert.GreaterOrEqual(t, pg, time.Millisecond)
+	assert.GreaterOrEqual(t, total, pg)
+
+	var canceled uint32
+
+	op1.QueryStarted(43)
+	assert.False(t, op1.Killed())
+	op1.Kill(func(pid uint32) {
+		// the operation is not locked while the query is canceled
+		assert.True(t, op1.Killed())
+		assert.Equal(t, pid, op1.PID())
+
+		canceled = pid
+	})
+	assert.Equal(t, uint32(43), canceled)
+	assert.True(t, op1.Killed())
+
+	op2.Kill(func(uint32) { t.Error("unexpected query cancellation") })
+
+	require.Error(t, ctx1.Err())
+	assert.ErrorIs(t, context.Cause(ctx1), ErrKilled)
+
+	op1.Finish()
+	op2.Finish()
+
+	assert.Nil(t, r.Get(op1.ID))
+	assert.Empty(t, r.All())
+}
@@ -0,0 +1,340 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/session"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// Default values of server parameters.
+const (
+	defaultCursorTimeout  = 10 * time.Minute
+	defaultSessionTimeout = time.Duration(session.LogicalSessionTimeoutMinutes) * time.Minute
+)
+
+// parameter represents a server parameter
+// available via `getParameter`, `setParameter`, and `getCmdLineOpts` commands.
+type parameter interface {
+	// value returns the current BSON value.
+	value() any
+
+	// startupValue returns the BSON value at startup.
+	startupValue() any
+
+	// settableAtRuntime returns true if the value can be changed by `setParameter` command.
+	settableAtRuntime() bool
+
+	// settableAtStartup returns true if the value can be set by a flag.
+	settableAtStartup() bool
+
+	// set validates and sets a new BSON value.
+	set(name string, v any) error
+}
+
+// typedParameter is a [parameter] with a value of Go type T.
+//
+//nolint:vet // for readability
+type typedParameter[T any] struct {
+	rw      sync.RWMutex
+	current T
+	startup T
+
+	atStartup bool
+
+	toBSON   func(T) any                         // converts value to BSON
+	fromBSON func(name string, v any) (T, error) // validates BSON value; nil if not settable at runtime
+	apply    func(T)                             // called with a new value; may be nil
+}
+
+// newParameter returns a new parameter with the given startup value.
+func newParameter[T any](startup T, atStartup bool, toBSON func(T) any, fromBSON func(string, any) (T, error)) *typedParameter[T] {
+	return &typedParameter[T]{
+		current:   startup,
+		startup:   startup,
+		atStartup: atStartup,
+		toBSON:    toBSON,
+		fromBSON:  fromBSON,
+	}
+}
+
+// get returns the current value.
+func (p *typedParameter[T]) get() T {
+	p.rw.RLock()
+	defer p.rw.RUnlock()
+
+	return p.current
+}
+
+// value implements [parameter].
+func (p *typedParameter[T]) value() any {
+	return p.toBSON(p.get())
+}
+
+// startupValue implements [parameter].
+func (p *typedParameter[T]) startupValue() any {
+	return p.toBSON(p.startup)
+}
+
+// settableAtRuntime implements [parameter].
+func (p *typedParameter[T]) settableAtRuntime() bool {
+	return p.fromBSON != nil
+}
+
+// settableAtStartup implements [parameter].
+func (p *typedParameter[T]) settableAtStartup() bool {
+	return p.atStartup
+}
+
+// set implements [parameter].
+func (p *typedParameter[T]) set(name string, v any) error {
+	must.BeTrue(p.fromBSON != nil)
+
+	nv, err := p.fromBSON(name, v)
+	if err != nil {
+		return err
+	}
+
+	p.rw.Lock()
+	defer p.rw.Unlock()
+
+	p.current = nv
+
+	if p.apply != nil {
+		p.apply(nv)
+	}
+
+	return nil
+}
+
+// parameters represents a registry of server parameters.
+//
+//nolint:vet // for readability
+type parameters struct {
+	logLevel        *typedParameter[slog.Level] // nil if log level can't be changed
+	slowOpThreshold *typedParameter[time.Duration]
+	cursorTimeout   *typedParameter[time.Duration]
+	sessionTimeout  *typedParameter[time.Duration]
+	maxConnections  *typedParameter[int32]
+
+	all map[string]parameter
+}
+
+// newParameters creates a registry of server parameters with startup values from the handler options.
+func newParameters(opts *NewOpts, s *session.Registry) *parameters {
+	p := &parameters{
+		slowOpThreshold: newParameter(opts.SlowOpThreshold, true, millisToBSON, millisFromBSON),
+		cursorTimeout:   newParameter(cmp.Or(opts.CursorTimeout, defaultCursorTimeout), true, millisToBSON, millisFromBSON),
+		sessionTimeout:  newParameter(cmp.Or(opts.SessionTimeout, defaultSessionTimeout), true, minutesToBSON, minutesFromBSON),
+		maxConnections:  newParameter(opts.MaxConnections, true, identityToBSON[int32], int32FromBSON),
+	}
+
+	p.sessionTimeout.apply = s.SetTimeout
+
+	p.all = map[string]parameter{
+		"authenticationMechanisms": newParameter[any](
+			wirebson.MustArray("SCRAM-SHA-1", "SCRAM-SHA-256"), true, copyToBSON, nil,
+		),
+
+		// accepted for compatibility, only the current value can be set
+		"authSchemaVersion": newParameter(int32(5), true, identityToBSON[int32], func(name string, v any) (int32, error) {
+			if n, err := int32FromBSON(name, v); err != nil || n != 5 {
+				msg := fmt.Sprintf("Invalid value for parameter %s: %v", name, v)
+				return 0, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, name)
+			}
+
+			return 5, nil
+		}),
+
+		"cursorTimeoutMillis": p.cursorTimeout,
+
+		// TODO https://github.com/FerretDB/FerretDB/issues/5073
+		"featureCompatibilityVersion": newParameter[any](
+			wirebson.MustDocument("version", "7.0"), false, copyToBSON, nil,
+		),
+
+		"ferretdbMaxConnections":        p.maxConnections,
+		"ferretdbSlowOpThresholdMillis": p.slowOpThreshold,
+
+		"localLogicalSessionTimeoutMinutes": p.sessionTimeout,
+
+		// accepted for compatibility, has no effect
+		"quiet": newParameter(false, true, identityToBSON[bool], boolFromBSON),
+	}
+
+	if opts.LogLevel != nil {
+		p.logLevel = newParameter(opts.LogLevel.Level(), true, logLevelToBSON, logLevelFromBSON)
+		p.logLevel.apply = opts.LogLevel.Set
+		p.all["ferretdbLogLevel"] = p.logLevel
+	}
+
+	return p
+}
+
+// names returns sorted names of all parameters.
+func (p *parameters) names() []string {
+	res := make([]string, 0, len(p.all))
+	for name := range p.all {
+		res = append(res, name)
+	}
+
+	slices.SortFunc(res, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+
+	return res
+}
+
+// identityToBSON returns the value as is.
+func identityToBSON[T any](v T) any {
+	return v
+}
+
+// copyToBSON returns a copy of the document or array value.
+func copyToBSON(v any) any {
+	switch v := v.(type) {
+	case *wirebson.Document:
+		return v.Copy()
+	case *wirebson.Array:
+		return v.Copy()
+	default:
+		panic(fmt.Sprintf("unexpected type %T", v))
+	}
+}
+
+// millisToBSON returns duration in milliseconds.
+func millisToBSON(d time.Duration) any {
+	return d.Milliseconds()
+}
+
+// minutesToBSON returns duration in minutes.
+func minutesToBSON(d time.Duration) any {
+	return int32(d / time.Minute)
+}
+
+// logLevelToBSON returns log level name.
+func logLevelToBSON(l slog.Level) any {
+	return strings.ToLower(l.String())
+}
+
+// int64FromBSON returns a non-negative whole number parameter value.
+func int64FromBSON(name string, v any) (int64, error) {
+	var f float64
+
+	switch v := v.(type) {
+	case int32:
+		f = float64(v)
+	case int64:
+		f = float64(v)
+	case float64:
+		f = v
+	default:
+		msg := fmt.Sprintf("Invalid value for parameter %s: wrong type '%s'", name, aliasFromType(v))
+		return 0, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, name)
+	}
+
+	if f != math.Trunc(f) || f < 0 || f > math.MaxInt64 {
+		msg := fmt.Sprintf("Invalid value for parameter %s: %v is not a non-negative whole number", name, v)
+		return 0, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, name)
+	}
+
+	return int64(f), nil
+}
+
+// int32FromBSON returns a non-negative whole number parameter value that fits into int32.
+func int32FromBSON(name string, v any) (int32, error) {
+	n, err := int64FromBSON(name, v)
+	if err != nil {
+		return 0, err
+	}
+
+	if n > math.MaxInt32 {
+		msg := fmt.Sprintf("Invalid value for parameter %s: %v is out of range", name, v)
+		return 0, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, name)
+	}
+
+	return int32(n), nil
+}
+
+// millisFromBSON returns duration parameter value set in milliseconds.
+func millisFromBSON(name string, v any) (time.Duration, error) {
+	n, err := int64FromBSON(name, v)
+	if err != nil {
+		return 0, err
+	}
+
+	if n > math.MaxInt64/int64(time.Millisecond) {
+		msg := fmt.Sprintf("Invalid value for parameter %s: %v is out of range", name, v)
+		return 0, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, name)
+	}
+
+	return time.Duration(n) * time.Millisecond, nil
+}
+
+// minutesFromBSON returns duration parameter value set in minutes.
+func minutesFromBSON(name string, v any) (time.Duration, error) {
+	n, err := int32FromBSON(name, v)
+	if err != nil {
+		return 0, err
+	}
+
+	if n == 0 {
+		msg := fmt.Sprintf("Invalid value for parameter %s: must be greater than 0", name)
+		return 0, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, name)
+	}
+
+	return time.Duration(n) * time.Minute, nil
+}
+
+// boolFromBSON returns boolean parameter value.
+func boolFromBSON(name string, v any) (bool, error) {
+	b, ok := v.(bool)
+	if !ok {
+		msg := fmt.Sprintf("Invalid value for parameter %s: wrong type '%s'", name, aliasFromType(v))
+		return false, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, name)
+	}
+
+	return b, nil
+}
+
+// logLevelFromBSON returns log level parameter value.
+func logLevelFromBSON(name string, v any) (slog.Level, error) {
+	var l slog.Level
+
+	s, ok := v.(string)
+	if ok {
+		switch strings.ToLower(s) {
+		case "debug", "info", "warn", "error":
+			must.NoError(l.UnmarshalText([]byte(s)))
+			return l, nil
+		}
+	}
+
+	msg := fmt.Sprintf("Invalid value for parameter %s: %v, expected 'debug', 'info', 'warn', or 'error'", name, v)
+
+	return l, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, name)
+}
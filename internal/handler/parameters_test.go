@@ -0,0 +1,99 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/session"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestParameters(t *testing.T) {
+	t.Parallel()
+
+	level := new(slog.LevelVar)
+
+	opts := &NewOpts{
+		LogLevel:        level,
+		SlowOpThreshold: 100 * time.Millisecond,
+		MaxConnections:  10,
+	}
+
+	s := session.NewRegistry(defaultSessionTimeout, testutil.Logger(t))
+	t.Cleanup(s.Stop)
+
+	p := newParameters(opts, s)
+
+	assert.Equal(t, int64(600000), p.all["cursorTimeoutMillis"].value())
+	assert.Equal(t, int32(30), p.all["localLogicalSessionTimeoutMinutes"].value())
+	assert.Equal(t, int64(100), p.all["ferretdbSlowOpThresholdMillis"].value())
+	assert.Equal(t, int32(10), p.all["ferretdbMaxConnections"].value())
+	assert.Equal(t, "info", p.all["ferretdbLogLevel"].value())
+
+	assert.Equal(t, []string{
+		"authenticationMechanisms",
+		"authSchemaVersion",
+		"cursorTimeoutMillis",
+		"featureCompatibilityVersion",
+		"ferretdbLogLevel",
+		"ferretdbMaxConnections",
+		"ferretdbSlowOpThresholdMillis",
+		"localLogicalSessionTimeoutMinutes",
+		"quiet",
+	}, p.names())
+
+	t.Run("Set", func(t *testing.T) {
+		require.NoError(t, p.all["ferretdbSlowOpThresholdMillis"].set("ferretdbSlowOpThresholdMillis", int32(5)))
+		assert.Equal(t, 5*time.Millisecond, p.slowOpThreshold.get())
+		assert.Equal(t, int64(100), p.all["ferretdbSlowOpThresholdMillis"].startupValue())
+
+		require.NoError(t, p.all["cursorTimeoutMillis"].set("cursorTimeoutMillis", float64(1000)))
+		assert.Equal(t, time.Second, p.cursorTimeout.get())
+
+		require.NoError(t, p.all["ferretdbLogLevel"].set("ferretdbLogLevel", "debug"))
+		assert.Equal(t, slog.LevelDebug, level.Level())
+
+		require.NoError(t, p.all["localLogicalSessionTimeoutMinutes"].set("localLogicalSessionTimeoutMinutes", int64(5)))
+		assert.Equal(t, 5*time.Minute, p.sessionTimeout.get())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for name, v := range map[string]any{
+			"cursorTimeoutMillis":               int32(-1),
+			"ferretdbSlowOpThresholdMillis":     1.5,
+			"ferretdbMaxConnections":            "10",
+			"ferretdbLogLevel":                  "trace",
+			"localLogicalSessionTimeoutMinutes": int32(0),
+			"authSchemaVersion":                 int32(3),
+			"quiet":                             int32(1),
+		} {
+			err := p.all[name].set(name, v)
+
+			var e *mongoerrors.Error
+			require.ErrorAs(t, err, &e, name)
+			assert.Equal(t, int32(mongoerrors.ErrBadValue), e.Code, name)
+		}
+	})
+
+	assert.False(t, p.all["authenticationMechanisms"].settableAtRuntime())
+	assert.False(t, p.all["featureCompatibilityVersion"].settableAtStartup())
+}
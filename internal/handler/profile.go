@@ -0,0 +1,158 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/profiler"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// profile records the command with the profiler if that is enabled for the command's database.
+//
+// Documents and keys examined are not recorded,
+// as DocumentDB does not report them without executing the command with `explain`.
+// Plan summary is computed by [Handler.PlanSummary] without executing the command
+// in the profiler's writer, so the response is not delayed.
+func (h *Handler) profile(ctx context.Context, doc *wirebson.Document, res *middleware.Response, resErr error, d time.Duration) {
+	db, _ := doc.Get("$db").(string)
+	if db == "" {
+		return
+	}
+
+	command := doc.Command()
+
+	ns := db + ".$cmd"
+	if collection, ok := doc.Get(command).(string); ok && collection != "" {
+		if collection == profiler.Collection {
+			return
+		}
+
+		ns = db + "." + collection
+	}
+
+	if !h.profiler.ShouldRecord(db, d) {
+		return
+	}
+
+	cmd := doc
+	if slices.Contains(redactedCommands, command) {
+		cmd = wirebson.MustDocument(command, "###")
+	}
+
+	entry := wirebson.MustDocument(
+		"op", opType(command),
+		"ns", ns,
+		"command", cmd,
+	)
+
+	if res != nil && res.OpMsg != nil {
+		profileResponse(entry, command, res.OpMsg)
+	}
+
+	must.NoError(entry.Add("millis", int32(d.Milliseconds())))
+	must.NoError(entry.Add("ts", time.Now()))
+
+	connInfo := conninfo.Get(ctx)
+
+	if connInfo.Peer.IsValid() {
+		must.NoError(entry.Add("client", connInfo.Peer.Addr().String()))
+	}
+
+	if appName := connInfo.AppName(); appName != "" {
+		must.NoError(entry.Add("appName", appName))
+	}
+
+	if u := connInfo.Conv().Username(); u != "" {
+		must.NoError(entry.Add("user", u))
+	}
+
+	if resErr == nil {
+		must.NoError(entry.Add("ok", float64(1)))
+	} else {
+		must.NoError(entry.Add("ok", float64(0)))
+
+		var e *mongoerrors.Error
+		if errors.As(resErr, &e) {
+			must.NoError(entry.Add("errCode", e.Code))
+			must.NoError(entry.Add("errName", e.Name))
+			must.NoError(entry.Add("errMsg", e.Message))
+		}
+	}
+
+	// plan summary is computed later by the profiler's writer from a copy of the command
+	h.profiler.Record(ctx, db, entry, must.NotFail(doc.Encode()))
+}
+
+// profileResponse adds fields extracted from the command's response to the profiler entry.
+func profileResponse(entry *wirebson.Document, command string, msg *wire.OpMsg) {
+	raw, err := msg.DocumentRaw()
+	if err != nil {
+		return
+	}
+
+	must.NoError(entry.Add("responseLength", int32(len(raw))))
+
+	res, err := raw.DecodeDeep()
+	if err != nil {
+		return
+	}
+
+	switch command {
+	case "find", "aggregate", "getMore":
+		cursor, _ := res.Get("cursor").(*wirebson.Document)
+		if cursor == nil {
+			return
+		}
+
+		batch, _ := cursor.Get("firstBatch").(*wirebson.Array)
+		if batch == nil {
+			batch, _ = cursor.Get("nextBatch").(*wirebson.Array)
+		}
+
+		if batch != nil {
+			must.NoError(entry.Add("nreturned", int32(batch.Len())))
+		}
+
+	case "insert":
+		if n, ok := res.Get("n").(int32); ok {
+			must.NoError(entry.Add("ninserted", n))
+		}
+
+	case "update":
+		if n, ok := res.Get("n").(int32); ok {
+			must.NoError(entry.Add("nMatched", n))
+		}
+
+		if n, ok := res.Get("nModified").(int32); ok {
+			must.NoError(entry.Add("nModified", n))
+		}
+
+	case "delete":
+		if n, ok := res.Get("n").(int32); ok {
+			must.NoError(entry.Add("ndeleted", n))
+		}
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package profiler implements the database profiler that records operations
+// into `system.profile` collections.
+package profiler
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb/documentdb_api"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// Collection is the name of the collection with profiler entries.
+const Collection = "system.profile"
+
+const (
+	// queueSize is the number of entries waiting to be written.
+	queueSize = 1000
+
+	// maxEntries is the maximum number of entries kept in a single `system.profile` collection.
+	// Older entries are removed periodically to emulate a capped collection.
+	maxEntries = 1000
+
+	// trimInterval is the interval between removals of older entries.
+	trimInterval = time.Minute
+
+	// writeTimeout is the timeout for writing or trimming entries.
+	writeTimeout = 10 * time.Second
+)
+
+// Level represents the profiling level.
+type Level int32
+
+const (
+	// LevelOff disables profiling.
+	LevelOff Level = 0
+	// LevelSlow records operations slower than the threshold.
+	LevelSlow Level = 1
+	// LevelAll records all operations.
+	LevelAll Level = 2
+)
+
+// Settings represents profiler settings of a single database.
+type Settings struct {
+	Level      Level
+	SlowMS     int32
+	SampleRate float64
+}
+
+// DefaultSettings are used for databases without explicitly set profiler settings.
+var DefaultSettings = Settings{
+	Level:      LevelOff,
+	SlowMS:     100,
+	SampleRate: 1,
+}
+
+// PlanSummaryFunc returns plan summary for the given command, or an empty string if it is not available.
+type PlanSummaryFunc func(ctx context.Context, command *wirebson.Document) string
+
+// entry represents a single profiler entry waiting to be written.
+type entry struct {
+	db      string
+	doc     *wirebson.Document
+	command wirebson.RawDocument // copy of the command for plan summary; nil if not needed
+}
+
+// Profiler records operations into `system.profile` collections asynchronously.
+type Profiler struct {
+	p           *documentdb.Pool
+	l           *slog.Logger
+	planSummary PlanSummaryFunc
+
+	rw       sync.RWMutex
+	settings map[string]Settings
+
+	queue chan entry
+}
+
+// New creates a new profiler.
+// If planSummary is not nil, it is called by the writer for entries recorded with a command.
+// [Profiler.Run] must be called on the returned value.
+func New(p *documentdb.Pool, l *slog.Logger, planSummary PlanSummaryFunc) *Profiler {
+	return &Profiler{
+		p:           p,
+		l:           l,
+		planSummary: planSummary,
+		settings:    map[string]Settings{},
+		queue:       make(chan entry, queueSize),
+	}
+}
+
+// Settings returns profiler settings for the given database.
+func (pr *Profiler) Settings(db string) Settings {
+	pr.rw.RLock()
+	defer pr.rw.RUnlock()
+
+	if s, ok := pr.settings[db]; ok {
+		return s
+	}
+
+	return DefaultSettings
+}
+
+// SetSettings sets profiler settings for the given database and returns previous ones.
+func (pr *Profiler) SetSettings(db string, s Settings) Settings {
+	pr.rw.Lock()
+	defer pr.rw.Unlock()
+
+	prev, ok := pr.settings[db]
+	if !ok {
+		prev = DefaultSettings
+	}
+
+	pr.settings[db] = s
+
+	return prev
+}
+
+// ShouldRecord returns true if the operation in the given database
+// with the given duration should be recorded.
+func (pr *Profiler) ShouldRecord(db string, d time.Duration) bool {
+	s := pr.Settings(db)
+
+	switch s.Level {
+	case LevelAll:
+		return true
+	case LevelSlow:
+		return d.Milliseconds() >= int64(s.SlowMS) && rand.Float64() < s.SampleRate
+	default:
+		return false
+	}
+}
+
+// Record queues the profiler entry for the given database.
+// If the queue is full, the entry is dropped.
+//
+// If command is not nil, the plan summary for it is added to the entry before it is written,
+// unless the pool is busy.
+func (pr *Profiler) Record(ctx context.Context, db string, doc *wirebson.Document, command wirebson.RawDocument) {
+	select {
+	case pr.queue <- entry{db: db, doc: doc, command: command}:
+	default:
+		pr.l.WarnContext(ctx, "Profiler queue is full, entry dropped", slog.String("db", db))
+	}
+}
+
+// Run writes queued entries until ctx is canceled.
+func (pr *Profiler) Run(ctx context.Context) {
+	ticker := time.NewTicker(trimInterval)
+	defer ticker.Stop()
+
+	// databases with new entries since the last trim
+	written := map[string]struct{}{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			pr.l.InfoContext(ctx, "Profiler stopped", slog.Int("dropped", len(pr.queue)))
+			return
+
+		case e := <-pr.queue:
+			pr.addPlanSummary(ctx, e)
+
+			if err := pr.write(ctx, e); err != nil {
+				pr.l.WarnContext(ctx, "Failed to write profiler entry", slog.String("db", e.db), logging.Error(err))
+				continue
+			}
+
+			written[e.db] = struct{}{}
+
+		case <-ticker.C:
+			for db := range written {
+				if err := pr.trim(ctx, db); err != nil {
+					pr.l.WarnContext(ctx, "Failed to remove old profiler entries", slog.String("db", db), logging.Error(err))
+				}
+			}
+
+			clear(written)
+		}
+	}
+}
+
+// addPlanSummary adds the plan summary to the entry if it is available.
+//
+// It is skipped if the pool is busy, as computing it requires additional queries.
+func (pr *Profiler) addPlanSummary(ctx context.Context, e entry) {
+	if e.command == nil || pr.planSummary == nil {
+		return
+	}
+
+	if pr.p.Busy() {
+		pr.l.DebugContext(ctx, "Pool is busy, plan summary dropped", slog.String("db", e.db))
+		return
+	}
+
+	command, err := e.command.Decode()
+	if err != nil {
+		return
+	}
+
+	if summary := pr.planSummary(ctx, command); summary != "" {
+		must.NoError(e.doc.Add("planSummary", summary))
+	}
+}
+
+// write inserts a single entry.
+func (pr *Profiler) write(ctx context.Context, e entry) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	raw, err := e.doc.Encode()
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return pr.p.WithConn(func(conn *pgx.Conn) error {
+		_, err = documentdb_api.InsertOne(ctx, conn, pr.l, e.db, Collection, raw)
+		return err
+	})
+}
+
+// trim removes entries except the newest [maxEntries] ones from the given database.
+func (pr *Profiler) trim(ctx context.Context, db string) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	spec := must.NotFail(wirebson.MustDocument(
+		"find", Collection,
+		"sort", wirebson.MustDocument("ts", int32(-1)),
+		"projection", wirebson.MustDocument("ts", int32(1)),
+		"skip", int64(maxEntries),
+		"limit", int64(1),
+		"singleBatch", true,
+	).Encode())
+
+	page, cursorID, err := pr.p.Find(ctx, db, spec)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if cursorID != 0 {
+		_ = pr.p.KillCursor(ctx, cursorID)
+	}
+
+	pageDoc, err := page.DecodeDeep()
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	cursor, _ := pageDoc.Get("cursor").(*wirebson.Document)
+	if cursor == nil {
+		return lazyerrors.Errorf("unexpected page: %s", pageDoc.LogMessage())
+	}
+
+	batch, _ := cursor.Get("firstBatch").(*wirebson.Array)
+	if batch == nil || batch.Len() == 0 {
+		return nil
+	}
+
+	first, _ := batch.Get(0).(*wirebson.Document)
+	if first == nil {
+		return nil
+	}
+
+	ts, ok := first.Get("ts").(time.Time)
+	if !ok {
+		return nil
+	}
+
+	deleteSpec := must.NotFail(wirebson.MustDocument(
+		"delete", Collection,
+		"deletes", wirebson.MustArray(wirebson.MustDocument(
+			"q", wirebson.MustDocument("ts", wirebson.MustDocument("$lte", ts)),
+			"limit", int32(0),
+		)),
+	).Encode())
+
+	return pr.p.WithConn(func(conn *pgx.Conn) error {
+		_, _, err = documentdb_api.Delete(ctx, conn, pr.l, db, deleteSpec, nil)
+		return err
+	})
+}
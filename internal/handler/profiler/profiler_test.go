@@ -0,0 +1,47 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profiler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestProfiler(t *testing.T) {
+	t.Parallel()
+
+	pr := New(nil, testutil.Logger(t), nil)
+
+	assert.Equal(t, DefaultSettings, pr.Settings("db"))
+	assert.False(t, pr.ShouldRecord("db", time.Hour))
+
+	prev := pr.SetSettings("db", Settings{Level: LevelSlow, SlowMS: 50, SampleRate: 1})
+	assert.Equal(t, DefaultSettings, prev)
+
+	assert.False(t, pr.ShouldRecord("db", 49*time.Millisecond))
+	assert.True(t, pr.ShouldRecord("db", 50*time.Millisecond))
+	assert.False(t, pr.ShouldRecord("other", time.Hour))
+
+	prev = pr.SetSettings("db", Settings{Level: LevelSlow, SlowMS: 50, SampleRate: 0})
+	assert.Equal(t, Settings{Level: LevelSlow, SlowMS: 50, SampleRate: 1}, prev)
+	assert.False(t, pr.ShouldRecord("db", time.Hour))
+
+	pr.SetSettings("db", Settings{Level: LevelAll, SlowMS: 50, SampleRate: 0})
+	assert.True(t, pr.ShouldRecord("db", 0))
+}
@@ -16,79 +16,205 @@
 package proxy
 
 import (
-	"bufio"
 	"context"
 	"crypto/tls"
+	"fmt"
+	"log/slog"
 	"net"
+	"slices"
+	"sync/atomic"
+	"time"
 
 	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
 
 	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
 	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
 	"github.com/FerretDB/FerretDB/v2/internal/util/tlsutil"
 )
 
+const (
+	// defaultSize is the default number of upstream connections.
+	defaultSize = 4
+
+	// defaultMaxConns is the default maximum number of upstream connections pinned to client connections.
+	defaultMaxConns = 100
+
+	// healthCheckInterval is the interval between upstream health checks.
+	healthCheckInterval = 10 * time.Second
+
+	// healthCheckTimeout is the timeout of a single health check.
+	healthCheckTimeout = 5 * time.Second
+
+	// dialTimeout is the timeout of a single connection attempt.
+	dialTimeout = 5 * time.Second
+)
+
 // Handler handles requests by sending them to another wire protocol compatible service.
+//
+// Requests of client connections should be sent over upstream connections returned by [Handler.Conn],
+// one per client connection, because authentication and other connection state belong
+// to the upstream connection and must not be shared between clients.
+// The number of such established connections is limited by [NewOpts.MaxConns].
+//
+// Handler itself maintains a fixed-size pool of upstream connections shared by all callers
+// for unauthenticated stateless requests (like ones mirrored in shadow mode) and health checks.
+// Requests are multiplexed over them; responses are matched by request IDs.
+// Authentication commands are rejected there.
+// Broken connections are re-established with exponential backoff.
 type Handler struct {
-	conn net.Conn
-	bufr *bufio.Reader
-	bufw *bufio.Writer
+	opts      *NewOpts
+	tlsConfig *tls.Config
+	conns     []*upstream
+	slots     chan struct{} // one value per established pinned upstream connection
+	next      atomic.Uint32
+	requestID atomic.Int32
+}
+
+// NewOpts represents handler options.
+//
+//nolint:vet // for readability
+type NewOpts struct {
+	Addr     string
+	CertFile string // empty value disables TLS
+	KeyFile  string
+	CAFile   string
+
+	L *slog.Logger
+
+	Size     int // number of shared upstream connections; defaults to 4
+	MaxConns int // maximum number of pinned upstream connections; defaults to 100
 }
 
 // New creates a new Handler for a service with given address.
-func New(addr, certFile, keyFile, caFile string) (*Handler, error) {
-	var conn net.Conn
-	var err error
+//
+// Upstream connections are established lazily.
+// [Handler.Run] must be called on the returned value.
+func New(opts *NewOpts) (*Handler, error) {
+	must.NotBeZero(opts)
 
-	if certFile != "" {
-		conn, err = dialTLS(addr, certFile, keyFile, caFile)
-	} else {
-		conn, err = net.Dial("tcp", addr)
+	if opts.Addr == "" {
+		return nil, lazyerrors.New("proxy address is required")
 	}
 
-	if err != nil {
-		return nil, lazyerrors.Error(err)
+	h := &Handler{
+		opts: opts,
+	}
+
+	if opts.CertFile != "" {
+		var err error
+		if h.tlsConfig, err = tlsutil.Config(opts.CertFile, opts.KeyFile, opts.CAFile); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	size := opts.Size
+	if size <= 0 {
+		size = defaultSize
+	}
+
+	maxConns := opts.MaxConns
+	if maxConns <= 0 {
+		maxConns = defaultMaxConns
+	}
+
+	h.slots = make(chan struct{}, maxConns)
+
+	h.conns = make([]*upstream, size)
+	for i := range h.conns {
+		h.conns[i] = newUpstream(h, logging.WithName(opts.L, fmt.Sprintf("upstream-%d", i)), false)
 	}
 
-	return &Handler{
-		conn: conn,
-		bufr: bufio.NewReader(conn),
-		bufw: bufio.NewWriter(conn),
-	}, nil
+	return h, nil
 }
 
-// dialTLS connects to the given address using TLS.
-func dialTLS(addr, certFile, keyFile, caFile string) (net.Conn, error) {
-	config, err := tlsutil.Config(certFile, keyFile, caFile)
-	if err != nil {
-		return nil, err
+// dial connects to the upstream.
+func (h *Handler) dial(ctx context.Context) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	if h.tlsConfig == nil {
+		var d net.Dialer
+
+		conn, err := d.DialContext(ctx, "tcp", h.opts.Addr)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return conn, nil
 	}
 
-	conn, err := tls.Dial("tcp", addr, config)
-	if err != nil {
-		return nil, lazyerrors.Error(err)
+	d := tls.Dialer{
+		Config: h.tlsConfig,
 	}
 
-	if err = conn.Handshake(); err != nil {
+	conn, err := d.DialContext(ctx, "tcp", h.opts.Addr)
+	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
 	return conn, nil
 }
 
-// Run runs the handler until ctx is canceled.
+// Run runs health checks until ctx is canceled.
 //
-// When this method returns, handler is stopped.
+// When this method returns, handler is stopped and all upstream connections are closed.
 func (h *Handler) Run(ctx context.Context) {
-	<-ctx.Done()
-	_ = h.conn.Close()
+	defer func() {
+		for _, u := range h.conns {
+			u.close()
+		}
+	}()
+
+	t := time.NewTicker(healthCheckInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		for _, u := range h.conns {
+			u.healthCheck(ctx)
+		}
+	}
 }
 
-// Handle processes a request by sending it to another wire protocol compatible service.
+// Handle processes a request by sending it to another wire protocol compatible service
+// over a shared upstream connection.
+//
+// Authentication commands are rejected, because the authentication state would be shared
+// with all other callers; use [Handler.Conn] for them.
+//
+// See [Conn.Handle] for details.
 func (h *Handler) Handle(ctx context.Context, req *middleware.Request) (*middleware.Response, error) {
-	deadline, _ := ctx.Deadline()
-	_ = h.conn.SetDeadline(deadline)
+	if command := requestCommand(req); slices.Contains(authCommands, command) {
+		return nil, lazyerrors.Errorf("%s can't be sent over shared upstream connection", command)
+	}
+
+	return h.handle(ctx, h.pick(), req)
+}
+
+// Conn returns a new upstream connection for a single client connection.
+//
+// The connection is established lazily and re-established after failures
+// until the client sends an authentication command.
+// After that, the failed connection is closed, because its authentication state would be lost;
+// see [Conn.Closed].
+// The caller must call [Conn.Close] when the client connection is closed.
+func (h *Handler) Conn(l *slog.Logger) *Conn {
+	return &Conn{
+		h: h,
+		u: newUpstream(h, logging.WithName(l, "upstream"), true),
+	}
+}
 
+// handle sends the request over the given upstream connection.
+func (h *Handler) handle(ctx context.Context, u *upstream, req *middleware.Request) (*middleware.Response, error) {
 	var body wire.MsgBody
 
 	switch {
@@ -100,15 +226,20 @@ func (h *Handler) Handle(ctx context.Context, req *middleware.Request) (*middlew
 		return nil, lazyerrors.New("request body is nil")
 	}
 
-	if err := wire.WriteMessage(h.bufw, req.WireHeader(), body); err != nil {
-		return nil, lazyerrors.Error(err)
+	if u.pinned && slices.Contains(authCommands, requestCommand(req)) {
+		u.markAuthenticated()
 	}
 
-	if err := h.bufw.Flush(); err != nil {
-		return nil, lazyerrors.Error(err)
+	// the upstream does not reply to such requests
+	if req.OpMsg != nil && req.OpMsg.Flags.FlagSet(wire.OpMsgMoreToCome) {
+		if _, err := u.send(ctx, req.WireHeader(), body, nil); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return nil, nil
 	}
 
-	respHeader, respBody, err := wire.ReadMessage(h.bufr)
+	respHeader, respBody, err := u.roundTrip(ctx, req.WireHeader(), body)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -116,7 +247,75 @@ func (h *Handler) Handle(ctx context.Context, req *middleware.Request) (*middlew
 	return middleware.ResponseWire(respHeader, respBody)
 }
 
+// pick returns the next upstream connection, preferring connected ones.
+func (h *Handler) pick() *upstream {
+	n := uint32(len(h.conns))
+	start := h.next.Add(1)
+
+	for i := range n {
+		if u := h.conns[(start+i)%n]; u.connected() {
+			return u
+		}
+	}
+
+	return h.conns[start%n]
+}
+
+// authCommands contains commands that change the authentication state of the upstream connection.
+var authCommands = []string{
+	"authenticate",
+	"logout",
+	"saslContinue",
+	"saslStart",
+}
+
+// requestCommand returns the command name of the request, or an empty string.
+func requestCommand(req *middleware.Request) string {
+	var doc *wirebson.Document
+	var err error
+
+	switch {
+	case req.OpMsg != nil:
+		doc, err = req.OpMsg.Section0()
+	case req.OpQuery != nil:
+		doc, err = req.OpQuery.Query()
+	}
+
+	if err != nil || doc == nil {
+		return ""
+	}
+
+	return doc.Command()
+}
+
+// Conn represents an upstream connection pinned to a single client connection.
+type Conn struct {
+	h *Handler
+	u *upstream
+}
+
+// Handle processes a request by sending it to another wire protocol compatible service.
+//
+// For requests with moreToCome flag, the response is not awaited, and nil response is returned.
+//
+// Returned errors indicate upstream failures; they should be reported to the client as network errors.
+func (c *Conn) Handle(ctx context.Context, req *middleware.Request) (*middleware.Response, error) {
+	return c.h.handle(ctx, c.u, req)
+}
+
+// Closed returns true if the upstream connection failed after authentication and would not be re-established.
+// The client connection should be closed in that case.
+func (c *Conn) Closed() bool {
+	return c.u.isClosed()
+}
+
+// Close closes the upstream connection.
+func (c *Conn) Close() {
+	c.u.close()
+}
+
 // check interfaces
 var (
 	_ middleware.HandleFunc = (*Handler)(nil).Handle
+	_ middleware.HandleFunc = (*Conn)(nil).Handle
 )
@@ -0,0 +1,312 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+// fakeUpstream replies to each request with the request's `n` field.
+// It returns upstream listener.
+func fakeUpstream(t *testing.T) net.Listener {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { lis.Close() })
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				bufr := bufio.NewReader(conn)
+				bufw := bufio.NewWriter(conn)
+
+				for {
+					header, body, err := wire.ReadMessage(bufr)
+					if err != nil {
+						return
+					}
+
+					doc, err := body.(*wire.OpMsg).DocumentDeep()
+					if err != nil {
+						return
+					}
+
+					res := wire.MustOpMsg("n", doc.Get("n"), "ok", float64(1))
+					b, err := res.MarshalBinary()
+					if err != nil {
+						return
+					}
+
+					resHeader := &wire.MsgHeader{
+						MessageLength: int32(wire.MsgHeaderLen + len(b)),
+						ResponseTo:    header.RequestID,
+						OpCode:        wire.OpCodeMsg,
+					}
+
+					if err = wire.WriteMessage(bufw, resHeader, res); err != nil {
+						return
+					}
+
+					if err = bufw.Flush(); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	return lis
+}
+
+// request returns a new request with the given `n` field and request ID.
+func request(t *testing.T, n int32, requestID int32) *middleware.Request {
+	t.Helper()
+
+	msg := wire.MustOpMsg("ping", int32(1), "n", n, "$db", "admin")
+	b, err := msg.MarshalBinary()
+	require.NoError(t, err)
+
+	header := &wire.MsgHeader{
+		MessageLength: int32(wire.MsgHeaderLen + len(b)),
+		RequestID:     requestID,
+		OpCode:        wire.OpCodeMsg,
+	}
+
+	return middleware.RequestWire(header, msg)
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+	lis := fakeUpstream(t)
+
+	h, err := New(&NewOpts{
+		Addr: lis.Addr().String(),
+		L:    testutil.Logger(t),
+		Size: 2,
+	})
+	require.NoError(t, err)
+
+	runCtx, runCancel := context.WithCancel(ctx)
+	runDone := make(chan struct{})
+
+	go func() {
+		defer close(runDone)
+		h.Run(runCtx)
+	}()
+
+	t.Cleanup(func() {
+		runCancel()
+		<-runDone
+	})
+
+	t.Run("Multiplex", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		for i := range int32(50) {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				// the same request ID for all requests, like from different clients
+				resp, err := h.Handle(ctx, request(t, i, 42))
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				assert.Equal(t, int32(42), resp.WireHeader().ResponseTo)
+
+				doc, err := resp.OpMsg.DocumentDeep()
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				assert.Equal(t, i, doc.Get("n"))
+			}()
+		}
+
+		wg.Wait()
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		h, err := New(&NewOpts{
+			Addr: "127.0.0.1:1",
+			L:    testutil.Logger(t),
+			Size: 1,
+		})
+		require.NoError(t, err)
+
+		_, err = h.Handle(ctx, request(t, 1, 1))
+		require.Error(t, err)
+
+		// backoff is not passed yet
+		_, err = h.Handle(ctx, request(t, 1, 1))
+		require.ErrorIs(t, err, errUnavailable)
+	})
+
+	t.Run("Reconnect", func(t *testing.T) {
+		u := h.conns[0]
+
+		u.m.Lock()
+		conn := u.conn
+		u.m.Unlock()
+
+		if conn == nil {
+			t.Skip("not connected")
+		}
+
+		u.fail(conn, net.ErrClosed)
+
+		u.m.Lock()
+		u.retryAt = time.Time{}
+		u.m.Unlock()
+
+		_, _, err := u.roundTrip(ctx, request(t, 1, 1).WireHeader(), request(t, 1, 1).OpMsg)
+		require.NoError(t, err)
+		assert.True(t, u.connected())
+	})
+
+	t.Run("SharedAuth", func(t *testing.T) {
+		msg := wire.MustOpMsg("saslStart", int32(1), "$db", "admin")
+		b, err := msg.MarshalBinary()
+		require.NoError(t, err)
+
+		header := &wire.MsgHeader{
+			MessageLength: int32(wire.MsgHeaderLen + len(b)),
+			RequestID:     1,
+			OpCode:        wire.OpCodeMsg,
+		}
+
+		_, err = h.Handle(ctx, middleware.RequestWire(header, msg))
+		require.Error(t, err)
+	})
+
+	t.Run("Pinned", func(t *testing.T) {
+		c := h.Conn(testutil.Logger(t))
+		defer c.Close()
+
+		resp, err := c.Handle(ctx, request(t, 1, 1))
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), resp.WireHeader().ResponseTo)
+
+		c.u.m.Lock()
+		conn := c.u.conn
+		c.u.m.Unlock()
+
+		require.NotNil(t, conn)
+
+		// pinned connection is re-established before authentication
+		c.u.fail(conn, net.ErrClosed)
+		assert.False(t, c.Closed())
+
+		c.u.m.Lock()
+		c.u.retryAt = time.Time{}
+		c.u.m.Unlock()
+
+		_, err = c.Handle(ctx, request(t, 2, 2))
+		require.NoError(t, err)
+	})
+
+	t.Run("PinnedAuthenticated", func(t *testing.T) {
+		c := h.Conn(testutil.Logger(t))
+		defer c.Close()
+
+		msg := wire.MustOpMsg("saslStart", int32(1), "n", int32(1), "$db", "admin")
+		b, err := msg.MarshalBinary()
+		require.NoError(t, err)
+
+		header := &wire.MsgHeader{
+			MessageLength: int32(wire.MsgHeaderLen + len(b)),
+			RequestID:     1,
+			OpCode:        wire.OpCodeMsg,
+		}
+
+		_, err = c.Handle(ctx, middleware.RequestWire(header, msg))
+		require.NoError(t, err)
+
+		c.u.m.Lock()
+		conn := c.u.conn
+		c.u.m.Unlock()
+
+		require.NotNil(t, conn)
+
+		// authentication state is lost, so pinned connection is not re-established
+		c.u.fail(conn, net.ErrClosed)
+		assert.True(t, c.Closed())
+
+		_, err = c.Handle(ctx, request(t, 2, 2))
+		require.ErrorIs(t, err, errClosed)
+	})
+
+	t.Run("MaxConns", func(t *testing.T) {
+		h, err := New(&NewOpts{
+			Addr:     lis.Addr().String(),
+			L:        testutil.Logger(t),
+			MaxConns: 1,
+		})
+		require.NoError(t, err)
+
+		c1 := h.Conn(testutil.Logger(t))
+		defer c1.Close()
+
+		_, err = c1.Handle(ctx, request(t, 1, 1))
+		require.NoError(t, err)
+
+		c2 := h.Conn(testutil.Logger(t))
+		defer c2.Close()
+
+		_, err = c2.Handle(ctx, request(t, 2, 2))
+		require.ErrorIs(t, err, errTooManyConns)
+
+		c1.Close()
+
+		_, err = c2.Handle(ctx, request(t, 3, 3))
+		require.NoError(t, err)
+	})
+
+	t.Run("MoreToCome", func(t *testing.T) {
+		c := h.Conn(testutil.Logger(t))
+		defer c.Close()
+
+		req := request(t, 1, 1)
+		req.OpMsg.Flags |= wire.OpMsgFlags(wire.OpMsgMoreToCome)
+
+		resp, err := c.Handle(ctx, req)
+		require.NoError(t, err)
+		assert.Nil(t, resp)
+	})
+}
@@ -0,0 +1,401 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+)
+
+const (
+	// minBackoff is the minimal delay between reconnection attempts.
+	minBackoff = 100 * time.Millisecond
+
+	// maxBackoff is the maximal delay between reconnection attempts.
+	maxBackoff = 10 * time.Second
+)
+
+var (
+	// errUnavailable is returned when the upstream connection is broken
+	// and the reconnection backoff has not passed yet.
+	errUnavailable = errors.New("upstream is unavailable")
+
+	// errClosed is returned when the handler is stopped,
+	// or when the pinned upstream connection with authentication state failed.
+	errClosed = errors.New("upstream connection is closed")
+
+	// errTooManyConns is returned when the limit of pinned upstream connections is reached.
+	errTooManyConns = errors.New("too many upstream connections")
+)
+
+// result represents an upstream response or error.
+type result struct {
+	header *wire.MsgHeader
+	body   wire.MsgBody
+	err    error
+}
+
+// upstream represents a single multiplexed upstream connection.
+//
+// Requests are written sequentially by callers;
+// responses are read by a separate goroutine and dispatched by their ResponseTo field.
+//
+// Pinned upstream holds one of the handler's connection slots while connected.
+// It is re-established after failure only until the first authentication command is sent over it,
+// because the authentication state would be lost.
+type upstream struct {
+	h      *Handler
+	l      *slog.Logger
+	pinned bool
+
+	m             sync.Mutex
+	conn          net.Conn // nil if not connected
+	bufw          *bufio.Writer
+	pending       map[int32]chan result
+	closed        bool
+	authenticated bool          // authentication command was sent
+	dialing       chan struct{} // closed when the current connection attempt finishes; nil if there is none
+
+	// for reconnection backoff
+	attempts int
+	retryAt  time.Time
+}
+
+// newUpstream creates a new disconnected upstream.
+func newUpstream(h *Handler, l *slog.Logger, pinned bool) *upstream {
+	return &upstream{
+		h:       h,
+		l:       l,
+		pinned:  pinned,
+		pending: map[int32]chan result{},
+	}
+}
+
+// connected returns true if the upstream connection is established.
+func (u *upstream) connected() bool {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	return u.conn != nil
+}
+
+// connect establishes the connection if needed, respecting backoff.
+//
+// It must be called without u.m held; concurrent calls wait for a single connection attempt.
+func (u *upstream) connect(ctx context.Context) error {
+	u.m.Lock()
+
+	for u.dialing != nil {
+		dialing := u.dialing
+		u.m.Unlock()
+
+		select {
+		case <-dialing:
+		case <-ctx.Done():
+			return lazyerrors.Error(context.Cause(ctx))
+		}
+
+		u.m.Lock()
+	}
+
+	switch {
+	case u.closed:
+		u.m.Unlock()
+		return errClosed
+	case u.conn != nil:
+		u.m.Unlock()
+		return nil
+	case time.Now().Before(u.retryAt):
+		u.m.Unlock()
+		return errUnavailable
+	}
+
+	if u.pinned {
+		select {
+		case u.h.slots <- struct{}{}:
+		default:
+			u.m.Unlock()
+			return errTooManyConns
+		}
+	}
+
+	dialing := make(chan struct{})
+	u.dialing = dialing
+
+	u.m.Unlock()
+
+	// do not hold u.m while dialing, so callers like connected() are not blocked
+	conn, err := u.h.dial(ctx)
+
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	u.dialing = nil
+	close(dialing)
+
+	if err == nil && u.closed {
+		_ = conn.Close()
+		err = errClosed
+	}
+
+	if err != nil {
+		if u.pinned {
+			<-u.h.slots
+		}
+
+		if errors.Is(err, errClosed) {
+			return err
+		}
+
+		u.backoff()
+		u.l.WarnContext(ctx, "Failed to connect to upstream", slog.Int("attempts", u.attempts), logging.Error(err))
+
+		return lazyerrors.Error(err)
+	}
+
+	if u.attempts > 0 {
+		u.l.InfoContext(ctx, "Reconnected to upstream", slog.Int("attempts", u.attempts))
+	}
+
+	u.attempts = 0
+	u.retryAt = time.Time{}
+	u.conn = conn
+	u.bufw = bufio.NewWriter(conn)
+
+	go u.readLoop(conn)
+
+	return nil
+}
+
+// backoff schedules the next connection attempt.
+//
+// It must be called with u.m held.
+func (u *upstream) backoff() {
+	u.attempts++
+
+	d := maxBackoff
+	if u.attempts < 16 {
+		d = min(minBackoff<<u.attempts, maxBackoff)
+	}
+
+	u.retryAt = time.Now().Add(d)
+}
+
+// fail closes the given connection (if it is still current) and fails all pending requests.
+func (u *upstream) fail(conn net.Conn, err error) {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	if u.conn != conn {
+		return
+	}
+
+	_ = u.conn.Close()
+	u.conn = nil
+	u.bufw = nil
+
+	if u.pinned {
+		<-u.h.slots
+	}
+
+	if !u.closed {
+		u.l.Warn("Upstream connection failed", logging.Error(err))
+		u.backoff()
+
+		// authentication state is lost
+		u.closed = u.pinned && u.authenticated
+	}
+
+	for id, ch := range u.pending {
+		ch <- result{err: err}
+		delete(u.pending, id)
+	}
+}
+
+// readLoop reads responses from the given connection until it fails.
+func (u *upstream) readLoop(conn net.Conn) {
+	bufr := bufio.NewReader(conn)
+
+	for {
+		header, body, err := wire.ReadMessage(bufr)
+		if err != nil {
+			u.fail(conn, lazyerrors.Error(err))
+			return
+		}
+
+		u.m.Lock()
+		ch := u.pending[header.ResponseTo]
+		delete(u.pending, header.ResponseTo)
+		u.m.Unlock()
+
+		if ch == nil {
+			// the caller gave up waiting
+			u.l.Debug("Dropping upstream response", slog.Int("response_to", int(header.ResponseTo)))
+			continue
+		}
+
+		ch <- result{header: header, body: body}
+	}
+}
+
+// send sends the request.
+//
+// The request ID is replaced with a unique one to avoid clashes between clients; it is returned.
+// If ch is not nil, the response or connection error is sent to it.
+func (u *upstream) send(ctx context.Context, header *wire.MsgHeader, body wire.MsgBody, ch chan result) (int32, error) {
+	if err := u.connect(ctx); err != nil {
+		return 0, err
+	}
+
+	u.m.Lock()
+
+	// the connection could fail after connect returned
+	conn := u.conn
+	if conn == nil {
+		u.m.Unlock()
+		return 0, errUnavailable
+	}
+
+	id := u.h.requestID.Add(1)
+	if ch != nil {
+		u.pending[id] = ch
+	}
+
+	h := *header
+	h.RequestID = id
+
+	err := wire.WriteMessage(u.bufw, &h, body)
+	if err == nil {
+		err = u.bufw.Flush()
+	}
+
+	u.m.Unlock()
+
+	if err != nil {
+		err = lazyerrors.Error(err)
+		u.fail(conn, err)
+
+		// ch already got the error
+		if ch == nil {
+			return 0, err
+		}
+	}
+
+	return id, nil
+}
+
+// roundTrip sends the request and waits for the response.
+//
+// The response's ResponseTo is set to the original request ID.
+func (u *upstream) roundTrip(ctx context.Context, header *wire.MsgHeader, body wire.MsgBody) (*wire.MsgHeader, wire.MsgBody, error) {
+	ch := make(chan result, 1)
+
+	id, err := u.send(ctx, header, body, ch)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	select {
+	case res := <-ch:
+		if res.err != nil {
+			return nil, nil, res.err
+		}
+
+		res.header.ResponseTo = header.RequestID
+
+		return res.header, res.body, nil
+
+	case <-ctx.Done():
+		u.m.Lock()
+		delete(u.pending, id)
+		u.m.Unlock()
+
+		return nil, nil, lazyerrors.Error(context.Cause(ctx))
+	}
+}
+
+// healthCheck pings the upstream, reconnecting if needed.
+func (u *upstream) healthCheck(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	msg := wire.MustOpMsg("ping", int32(1), "$db", "admin")
+
+	b, err := msg.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+
+	header := &wire.MsgHeader{
+		MessageLength: int32(wire.MsgHeaderLen + len(b)),
+		OpCode:        wire.OpCodeMsg,
+	}
+
+	u.m.Lock()
+	conn := u.conn
+	u.m.Unlock()
+
+	if _, _, err = u.roundTrip(ctx, header, msg); err != nil {
+		if errors.Is(err, errUnavailable) || errors.Is(err, errClosed) {
+			return
+		}
+
+		u.l.WarnContext(ctx, "Upstream health check failed", logging.Error(err))
+
+		// treat timeouts as connection failures
+		if conn != nil {
+			u.fail(conn, err)
+		}
+	}
+}
+
+// markAuthenticated records that the authentication command is sent over the upstream connection,
+// so pinned connection is not re-established after failure.
+func (u *upstream) markAuthenticated() {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	u.authenticated = true
+}
+
+// isClosed returns true if the upstream connection is closed and would not be re-established.
+func (u *upstream) isClosed() bool {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	return u.closed
+}
+
+// close closes the connection and prevents reconnections.
+func (u *upstream) close() {
+	u.m.Lock()
+	u.closed = true
+	conn := u.conn
+	u.m.Unlock()
+
+	if conn != nil {
+		u.fail(conn, errClosed)
+	}
+}
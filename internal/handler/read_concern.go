@@ -0,0 +1,181 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// readConcernLevels contains valid `readConcern.level` values.
+var readConcernLevels = []string{"local", "majority", "linearizable", "available", "snapshot"}
+
+// readConcern represents a parsed `readConcern` document.
+type readConcern struct {
+	level            string             // empty if not set
+	afterClusterTime wirebson.Timestamp // zero if not set
+	atClusterTime    wirebson.Timestamp // zero if not set; only for snapshot level
+}
+
+// snapshot returns true if the read concern requires reading from a snapshot.
+func (rc *readConcern) snapshot() bool {
+	return rc.level == "snapshot"
+}
+
+// getReadConcern returns the read concern of the command.
+// If it was not supplied, the zero value is returned.
+func getReadConcern(doc *wirebson.Document) (*readConcern, error) {
+	var res readConcern
+
+	v := doc.Get("readConcern")
+	if v == nil {
+		return &res, nil
+	}
+
+	command := doc.Command()
+
+	rcV, ok := v.(wirebson.AnyDocument)
+	if !ok {
+		msg := fmt.Sprintf(
+			"BSON field '%s.readConcern' is the wrong type '%s', expected type 'object'",
+			command, aliasFromType(v),
+		)
+
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, command)
+	}
+
+	rc, err := rcV.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	for k, v := range rc.All() {
+		switch k {
+		case "level":
+			level, ok := v.(string)
+			if !ok || !slices.Contains(readConcernLevels, level) {
+				msg := fmt.Sprintf(
+					"readConcern.level must be either 'local', 'majority', 'linearizable', 'available', or 'snapshot'; found: %v",
+					v,
+				)
+
+				return nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, command)
+			}
+
+			res.level = level
+
+		case "afterClusterTime", "atClusterTime":
+			ts, ok := v.(wirebson.Timestamp)
+			if !ok {
+				msg := fmt.Sprintf(
+					"BSON field 'readConcern.%s' is the wrong type '%s', expected type 'timestamp'",
+					k, aliasFromType(v),
+				)
+
+				return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, command)
+			}
+
+			if ts == 0 {
+				msg := fmt.Sprintf("%s cannot be a null timestamp", k)
+				return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, command)
+			}
+
+			if k == "afterClusterTime" {
+				res.afterClusterTime = ts
+			} else {
+				res.atClusterTime = ts
+			}
+		}
+	}
+
+	if res.afterClusterTime != 0 && res.atClusterTime != 0 {
+		msg := "Specifying a timestamp for readConcern snapshot in a causally consistent session is not allowed"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, command)
+	}
+
+	if res.atClusterTime != 0 && !res.snapshot() {
+		msg := "readConcern atClusterTime value is only allowed for readConcern level snapshot"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, command)
+	}
+
+	return &res, nil
+}
+
+// snapshotFunc returns the first page of the cursor, the cursor ID, and the snapshot's WAL location.
+// See [documentdb.Pool.FindSnapshot].
+type snapshotFunc func(ctx context.Context, db string, spec wirebson.RawDocument, lsn uint64) (wirebson.RawDocument, int64, uint64, error)
+
+// readSnapshot returns the first page and the cursor ID of the read command with `readConcern: {level: "snapshot"}`.
+// The page's cursor document contains `atClusterTime` field that could be used by the next reads
+// to see the same data.
+//
+// Snapshot reads are always executed on the primary; f should be a method of [Handler.Pool].
+func (h *Handler) readSnapshot(ctx context.Context, db string, spec wirebson.RawDocument, doc *wirebson.Document, rc *readConcern, f snapshotFunc) (wirebson.RawDocument, int64, error) {
+	command := doc.Command()
+
+	if err := h.checkClusterTime(ctx, "atClusterTime", rc.atClusterTime, command); err != nil {
+		return nil, 0, err
+	}
+
+	page, cursorID, lsn, err := f(ctx, db, spec, uint64(rc.atClusterTime))
+	if err != nil {
+		if errors.Is(err, documentdb.ErrSnapshotTooOld) {
+			msg := fmt.Sprintf(
+				"Read timestamp %d is older than the oldest available timestamp",
+				uint64(rc.atClusterTime),
+			)
+
+			return nil, 0, mongoerrors.NewWithArgument(mongoerrors.ErrSnapshotTooOld, msg, command)
+		}
+
+		return nil, 0, lazyerrors.Error(err)
+	}
+
+	// snapshot's WAL location was read from the primary
+	h.advanceClusterTime(wirebson.Timestamp(lsn))
+
+	pageDoc, err := page.Decode()
+	if err != nil {
+		return nil, 0, lazyerrors.Error(err)
+	}
+
+	cursorV, ok := pageDoc.Get("cursor").(wirebson.AnyDocument)
+	if !ok {
+		return nil, 0, lazyerrors.Errorf("unexpected page %v", pageDoc)
+	}
+
+	cursorDoc, err := cursorV.Decode()
+	if err != nil {
+		return nil, 0, lazyerrors.Error(err)
+	}
+
+	must.NoError(cursorDoc.Add("atClusterTime", wirebson.Timestamp(lsn)))
+	must.NoError(pageDoc.Replace("cursor", cursorDoc))
+
+	if page, err = pageDoc.Encode(); err != nil {
+		return nil, 0, lazyerrors.Error(err)
+	}
+
+	return page, cursorID, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+)
+
+func TestGetReadConcern(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		rc any
+
+		expected *readConcern
+		code     mongoerrors.Code
+	}{
+		"Missing": {
+			expected: &readConcern{},
+		},
+		"Local": {
+			rc:       wirebson.MustDocument("level", "local"),
+			expected: &readConcern{level: "local"},
+		},
+		"AfterClusterTime": {
+			rc:       wirebson.MustDocument("level", "majority", "afterClusterTime", wirebson.Timestamp(42)),
+			expected: &readConcern{level: "majority", afterClusterTime: 42},
+		},
+		"Snapshot": {
+			rc:       wirebson.MustDocument("level", "snapshot"),
+			expected: &readConcern{level: "snapshot"},
+		},
+		"SnapshotAtClusterTime": {
+			rc:       wirebson.MustDocument("level", "snapshot", "atClusterTime", wirebson.Timestamp(42)),
+			expected: &readConcern{level: "snapshot", atClusterTime: 42},
+		},
+		"AtClusterTimeWithoutSnapshot": {
+			rc:   wirebson.MustDocument("level", "majority", "atClusterTime", wirebson.Timestamp(42)),
+			code: mongoerrors.ErrInvalidOptions,
+		},
+		"AfterAndAtClusterTime": {
+			rc: wirebson.MustDocument(
+				"level", "snapshot",
+				"afterClusterTime", wirebson.Timestamp(42),
+				"atClusterTime", wirebson.Timestamp(42),
+			),
+			code: mongoerrors.ErrInvalidOptions,
+		},
+		"NullTimestamp": {
+			rc:   wirebson.MustDocument("afterClusterTime", wirebson.Timestamp(0)),
+			code: mongoerrors.ErrInvalidOptions,
+		},
+		"WrongAfterClusterTimeType": {
+			rc:   wirebson.MustDocument("afterClusterTime", int64(42)),
+			code: mongoerrors.ErrTypeMismatch,
+		},
+		"InvalidLevel": {
+			rc:   wirebson.MustDocument("level", "invalid"),
+			code: mongoerrors.ErrFailedToParse,
+		},
+		"WrongType": {
+			rc:   "local",
+			code: mongoerrors.ErrTypeMismatch,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doc := wirebson.MustDocument("find", "test", "$db", "test")
+			if tc.rc != nil {
+				require.NoError(t, doc.Add("readConcern", tc.rc))
+			}
+
+			rc, err := getReadConcern(doc)
+
+			if tc.code != 0 {
+				var e *mongoerrors.Error
+				require.ErrorAs(t, err, &e)
+				assert.Equal(t, tc.code, mongoerrors.Code(e.Code))
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, rc)
+			assert.Equal(t, tc.expected.level == "snapshot", rc.snapshot())
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// readPool returns the pool that should be used for the read command
+// according to its `$readPreference`,
+// after waiting for it to reach the cluster time requested by the command (see [Handler.waitForClusterTime]).
+func (h *Handler) readPool(ctx context.Context, doc *wirebson.Document) (*documentdb.Pool, error) {
+	pool, err := h.selectReadPool(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = h.waitForClusterTime(ctx, pool, doc); err != nil {
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+// selectReadPool returns the pool that should be used for the read command
+// according to its `$readPreference`.
+//
+// `secondary` mode requires a replica; `secondaryPreferred` and `nearest` modes use a replica if possible.
+// Other modes (and the absence of `$readPreference`) use the primary.
+// Replicas with replication lag exceeding `maxStalenessSeconds` are not used.
+func (h *Handler) selectReadPool(doc *wirebson.Document) (*documentdb.Pool, error) {
+	v := doc.Get("$readPreference")
+	if v == nil {
+		return h.Pool, nil
+	}
+
+	rp, ok := v.(wirebson.AnyDocument)
+	if !ok {
+		msg := fmt.Sprintf("$readPreference has type %s (expected object)", aliasFromType(v))
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, "$readPreference")
+	}
+
+	mode, err := getRequiredParam[string](rp, "mode")
+	if err != nil {
+		return nil, err
+	}
+
+	maxStaleness, err := getOptionalParamAny(rp, "maxStalenessSeconds", int32(-1))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var seconds float64
+
+	switch v := maxStaleness.(type) {
+	case int32:
+		seconds = float64(v)
+	case int64:
+		seconds = float64(v)
+	case float64:
+		seconds = v
+	default:
+		msg := fmt.Sprintf("maxStalenessSeconds has type %s (expected number)", aliasFromType(v))
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, "$readPreference")
+	}
+
+	// -1 means no maximum
+	if seconds < 0 && seconds != -1 {
+		msg := fmt.Sprintf("maxStalenessSeconds must be a non-negative number or -1, got %v", seconds)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "$readPreference")
+	}
+
+	var staleness time.Duration
+	if seconds > 0 {
+		staleness = time.Duration(seconds * float64(time.Second))
+	}
+
+	switch mode {
+	case "primary", "primaryPreferred":
+		if mode == "primary" && staleness > 0 {
+			msg := "mode 'primary' does not allow for maxStalenessSeconds"
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "$readPreference")
+		}
+
+		return h.Pool, nil
+
+	case "secondary":
+		if p := h.Pool.Replica(staleness); p != nil {
+			return p, nil
+		}
+
+		msg := "Could not find host matching read preference { mode: \"secondary\" }"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToSatisfyReadPreference, msg, "$readPreference")
+
+	case "secondaryPreferred", "nearest":
+		if p := h.Pool.Replica(staleness); p != nil {
+			return p, nil
+		}
+
+		return h.Pool, nil
+
+	default:
+		msg := fmt.Sprintf("Could not parse $readPreference mode '%s'", mode)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, "$readPreference")
+	}
+}
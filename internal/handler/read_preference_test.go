@@ -0,0 +1,94 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+)
+
+func TestSelectReadPool(t *testing.T) {
+	t.Parallel()
+
+	// pool without replicas; no connections are used
+	h := &Handler{
+		NewOpts: &NewOpts{
+			Pool: new(documentdb.Pool),
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		rp   any
+		code mongoerrors.Code
+	}{
+		"Missing": {},
+		"Primary": {
+			rp: wirebson.MustDocument("mode", "primary"),
+		},
+		"SecondaryPreferred": {
+			rp: wirebson.MustDocument("mode", "secondaryPreferred", "maxStalenessSeconds", int32(90)),
+		},
+		"Nearest": {
+			rp: wirebson.MustDocument("mode", "nearest", "maxStalenessSeconds", int32(-1)),
+		},
+		"Secondary": {
+			rp:   wirebson.MustDocument("mode", "secondary"),
+			code: mongoerrors.ErrFailedToSatisfyReadPreference,
+		},
+		"PrimaryMaxStaleness": {
+			rp:   wirebson.MustDocument("mode", "primary", "maxStalenessSeconds", int32(90)),
+			code: mongoerrors.ErrBadValue,
+		},
+		"NegativeMaxStaleness": {
+			rp:   wirebson.MustDocument("mode", "nearest", "maxStalenessSeconds", int32(-2)),
+			code: mongoerrors.ErrBadValue,
+		},
+		"InvalidMode": {
+			rp:   wirebson.MustDocument("mode", "invalid"),
+			code: mongoerrors.ErrFailedToParse,
+		},
+		"InvalidType": {
+			rp:   "secondary",
+			code: mongoerrors.ErrTypeMismatch,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doc := wirebson.MustDocument("find", "test", "$db", "test")
+			if tc.rp != nil {
+				require.NoError(t, doc.Add("$readPreference", tc.rp))
+			}
+
+			p, err := h.selectReadPool(doc)
+			if tc.code != 0 {
+				var e *mongoerrors.Error
+				require.ErrorAs(t, err, &e)
+				assert.Equal(t, int32(tc.code), e.Code)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Same(t, h.Pool, p)
+		})
+	}
+}
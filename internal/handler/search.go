@@ -0,0 +1,400 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// Atlas Search stages are translated to DocumentDB stages:
+//   - `$vectorSearch` to `$search` stage with `cosmosSearch` operator;
+//   - `$search` with `text` operator to `$match` stage with `$text` query sorted by text score.
+//
+// `$meta` expressions for Atlas scores in the following stages are replaced accordingly.
+// `$search` stages with `cosmosSearch` operator are passed to DocumentDB as is.
+
+// vectorSearchMaxCandidates is the maximal value of `$vectorSearch` `numCandidates` field.
+const vectorSearchMaxCandidates = 10000
+
+// vectorSearchMaxEfSearch is the maximal value of DocumentDB `efSearch` parameter of HNSW indexes.
+const vectorSearchMaxEfSearch = 1000
+
+// rewriteSearch replaces the leading `$vectorSearch` or `$search` stage of the aggregation pipeline
+// with DocumentDB equivalents.
+//
+// If the pipeline does not start with those stages, the spec is returned as is.
+func (h *Handler) rewriteSearch(ctx context.Context, dbName string, doc *wirebson.Document, spec wirebson.RawDocument) (wirebson.RawDocument, error) {
+	collection, pipeline, stage := firstStage(doc)
+	if stage == nil {
+		return spec, nil
+	}
+
+	command := stage.Command()
+	if command != "$vectorSearch" && command != "$search" {
+		return spec, nil
+	}
+
+	argV, _ := stage.Get(command).(wirebson.AnyDocument)
+	if argV == nil {
+		msg := fmt.Sprintf("%s stage specification must be an object", command)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, "aggregate")
+	}
+
+	arg, err := argV.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var aggregate any = collection
+	var stages []*wirebson.Document
+	var meta string
+	var score any
+
+	switch command {
+	case "$vectorSearch":
+		var index *wirebson.Document
+		if index, err = h.vectorSearchIndex(ctx, dbName, collection, arg); err != nil {
+			return nil, err
+		}
+
+		meta = "vectorSearchScore"
+
+		// Atlas returns no documents if the index does not exist
+		if index == nil {
+			aggregate = int32(1)
+			stages = []*wirebson.Document{must.NotFail(wirebson.NewDocument("$documents", wirebson.MakeArray(0)))}
+			score = must.NotFail(wirebson.NewDocument("$literal", wirebson.Null))
+
+			break
+		}
+
+		var s *wirebson.Document
+		if s, err = vectorSearchStage(arg, index); err != nil {
+			return nil, err
+		}
+
+		stages = []*wirebson.Document{s}
+		score = vectorSearchScore(index.Get("similarity"))
+
+	case "$search":
+		if arg.Get("cosmosSearch") != nil {
+			return spec, nil
+		}
+
+		if stages, err = textSearchStages(arg); err != nil {
+			return nil, err
+		}
+
+		meta = "searchScore"
+		score = must.NotFail(wirebson.NewDocument("$meta", "textScore"))
+	}
+
+	newPipeline := wirebson.MakeArray(pipeline.Len() + len(stages) - 1)
+
+	for _, s := range stages {
+		must.NoError(newPipeline.Add(s))
+	}
+
+	for i := 1; i < pipeline.Len(); i++ {
+		var v any
+		if v, err = replaceMeta(pipeline.Get(i), meta, score); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		must.NoError(newPipeline.Add(v))
+	}
+
+	return replacePipeline(doc, aggregate, newPipeline)
+}
+
+// vectorSearchIndex returns the `vector` field definition of the vector search index
+// specified by `$vectorSearch` stage.
+// It returns nil if the index does not exist.
+func (h *Handler) vectorSearchIndex(ctx context.Context, dbName, collection string, arg *wirebson.Document) (*wirebson.Document, error) {
+	name, ok := arg.Get("index").(string)
+	if !ok {
+		msg := "$vectorSearch \"index\" field must be a string"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, "aggregate")
+	}
+
+	// look up only the named index to avoid listing all indexes and checking their builds on every query
+	raw, err := h.Pool.IndexSpec(ctx, dbName, collection, name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if raw == nil {
+		return nil, nil
+	}
+
+	spec, err := raw.DecodeDeep()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	typ, def, ok := searchIndexDefinition(spec)
+	if !ok || typ != searchIndexTypeVector {
+		return nil, nil
+	}
+
+	return def.Get("fields").(*wirebson.Array).Get(0).(*wirebson.Document), nil
+}
+
+// vectorSearchStage returns DocumentDB `$search` stage for the given `$vectorSearch` stage
+// and the `vector` field definition of its index.
+//
+// `numCandidates` is used as `efSearch` parameter of HNSW indexes.
+func vectorSearchStage(arg, index *wirebson.Document) (*wirebson.Document, error) {
+	path, ok := arg.Get("path").(string)
+	if !ok {
+		msg := "$vectorSearch \"path\" field must be a string"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, "aggregate")
+	}
+
+	if path != index.Get("path") {
+		msg := fmt.Sprintf("Path '%s' needs to be indexed as vector", path)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, "aggregate")
+	}
+
+	vector, ok := arg.Get("queryVector").(wirebson.AnyArray)
+	if !ok {
+		msg := "$vectorSearch \"queryVector\" field must be an array"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, "aggregate")
+	}
+
+	limit, err := getWholeNumberParam(arg.Get("limit"))
+	if err != nil || limit <= 0 {
+		msg := fmt.Sprintf("$vectorSearch \"limit\" field must be a positive number, got %v", arg.Get("limit"))
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, "aggregate")
+	}
+
+	if exact, _ := arg.Get("exact").(bool); exact {
+		msg := "$vectorSearch exact nearest neighbors search is not supported"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrNotImplemented, msg, "aggregate")
+	}
+
+	candidates, err := getWholeNumberParam(arg.Get("numCandidates"))
+	if err != nil || candidates < limit || candidates > vectorSearchMaxCandidates {
+		msg := fmt.Sprintf(
+			"$vectorSearch \"numCandidates\" field must be a number between \"limit\" and %d, got %v",
+			vectorSearchMaxCandidates, arg.Get("numCandidates"),
+		)
+
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, "aggregate")
+	}
+
+	search := must.NotFail(wirebson.NewDocument(
+		"vector", vector,
+		"path", path,
+		"k", int32(limit),
+	))
+
+	if index.Get("kind") != "vector-ivf" {
+		must.NoError(search.Add("efSearch", int32(min(candidates, vectorSearchMaxEfSearch))))
+	}
+
+	if filter := arg.Get("filter"); filter != nil {
+		if _, ok = filter.(wirebson.AnyDocument); !ok {
+			msg := "$vectorSearch \"filter\" field must be an object"
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, "aggregate")
+		}
+
+		must.NoError(search.Add("filter", filter))
+	}
+
+	return must.NotFail(wirebson.NewDocument(
+		"$search", must.NotFail(wirebson.NewDocument(
+			"cosmosSearch", search,
+			"returnStoredSource", true,
+		)),
+	)), nil
+}
+
+// vectorSearchScore returns an expression that converts DocumentDB search score
+// (cosine similarity, inner product, or Euclidean distance) to Atlas Vector Search score
+// for the given Atlas similarity function.
+func vectorSearchScore(similarity any) *wirebson.Document {
+	score := must.NotFail(wirebson.NewDocument("$meta", "searchScore"))
+	plusOne := must.NotFail(wirebson.NewDocument("$add", wirebson.MustArray(float64(1), score)))
+
+	switch similarity {
+	case "euclidean":
+		// 1 / (1 + distance)
+		return must.NotFail(wirebson.NewDocument("$divide", wirebson.MustArray(float64(1), plusOne)))
+	default:
+		// (1 + score) / 2
+		return must.NotFail(wirebson.NewDocument("$divide", wirebson.MustArray(plusOne, float64(2))))
+	}
+}
+
+// textSearchStages returns DocumentDB stages for the given `$search` stage with `text` operator.
+//
+// `path` field is accepted but ignored, because text indexes define the searched fields.
+func textSearchStages(arg *wirebson.Document) ([]*wirebson.Document, error) {
+	var text *wirebson.Document
+
+	for k, v := range arg.All() {
+		switch k {
+		case "index":
+			// there could be only one text index per collection
+
+		case "text":
+			textV, ok := v.(wirebson.AnyDocument)
+			if !ok {
+				msg := "$search \"text\" operator must be an object"
+				return nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, "aggregate")
+			}
+
+			var err error
+			if text, err = textV.Decode(); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+		default:
+			msg := fmt.Sprintf("$search field %q is not supported", k)
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrNotImplemented, msg, "aggregate")
+		}
+	}
+
+	if text == nil {
+		msg := "$search stage must contain \"text\" operator"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, "aggregate")
+	}
+
+	var query string
+
+	for k, v := range text.All() {
+		switch k {
+		case "path":
+			// text index fields are searched
+		case "query":
+			var err error
+			if query, err = textSearchQuery(v); err != nil {
+				return nil, err
+			}
+
+		default:
+			msg := fmt.Sprintf("$search \"text\" operator field %q is not supported", k)
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrNotImplemented, msg, "aggregate")
+		}
+	}
+
+	if query == "" {
+		msg := "$search \"text\" operator must contain non-empty \"query\""
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, "aggregate")
+	}
+
+	return []*wirebson.Document{
+		must.NotFail(wirebson.NewDocument(
+			"$match", must.NotFail(wirebson.NewDocument(
+				"$text", must.NotFail(wirebson.NewDocument("$search", query)),
+			)),
+		)),
+		must.NotFail(wirebson.NewDocument(
+			"$sort", must.NotFail(wirebson.NewDocument(
+				"score", must.NotFail(wirebson.NewDocument("$meta", "textScore")),
+			)),
+		)),
+	}, nil
+}
+
+// textSearchQuery returns `$text` search string for the given `text` operator query,
+// that could be a string or an array of strings matched with OR semantics.
+func textSearchQuery(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+
+	case wirebson.AnyArray:
+		arr, err := v.Decode()
+		if err != nil {
+			return "", lazyerrors.Error(err)
+		}
+
+		terms := make([]string, 0, arr.Len())
+
+		for e := range arr.Values() {
+			s, ok := e.(string)
+			if !ok {
+				msg := fmt.Sprintf("$search \"text\" operator query has element of type %s (expected string)", aliasFromType(e))
+				return "", mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, "aggregate")
+			}
+
+			terms = append(terms, s)
+		}
+
+		return strings.Join(terms, " "), nil
+
+	default:
+		msg := fmt.Sprintf("$search \"text\" operator query has type %s (expected string or array)", aliasFromType(v))
+		return "", mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, "aggregate")
+	}
+}
+
+// replaceMeta returns the given value with all `{$meta: <meta>}` expressions replaced by the given expression.
+func replaceMeta(v any, meta string, expr any) (any, error) {
+	switch v := v.(type) {
+	case wirebson.AnyDocument:
+		doc, err := v.Decode()
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if doc.Len() == 1 && doc.Get("$meta") == meta {
+			return expr, nil
+		}
+
+		res := wirebson.MakeDocument(doc.Len())
+
+		for k, f := range doc.All() {
+			if f, err = replaceMeta(f, meta, expr); err != nil {
+				return nil, err
+			}
+
+			must.NoError(res.Add(k, f))
+		}
+
+		return res, nil
+
+	case wirebson.AnyArray:
+		arr, err := v.Decode()
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res := wirebson.MakeArray(arr.Len())
+
+		for e := range arr.Values() {
+			if e, err = replaceMeta(e, meta, expr); err != nil {
+				return nil, err
+			}
+
+			must.NoError(res.Add(e))
+		}
+
+		return res, nil
+
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,538 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// Atlas Search index types.
+const (
+	searchIndexTypeSearch = "search"
+	searchIndexTypeVector = "vectorSearch"
+)
+
+// searchIndexDefaultName is the name of the search index if it is not specified.
+const searchIndexDefaultName = "default"
+
+// Atlas Search indexes are translated to DocumentDB indexes:
+//   - `vectorSearch` indexes with a single `vector` field to vector indexes (`cosmosSearch` key);
+//   - `search` indexes with `string` fields or dynamic mappings to text indexes.
+//
+// All vector and text indexes are reported as search indexes, including ones created by `createIndexes`.
+
+// vectorSimilarities maps Atlas Vector Search similarity functions to DocumentDB ones.
+var vectorSimilarities = map[string]string{
+	"cosine":     "COS",
+	"dotProduct": "IP",
+	"euclidean":  "L2",
+}
+
+// vectorKinds contains supported DocumentDB vector index kinds; the first one is the default.
+var vectorKinds = []string{"vector-hnsw", "vector-ivf"}
+
+// vectorKindOptions contains DocumentDB vector index options that could be set in the `vector` field definition.
+var vectorKindOptions = []string{"m", "efConstruction", "numLists"}
+
+// searchLanguages contains languages of `lucene.<language>` analyzers that are supported by text indexes.
+var searchLanguages = []string{
+	"danish", "dutch", "english", "finnish", "french", "german", "hungarian", "italian",
+	"norwegian", "portuguese", "romanian", "russian", "spanish", "swedish", "turkish",
+}
+
+// searchIndexSpec returns DocumentDB index specification for `createIndexes`
+// for the given Atlas Search index type and definition.
+func searchIndexSpec(command, name, typ string, def *wirebson.Document) (*wirebson.Document, error) {
+	switch typ {
+	case searchIndexTypeVector:
+		return vectorIndexSpec(command, name, def)
+	case searchIndexTypeSearch:
+		return textIndexSpec(command, name, def)
+	default:
+		msg := fmt.Sprintf("Invalid search index type '%s', expected '%s' or '%s'", typ, searchIndexTypeSearch, searchIndexTypeVector)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+	}
+}
+
+// vectorIndexSpec returns DocumentDB vector index specification for the given `vectorSearch` index definition.
+//
+// `filter` fields are accepted but ignored, because filters are applied using regular indexes.
+func vectorIndexSpec(command, name string, def *wirebson.Document) (*wirebson.Document, error) {
+	fieldsV, ok := def.Get("fields").(wirebson.AnyArray)
+	if !ok {
+		msg := "Vector search index definition must contain \"fields\" array"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+	}
+
+	fields, err := fieldsV.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var res *wirebson.Document
+
+	for v := range fields.Values() {
+		fieldV, ok := v.(wirebson.AnyDocument)
+		if !ok {
+			msg := fmt.Sprintf("Vector search index field has type %s (expected object)", aliasFromType(v))
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, command)
+		}
+
+		var field *wirebson.Document
+		if field, err = fieldV.Decode(); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch t, _ := field.Get("type").(string); t {
+		case "filter":
+			continue
+
+		case "vector":
+			if res != nil {
+				msg := "Vector search index definition with more than one vector field is not supported"
+				return nil, mongoerrors.NewWithArgument(mongoerrors.ErrNotImplemented, msg, command)
+			}
+
+			if res, err = vectorFieldIndexSpec(command, name, field); err != nil {
+				return nil, err
+			}
+
+		default:
+			msg := fmt.Sprintf("Invalid vector search index field type '%s', expected 'vector' or 'filter'", t)
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+		}
+	}
+
+	if res == nil {
+		msg := "Vector search index definition must contain a vector field"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+	}
+
+	return res, nil
+}
+
+// vectorFieldIndexSpec returns DocumentDB vector index specification for the given `vector` field definition.
+//
+// In addition to Atlas fields, `kind` field selects DocumentDB vector index kind,
+// and `m`, `efConstruction`, and `numLists` fields set its options.
+func vectorFieldIndexSpec(command, name string, field *wirebson.Document) (*wirebson.Document, error) {
+	path, ok := field.Get("path").(string)
+	if !ok || path == "" {
+		msg := "Vector search index field must contain non-empty \"path\" string"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+	}
+
+	dimensions, err := getWholeNumberParam(field.Get("numDimensions"))
+	if err != nil || dimensions <= 0 {
+		msg := fmt.Sprintf("Vector search index field \"numDimensions\" must be a positive number, got %v", field.Get("numDimensions"))
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+	}
+
+	similarity, _ := field.Get("similarity").(string)
+
+	sim, ok := vectorSimilarities[similarity]
+	if !ok {
+		msg := fmt.Sprintf(
+			"Vector search index field \"similarity\" must be one of %s, got %v",
+			strings.Join(slices.Sorted(maps.Keys(vectorSimilarities)), ", "), field.Get("similarity"),
+		)
+
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+	}
+
+	kind := vectorKinds[0]
+
+	if v := field.Get("kind"); v != nil {
+		if kind, _ = v.(string); !slices.Contains(vectorKinds, kind) {
+			msg := fmt.Sprintf("Vector search index field \"kind\" must be one of %s, got %v", strings.Join(vectorKinds, ", "), v)
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+		}
+	}
+
+	opts := must.NotFail(wirebson.NewDocument(
+		"kind", kind,
+		"similarity", sim,
+		"dimensions", int32(dimensions),
+	))
+
+	for _, k := range vectorKindOptions {
+		v := field.Get(k)
+		if v == nil {
+			continue
+		}
+
+		var n int64
+		if n, err = getWholeNumberParam(v); err != nil {
+			msg := fmt.Sprintf("Vector search index field %q must be a number, got %v", k, v)
+			return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+		}
+
+		must.NoError(opts.Add(k, int32(n)))
+	}
+
+	return must.NotFail(wirebson.NewDocument(
+		"name", name,
+		"key", must.NotFail(wirebson.NewDocument(path, "cosmosSearch")),
+		"cosmosSearchOptions", opts,
+	)), nil
+}
+
+// textIndexSpec returns DocumentDB text index specification for the given `search` index definition.
+//
+// Dynamic mappings index all string fields; otherwise, `string` and `autocomplete` fields are indexed,
+// including fields of `document` fields.
+// Other field types are accepted but ignored.
+func textIndexSpec(command, name string, def *wirebson.Document) (*wirebson.Document, error) {
+	mappingsV, ok := def.Get("mappings").(wirebson.AnyDocument)
+	if !ok {
+		msg := "Search index definition must contain \"mappings\" object"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+	}
+
+	mappings, err := mappingsV.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	key := wirebson.MakeDocument(1)
+
+	dynamic, _ := mappings.Get("dynamic").(bool)
+	if dynamic {
+		must.NoError(key.Add("$**", "text"))
+	} else if err = addTextIndexFields(command, key, "", mappings); err != nil {
+		return nil, err
+	}
+
+	if key.Len() == 0 {
+		msg := "Search index definition must contain dynamic mappings or at least one string field"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrBadValue, msg, command)
+	}
+
+	res := must.NotFail(wirebson.NewDocument(
+		"name", name,
+		"key", key,
+	))
+
+	if v := def.Get("analyzer"); v != nil {
+		analyzer, _ := v.(string)
+
+		var lang string
+
+		switch analyzer {
+		case "lucene.standard", "lucene.simple", "lucene.whitespace":
+			lang = "none"
+		default:
+			if lang = strings.TrimPrefix(analyzer, "lucene."); !slices.Contains(searchLanguages, lang) {
+				msg := fmt.Sprintf("Search index analyzer %v is not supported", v)
+				return nil, mongoerrors.NewWithArgument(mongoerrors.ErrNotImplemented, msg, command)
+			}
+		}
+
+		must.NoError(res.Add("default_language", lang))
+	}
+
+	return res, nil
+}
+
+// addTextIndexFields adds text index keys for string fields of the given mappings (or `document` field)
+// to the key document.
+func addTextIndexFields(command string, key *wirebson.Document, prefix string, mappings *wirebson.Document) error {
+	fieldsV, _ := mappings.Get("fields").(wirebson.AnyDocument)
+	if fieldsV == nil {
+		return nil
+	}
+
+	fields, err := fieldsV.Decode()
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	for path, v := range fields.All() {
+		// a field could have several type definitions
+		var defs []any
+
+		switch v := v.(type) {
+		case wirebson.AnyArray:
+			arr, err := v.Decode()
+			if err != nil {
+				return lazyerrors.Error(err)
+			}
+
+			defs = slices.Collect(arr.Values())
+		default:
+			defs = []any{v}
+		}
+
+		for _, defV := range defs {
+			fieldV, ok := defV.(wirebson.AnyDocument)
+			if !ok {
+				msg := fmt.Sprintf("Search index field %q has type %s (expected object)", prefix+path, aliasFromType(defV))
+				return mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, command)
+			}
+
+			field, err := fieldV.Decode()
+			if err != nil {
+				return lazyerrors.Error(err)
+			}
+
+			switch t, _ := field.Get("type").(string); t {
+			case "string", "autocomplete":
+				if key.Get(prefix+path) == nil {
+					must.NoError(key.Add(prefix+path, "text"))
+				}
+
+			case "document", "embeddedDocuments":
+				if dynamic, _ := field.Get("dynamic").(bool); dynamic {
+					msg := fmt.Sprintf("Dynamic mappings of search index field %q are not supported", prefix+path)
+					return mongoerrors.NewWithArgument(mongoerrors.ErrNotImplemented, msg, command)
+				}
+
+				if err = addTextIndexFields(command, key, prefix+path+".", field); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// searchIndexDefinition returns Atlas Search index type and definition
+// for the given DocumentDB index specification returned by `listIndexes`.
+// It returns false if the index is not a vector or text index.
+func searchIndexDefinition(spec *wirebson.Document) (string, *wirebson.Document, bool) {
+	if opts, _ := spec.Get("cosmosSearchOptions").(*wirebson.Document); opts != nil {
+		key, _ := spec.Get("key").(*wirebson.Document)
+		if key == nil || key.Len() == 0 {
+			return "", nil, false
+		}
+
+		path, _ := key.GetByIndex(0)
+
+		field := must.NotFail(wirebson.NewDocument(
+			"type", "vector",
+			"path", path,
+			"numDimensions", opts.Get("dimensions"),
+		))
+
+		sim, _ := opts.Get("similarity").(string)
+		for k, v := range vectorSimilarities {
+			if v == sim {
+				must.NoError(field.Add("similarity", k))
+			}
+		}
+
+		for _, k := range append([]string{"kind"}, vectorKindOptions...) {
+			if v := opts.Get(k); v != nil {
+				must.NoError(field.Add(k, v))
+			}
+		}
+
+		return searchIndexTypeVector, must.NotFail(wirebson.NewDocument("fields", wirebson.MustArray(field))), true
+	}
+
+	weights, _ := spec.Get("weights").(*wirebson.Document)
+	if weights == nil {
+		return "", nil, false
+	}
+
+	var dynamic bool
+	fields := wirebson.MakeDocument(weights.Len())
+
+	for path := range weights.All() {
+		if path == "$**" {
+			dynamic = true
+			continue
+		}
+
+		must.NoError(fields.Add(path, must.NotFail(wirebson.NewDocument("type", "string"))))
+	}
+
+	def := must.NotFail(wirebson.NewDocument(
+		"mappings", must.NotFail(wirebson.NewDocument(
+			"dynamic", dynamic,
+			"fields", fields,
+		)),
+	))
+
+	if lang, _ := spec.Get("default_language").(string); lang != "" {
+		analyzer := "lucene." + lang
+		if lang == "none" {
+			analyzer = "lucene.standard"
+		}
+
+		must.NoError(def.Add("analyzer", analyzer))
+	}
+
+	return searchIndexTypeSearch, def, true
+}
+
+// searchIndexes returns `$listSearchIndexes` documents for the given collection.
+func (h *Handler) searchIndexes(ctx context.Context, dbName, collection string) ([]*wirebson.Document, error) {
+	listSpec := must.NotFail(wirebson.MustDocument(
+		"listIndexes", collection,
+		// use large batchSize to get all results in one batch
+		"cursor", wirebson.MustDocument("batchSize", int32(10000)),
+	).Encode())
+
+	listRes, cursorID, err := h.Pool.ListIndexes(ctx, dbName, listSpec)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if cursorID != 0 {
+		_ = h.Pool.KillCursor(ctx, cursorID)
+
+		return nil, lazyerrors.New("too many indexes for listing search indexes")
+	}
+
+	listDoc, err := listRes.DecodeDeep()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	cursor, _ := listDoc.Get("cursor").(*wirebson.Document)
+	if cursor == nil {
+		return nil, lazyerrors.Errorf("unexpected listIndexes response %v", listDoc)
+	}
+
+	batch, _ := cursor.Get("firstBatch").(*wirebson.Array)
+	if batch == nil {
+		return nil, lazyerrors.Errorf("unexpected listIndexes response %v", listDoc)
+	}
+
+	builds, err := h.Pool.IndexBuilds(ctx, dbName, collection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var res []*wirebson.Document
+
+	for v := range batch.Values() {
+		spec, ok := v.(*wirebson.Document)
+		if !ok {
+			continue
+		}
+
+		typ, def, ok := searchIndexDefinition(spec)
+		if !ok {
+			continue
+		}
+
+		name, _ := spec.Get("name").(string)
+		build := builds[name]
+
+		status, queryable := "READY", true
+
+		switch {
+		case build.Failed:
+			status, queryable = "FAILED", false
+		case !build.Complete:
+			status, queryable = "BUILDING", false
+		}
+
+		res = append(res, must.NotFail(wirebson.NewDocument(
+			"id", strconv.FormatInt(build.ID, 10),
+			"name", name,
+			"type", typ,
+			"status", status,
+			"queryable", queryable,
+			"latestDefinition", def,
+		)))
+	}
+
+	return res, nil
+}
+
+// findSearchIndex returns `$listSearchIndexes` document of the search index
+// specified by `name` or `id` field of the command.
+func (h *Handler) findSearchIndex(ctx context.Context, command, dbName, collection string, doc *wirebson.Document) (*wirebson.Document, error) {
+	name, _ := doc.Get("name").(string)
+	id, _ := doc.Get("id").(string)
+
+	if (name == "") == (id == "") {
+		msg := fmt.Sprintf("Either 'name' or 'id' string field must be specified for %s", command)
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrInvalidOptions, msg, command)
+	}
+
+	indexes, err := h.searchIndexes(ctx, dbName, collection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	for _, index := range indexes {
+		if (name != "" && index.Get("name") == name) || (id != "" && index.Get("id") == id) {
+			return index, nil
+		}
+	}
+
+	msg := fmt.Sprintf("Search index %s not found", name+id)
+
+	return nil, mongoerrors.NewWithArgument(mongoerrors.ErrIndexNotFound, msg, command)
+}
+
+// rewriteListSearchIndexes replaces the leading `$listSearchIndexes` stage of the aggregation pipeline
+// with `$documents` stage containing search indexes, so the rest of the pipeline
+// is executed by DocumentDB as usual.
+//
+// If the pipeline does not start with `$listSearchIndexes`, the spec is returned as is.
+func (h *Handler) rewriteListSearchIndexes(ctx context.Context, dbName string, doc *wirebson.Document, spec wirebson.RawDocument) (wirebson.RawDocument, error) {
+	collection, pipeline, stage := firstStage(doc)
+	if stage == nil || stage.Command() != "$listSearchIndexes" {
+		return spec, nil
+	}
+
+	argV, _ := stage.Get("$listSearchIndexes").(wirebson.AnyDocument)
+	if argV == nil {
+		msg := "$listSearchIndexes stage specification must be an object"
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrFailedToParse, msg, "aggregate")
+	}
+
+	arg, err := argV.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	name, _ := arg.Get("name").(string)
+	id, _ := arg.Get("id").(string)
+
+	indexes, err := h.searchIndexes(ctx, dbName, collection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	docs := wirebson.MakeArray(len(indexes))
+
+	for _, index := range indexes {
+		if (name != "" && index.Get("name") != name) || (id != "" && index.Get("id") != id) {
+			continue
+		}
+
+		must.NoError(docs.Add(index))
+	}
+
+	return replaceFirstStage(doc, pipeline, docs)
+}
@@ -0,0 +1,270 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestSearchIndexSpec(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		typ string
+		def *wirebson.Document
+
+		expected *wirebson.Document
+		code     mongoerrors.Code
+	}{
+		"Vector": {
+			typ: searchIndexTypeVector,
+			def: wirebson.MustDocument("fields", wirebson.MustArray(
+				wirebson.MustDocument("type", "vector", "path", "v", "numDimensions", int32(3), "similarity", "cosine"),
+				wirebson.MustDocument("type", "filter", "path", "genre"),
+			)),
+			expected: wirebson.MustDocument(
+				"name", "default",
+				"key", wirebson.MustDocument("v", "cosmosSearch"),
+				"cosmosSearchOptions", wirebson.MustDocument(
+					"kind", "vector-hnsw",
+					"similarity", "COS",
+					"dimensions", int32(3),
+				),
+			),
+		},
+		"VectorIVF": {
+			typ: searchIndexTypeVector,
+			def: wirebson.MustDocument("fields", wirebson.MustArray(
+				wirebson.MustDocument(
+					"type", "vector", "path", "v", "numDimensions", float64(2), "similarity", "euclidean",
+					"kind", "vector-ivf", "numLists", int32(10),
+				),
+			)),
+			expected: wirebson.MustDocument(
+				"name", "default",
+				"key", wirebson.MustDocument("v", "cosmosSearch"),
+				"cosmosSearchOptions", wirebson.MustDocument(
+					"kind", "vector-ivf",
+					"similarity", "L2",
+					"dimensions", int32(2),
+					"numLists", int32(10),
+				),
+			),
+		},
+		"VectorNoVectorField": {
+			typ: searchIndexTypeVector,
+			def: wirebson.MustDocument("fields", wirebson.MustArray(
+				wirebson.MustDocument("type", "filter", "path", "genre"),
+			)),
+			code: mongoerrors.ErrBadValue,
+		},
+		"VectorTwoVectorFields": {
+			typ: searchIndexTypeVector,
+			def: wirebson.MustDocument("fields", wirebson.MustArray(
+				wirebson.MustDocument("type", "vector", "path", "a", "numDimensions", int32(3), "similarity", "cosine"),
+				wirebson.MustDocument("type", "vector", "path", "b", "numDimensions", int32(3), "similarity", "cosine"),
+			)),
+			code: mongoerrors.ErrNotImplemented,
+		},
+		"VectorInvalidSimilarity": {
+			typ: searchIndexTypeVector,
+			def: wirebson.MustDocument("fields", wirebson.MustArray(
+				wirebson.MustDocument("type", "vector", "path", "v", "numDimensions", int32(3), "similarity", "manhattan"),
+			)),
+			code: mongoerrors.ErrBadValue,
+		},
+		"VectorInvalidDimensions": {
+			typ: searchIndexTypeVector,
+			def: wirebson.MustDocument("fields", wirebson.MustArray(
+				wirebson.MustDocument("type", "vector", "path", "v", "numDimensions", float64(1.5), "similarity", "cosine"),
+			)),
+			code: mongoerrors.ErrBadValue,
+		},
+		"Dynamic": {
+			typ: searchIndexTypeSearch,
+			def: wirebson.MustDocument("mappings", wirebson.MustDocument("dynamic", true)),
+			expected: wirebson.MustDocument(
+				"name", "default",
+				"key", wirebson.MustDocument("$**", "text"),
+			),
+		},
+		"Fields": {
+			typ: searchIndexTypeSearch,
+			def: wirebson.MustDocument(
+				"mappings", wirebson.MustDocument(
+					"dynamic", false,
+					"fields", wirebson.MustDocument(
+						"title", wirebson.MustDocument("type", "string"),
+						"year", wirebson.MustDocument("type", "number"),
+						"plot", wirebson.MustArray(
+							wirebson.MustDocument("type", "string"),
+							wirebson.MustDocument("type", "autocomplete"),
+						),
+						"awards", wirebson.MustDocument(
+							"type", "document",
+							"fields", wirebson.MustDocument("text", wirebson.MustDocument("type", "string")),
+						),
+					),
+				),
+				"analyzer", "lucene.french",
+			),
+			expected: wirebson.MustDocument(
+				"name", "default",
+				"key", wirebson.MustDocument("title", "text", "plot", "text", "awards.text", "text"),
+				"default_language", "french",
+			),
+		},
+		"NoStringFields": {
+			typ: searchIndexTypeSearch,
+			def: wirebson.MustDocument(
+				"mappings", wirebson.MustDocument(
+					"fields", wirebson.MustDocument("year", wirebson.MustDocument("type", "number")),
+				),
+			),
+			code: mongoerrors.ErrBadValue,
+		},
+		"UnsupportedAnalyzer": {
+			typ: searchIndexTypeSearch,
+			def: wirebson.MustDocument(
+				"mappings", wirebson.MustDocument("dynamic", true),
+				"analyzer", "lucene.klingon",
+			),
+			code: mongoerrors.ErrNotImplemented,
+		},
+		"NoMappings": {
+			typ:  searchIndexTypeSearch,
+			def:  wirebson.MustDocument(),
+			code: mongoerrors.ErrBadValue,
+		},
+		"InvalidType": {
+			typ:  "invalid",
+			def:  wirebson.MustDocument(),
+			code: mongoerrors.ErrBadValue,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			spec, err := searchIndexSpec("createSearchIndexes", "default", tc.typ, tc.def)
+
+			if tc.code != 0 {
+				var e *mongoerrors.Error
+				require.ErrorAs(t, err, &e)
+				assert.Equal(t, tc.code, mongoerrors.Code(e.Code))
+
+				return
+			}
+
+			require.NoError(t, err)
+			testutil.AssertEqual(t, tc.expected, spec)
+		})
+	}
+}
+
+func TestSearchIndexDefinition(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		spec *wirebson.Document
+
+		typ string
+		def *wirebson.Document // nil if not a search index
+	}{
+		"Regular": {
+			spec: wirebson.MustDocument("v", int32(2), "key", wirebson.MustDocument("a", int32(1)), "name", "a_1"),
+		},
+		"Vector": {
+			spec: wirebson.MustDocument(
+				"v", int32(2),
+				"key", wirebson.MustDocument("v", "cosmosSearch"),
+				"name", "vector",
+				"cosmosSearchOptions", wirebson.MustDocument(
+					"kind", "vector-hnsw",
+					"similarity", "IP",
+					"dimensions", int32(3),
+					"m", int32(16),
+				),
+			),
+			typ: searchIndexTypeVector,
+			def: wirebson.MustDocument("fields", wirebson.MustArray(wirebson.MustDocument(
+				"type", "vector",
+				"path", "v",
+				"numDimensions", int32(3),
+				"similarity", "dotProduct",
+				"kind", "vector-hnsw",
+				"m", int32(16),
+			))),
+		},
+		"Text": {
+			spec: wirebson.MustDocument(
+				"v", int32(2),
+				"key", wirebson.MustDocument("_fts", "text", "_ftsx", int32(1)),
+				"name", "default",
+				"weights", wirebson.MustDocument("plot", int32(1), "title", int32(1)),
+				"default_language", "none",
+				"language_override", "language",
+				"textIndexVersion", int32(2),
+			),
+			typ: searchIndexTypeSearch,
+			def: wirebson.MustDocument(
+				"mappings", wirebson.MustDocument(
+					"dynamic", false,
+					"fields", wirebson.MustDocument(
+						"plot", wirebson.MustDocument("type", "string"),
+						"title", wirebson.MustDocument("type", "string"),
+					),
+				),
+				"analyzer", "lucene.standard",
+			),
+		},
+		"TextDynamic": {
+			spec: wirebson.MustDocument(
+				"v", int32(2),
+				"key", wirebson.MustDocument("_fts", "text", "_ftsx", int32(1)),
+				"name", "default",
+				"weights", wirebson.MustDocument("$**", int32(1)),
+			),
+			typ: searchIndexTypeSearch,
+			def: wirebson.MustDocument(
+				"mappings", wirebson.MustDocument(
+					"dynamic", true,
+					"fields", wirebson.MustDocument(),
+				),
+			),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			typ, def, ok := searchIndexDefinition(tc.spec)
+
+			if tc.def == nil {
+				assert.False(t, ok)
+				return
+			}
+
+			require.True(t, ok)
+			assert.Equal(t, tc.typ, typ)
+			testutil.AssertEqual(t, tc.def, def)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestVectorSearchStage(t *testing.T) {
+	t.Parallel()
+
+	hnsw := wirebson.MustDocument("type", "vector", "path", "v", "similarity", "cosine", "kind", "vector-hnsw")
+	ivf := wirebson.MustDocument("type", "vector", "path", "v", "similarity", "euclidean", "kind", "vector-ivf")
+
+	for name, tc := range map[string]struct {
+		arg   *wirebson.Document
+		index *wirebson.Document
+
+		expected *wirebson.Document
+		code     mongoerrors.Code
+	}{
+		"HNSW": {
+			arg: wirebson.MustDocument(
+				"index", "vector",
+				"path", "v",
+				"queryVector", wirebson.MustArray(0.1, 0.2),
+				"numCandidates", int32(2000),
+				"limit", int32(5),
+				"filter", wirebson.MustDocument("genre", "drama"),
+			),
+			index: hnsw,
+			expected: wirebson.MustDocument("$search", wirebson.MustDocument(
+				"cosmosSearch", wirebson.MustDocument(
+					"vector", wirebson.MustArray(0.1, 0.2),
+					"path", "v",
+					"k", int32(5),
+					"efSearch", int32(1000),
+					"filter", wirebson.MustDocument("genre", "drama"),
+				),
+				"returnStoredSource", true,
+			)),
+		},
+		"IVF": {
+			arg: wirebson.MustDocument(
+				"index", "vector",
+				"path", "v",
+				"queryVector", wirebson.MustArray(0.1, 0.2),
+				"numCandidates", float64(10),
+				"limit", int64(2),
+			),
+			index: ivf,
+			expected: wirebson.MustDocument("$search", wirebson.MustDocument(
+				"cosmosSearch", wirebson.MustDocument(
+					"vector", wirebson.MustArray(0.1, 0.2),
+					"path", "v",
+					"k", int32(2),
+				),
+				"returnStoredSource", true,
+			)),
+		},
+		"PathNotIndexed": {
+			arg: wirebson.MustDocument(
+				"path", "other",
+				"queryVector", wirebson.MustArray(0.1, 0.2),
+				"numCandidates", int32(10),
+				"limit", int32(2),
+			),
+			index: hnsw,
+			code:  mongoerrors.ErrBadValue,
+		},
+		"NoQueryVector": {
+			arg: wirebson.MustDocument(
+				"path", "v",
+				"numCandidates", int32(10),
+				"limit", int32(2),
+			),
+			index: hnsw,
+			code:  mongoerrors.ErrFailedToParse,
+		},
+		"CandidatesLessThanLimit": {
+			arg: wirebson.MustDocument(
+				"path", "v",
+				"queryVector", wirebson.MustArray(0.1, 0.2),
+				"numCandidates", int32(1),
+				"limit", int32(2),
+			),
+			index: hnsw,
+			code:  mongoerrors.ErrFailedToParse,
+		},
+		"Exact": {
+			arg: wirebson.MustDocument(
+				"path", "v",
+				"queryVector", wirebson.MustArray(0.1, 0.2),
+				"limit", int32(2),
+				"exact", true,
+			),
+			index: hnsw,
+			code:  mongoerrors.ErrNotImplemented,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := vectorSearchStage(tc.arg, tc.index)
+
+			if tc.code != 0 {
+				var e *mongoerrors.Error
+				require.ErrorAs(t, err, &e)
+				assert.Equal(t, tc.code, mongoerrors.Code(e.Code))
+
+				return
+			}
+
+			require.NoError(t, err)
+			testutil.AssertEqual(t, tc.expected, res)
+		})
+	}
+}
+
+func TestTextSearchStages(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		arg *wirebson.Document
+
+		expectedQuery string
+		code          mongoerrors.Code
+	}{
+		"String": {
+			arg:           wirebson.MustDocument("index", "default", "text", wirebson.MustDocument("query", "whale", "path", "summary")),
+			expectedQuery: "whale",
+		},
+		"Array": {
+			arg:           wirebson.MustDocument("text", wirebson.MustDocument("query", wirebson.MustArray("hunt", "whales"))),
+			expectedQuery: "hunt whales",
+		},
+		"NoText": {
+			arg:  wirebson.MustDocument("index", "default"),
+			code: mongoerrors.ErrFailedToParse,
+		},
+		"Phrase": {
+			arg:  wirebson.MustDocument("phrase", wirebson.MustDocument("query", "whale", "path", "summary")),
+			code: mongoerrors.ErrNotImplemented,
+		},
+		"Fuzzy": {
+			arg:  wirebson.MustDocument("text", wirebson.MustDocument("query", "whale", "fuzzy", wirebson.MustDocument())),
+			code: mongoerrors.ErrNotImplemented,
+		},
+		"QueryType": {
+			arg:  wirebson.MustDocument("text", wirebson.MustDocument("query", int32(42))),
+			code: mongoerrors.ErrTypeMismatch,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := textSearchStages(tc.arg)
+
+			if tc.code != 0 {
+				var e *mongoerrors.Error
+				require.ErrorAs(t, err, &e)
+				assert.Equal(t, tc.code, mongoerrors.Code(e.Code))
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, res, 2)
+
+			expected := wirebson.MustDocument("$match", wirebson.MustDocument(
+				"$text", wirebson.MustDocument("$search", tc.expectedQuery),
+			))
+			testutil.AssertEqual(t, expected, res[0])
+			assert.Equal(t, "$sort", res[1].Command())
+		})
+	}
+}
+
+func TestReplaceMeta(t *testing.T) {
+	t.Parallel()
+
+	stage := wirebson.MustDocument("$project", wirebson.MustDocument(
+		"title", int32(1),
+		"score", wirebson.MustDocument("$meta", "vectorSearchScore"),
+		"scores", wirebson.MustArray(wirebson.MustDocument("$meta", "vectorSearchScore"), "other"),
+		"other", wirebson.MustDocument("$meta", "searchHighlights"),
+	))
+
+	raw := must.NotFail(stage.Encode())
+
+	res, err := replaceMeta(raw, "vectorSearchScore", "replaced")
+	require.NoError(t, err)
+
+	expected := wirebson.MustDocument("$project", wirebson.MustDocument(
+		"title", int32(1),
+		"score", "replaced",
+		"scores", wirebson.MustArray("replaced", "other"),
+		"other", wirebson.MustDocument("$meta", "searchHighlights"),
+	))
+	testutil.AssertEqual(t, expected, res.(*wirebson.Document))
+}
@@ -181,6 +181,56 @@ func (r *Registry) CreateOrUpdateByLSID(ctx context.Context, spec wirebson.AnyDo
 	return userID, sessionID, nil
 }
 
+// AdvanceOperationTime fetches `lsid` field from spec and
+// sets the operation time of that session to ts if it is later than the current one.
+// If `lsid` field is not present or the session does not exist, it does nothing.
+func (r *Registry) AdvanceOperationTime(ctx context.Context, spec wirebson.AnyDocument, ts wirebson.Timestamp) error {
+	userID := getUserID(ctx)
+
+	sessionID, err := getSessionUUID(spec)
+	if err != nil {
+		return err
+	}
+
+	if sessionID == uuid.Nil {
+		return nil
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	if s := r.sessions[userID][sessionID]; s != nil && s.operationTime < ts {
+		s.operationTime = ts
+	}
+
+	return nil
+}
+
+// OperationTime fetches `lsid` field from spec and
+// returns the latest operation time of that session set by [Registry.AdvanceOperationTime].
+// If `lsid` field is not present or the session does not exist, it returns zero value.
+func (r *Registry) OperationTime(ctx context.Context, spec wirebson.AnyDocument) (wirebson.Timestamp, error) {
+	userID := getUserID(ctx)
+
+	sessionID, err := getSessionUUID(spec)
+	if err != nil {
+		return 0, err
+	}
+
+	if sessionID == uuid.Nil {
+		return 0, nil
+	}
+
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	if s := r.sessions[userID][sessionID]; s != nil {
+		return s.operationTime, nil
+	}
+
+	return 0, nil
+}
+
 // ValidateCursor checks if the cursor is created by the same session and the same user.
 // If the cursor does not exist, there is nothing to check and no error is returned.
 func (r *Registry) ValidateCursor(userID UserID, sessionID uuid.UUID, cursorID int64) error {
@@ -411,6 +461,14 @@ func (r *Registry) deleteSessions(userID UserID, sessionIDs []uuid.UUID, reason
 	return cursorIDs
 }
 
+// SetTimeout sets the duration after which unused sessions expire.
+func (r *Registry) SetTimeout(timeout time.Duration) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	r.timeout = timeout
+}
+
 // DeleteExpired removes ended sessions and expired session from the registry and
 // returns cursors of the deleted sessions.
 func (r *Registry) DeleteExpired() []int64 {
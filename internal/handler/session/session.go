@@ -49,6 +49,9 @@ type sessionInfo struct {
 	lastUsed  time.Time
 	ended     bool
 
+	// the latest operation time returned to the client, used for causal consistency
+	operationTime wirebson.Timestamp
+
 	token *resource.Token
 }
 
@@ -0,0 +1,143 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+)
+
+// topReadCommands contains collection-level commands other than find and getMore
+// that are accounted as reads by `top` (unless they are aggregations with `$out` or `$merge` stages).
+var topReadCommands = []string{
+	"aggregate",
+	"collStats",
+	"count",
+	"distinct",
+	"listIndexes",
+	"validate",
+}
+
+// topWriteCommands contains collection-level commands other than insert, update, and delete
+// that are accounted as writes by `top`.
+//
+// `drop` and `renameCollection` are not accounted; instead, they remove latencies of affected collections.
+var topWriteCommands = []string{
+	"collMod",
+	"compact",
+	"create",
+	"createIndexes",
+	"createSearchIndexes",
+	"dropIndexes",
+	"dropSearchIndex",
+	"findAndModify",
+	"findandmodify",
+	"reIndex",
+	"updateSearchIndex",
+}
+
+// topType returns operation and lock types of the collection-level command for `top`.
+// It returns empty strings for commands that are not accounted.
+func topType(command string, doc *wirebson.Document) (typ, lock string) {
+	switch command {
+	case "find":
+		return "queries", "read"
+	case "getMore":
+		return "getmore", "read"
+	case "insert", "update":
+		return command, "write"
+	case "delete":
+		return "remove", "write"
+	}
+
+	switch {
+	case slices.Contains(topWriteCommands, command), middleware.AggregateWrites(doc):
+		return "commands", "write"
+	case slices.Contains(topReadCommands, command):
+		return "commands", "read"
+	default:
+		return "", ""
+	}
+}
+
+// recordLatency records the latency of the collection-level command for `top` and metrics.
+// Other commands are not recorded.
+//
+// Latencies of dropped and renamed collections and dropped databases are removed
+// if the command succeeded.
+func (h *Handler) recordLatency(doc *wirebson.Document, d time.Duration, err error) {
+	if h.ConnMetrics == nil {
+		return
+	}
+
+	db, _ := doc.Get("$db").(string)
+	if db == "" {
+		return
+	}
+
+	command := doc.Command()
+
+	switch command {
+	case "drop":
+		if collection, _ := doc.Get(command).(string); err == nil && collection != "" {
+			h.ConnMetrics.DeleteLatencies(db, collection)
+		}
+
+		return
+
+	case "dropDatabase":
+		if err == nil {
+			h.ConnMetrics.DeleteLatencies(db, "")
+		}
+
+		return
+
+	case "renameCollection":
+		if err != nil {
+			return
+		}
+
+		for _, key := range []string{command, "to"} {
+			ns, _ := doc.Get(key).(string)
+			if nsDB, collection, ok := strings.Cut(ns, "."); ok && nsDB != "" && collection != "" {
+				h.ConnMetrics.DeleteLatencies(nsDB, collection)
+			}
+		}
+
+		return
+	}
+
+	typ, lock := topType(command, doc)
+	if typ == "" {
+		return
+	}
+
+	key := command
+	if command == "getMore" {
+		key = "collection"
+	}
+
+	collection, _ := doc.Get(key).(string)
+	if collection == "" {
+		return
+	}
+
+	h.ConnMetrics.Latencies.WithLabelValues(db, collection, typ, lock).Observe(d.Seconds())
+}
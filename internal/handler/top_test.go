@@ -0,0 +1,76 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FerretDB/FerretDB/v2/internal/clientconn/connmetrics"
+)
+
+func TestRecordLatency(t *testing.T) {
+	t.Parallel()
+
+	h := &Handler{NewOpts: &NewOpts{ConnMetrics: connmetrics.NewListenerMetrics().ConnMetrics}}
+
+	namespaces := func() []string {
+		var res []string
+		for _, l := range h.ConnMetrics.GetLatencies() {
+			res = append(res, l.DB+"."+l.Collection+" "+l.Type+" "+l.Lock)
+		}
+
+		return res
+	}
+
+	h.recordLatency(wirebson.MustDocument("find", "a", "$db", "test"), time.Millisecond, nil)
+	h.recordLatency(wirebson.MustDocument("count", "b", "$db", "test"), time.Millisecond, nil)
+	h.recordLatency(wirebson.MustDocument("createIndexes", "c", "$db", "test"), time.Millisecond, nil)
+	h.recordLatency(wirebson.MustDocument("find", "d", "$db", "other"), time.Millisecond, nil)
+	h.recordLatency(wirebson.MustDocument("getMore", int64(1), "collection", "a", "$db", "test"), time.Millisecond, nil)
+
+	// not collection-level commands
+	h.recordLatency(wirebson.MustDocument("getParameter", "*", "$db", "admin"), time.Millisecond, nil)
+	h.recordLatency(wirebson.MustDocument("getLog", "global", "$db", "admin"), time.Millisecond, nil)
+
+	assert.ElementsMatch(t, []string{
+		"test.a queries read",
+		"test.a getmore read",
+		"test.b commands read",
+		"test.c commands write",
+		"other.d queries read",
+	}, namespaces())
+
+	h.recordLatency(wirebson.MustDocument("drop", "a", "$db", "test"), time.Millisecond, errors.New("failed"))
+	h.recordLatency(wirebson.MustDocument("drop", "b", "$db", "test"), time.Millisecond, nil)
+	h.recordLatency(
+		wirebson.MustDocument("renameCollection", "test.c", "to", "test.e", "$db", "admin"),
+		time.Millisecond, nil,
+	)
+
+	assert.ElementsMatch(t, []string{
+		"test.a queries read",
+		"test.a getmore read",
+		"other.d queries read",
+	}, namespaces())
+
+	h.recordLatency(wirebson.MustDocument("dropDatabase", int32(1), "$db", "test"), time.Millisecond, nil)
+
+	assert.ElementsMatch(t, []string{"other.d queries read"}, namespaces())
+}
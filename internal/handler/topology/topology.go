@@ -0,0 +1,326 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package topology emulates replica set topology for multiple FerretDB instances
+// that use the same PostgreSQL cluster.
+//
+// Each instance advertises its peers (from a static list or DNS SRV record).
+// The instance whose PostgreSQL connection is the primary reports itself as a writable primary;
+// others report themselves as secondaries and advertise that primary.
+package topology
+
+import (
+	"context"
+	"encoding/binary"
+	"log/slog"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/FerretDB/wire/wireclient"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+const (
+	// defaultInterval is the default interval between topology checks.
+	defaultInterval = 10 * time.Second
+
+	// checkTimeout is the timeout of a single topology check.
+	checkTimeout = 5 * time.Second
+
+	// SetVersion is the replica set configuration version.
+	// Members are configured by flags or DNS, not by `replSetReconfig`, so it never changes.
+	SetVersion = int32(1)
+)
+
+// Status represents the replica set topology as seen by this instance.
+type Status struct {
+	Me        string   // this instance's host:port
+	Hosts     []string // all instances' host:port, including this one, sorted
+	Primary   string   // primary's host:port; empty if unknown
+	Writable  bool     // true if this instance is the primary
+	Secondary bool     // true if this instance is a secondary
+
+	// ElectionID is set for the primary only; see [ElectionID].
+	ElectionID wirebson.ObjectID
+}
+
+// Topology periodically checks replica set topology.
+type Topology struct {
+	opts *NewOpts
+
+	// for tests
+	lookupSRV func(ctx context.Context, name string) ([]string, error)
+	hello     func(ctx context.Context, host string) (bool, error)
+
+	rw     sync.RWMutex
+	status Status
+}
+
+// NewOpts represents [New] options.
+//
+//nolint:vet // for readability
+type NewOpts struct {
+	L *slog.Logger
+
+	Self  string   // host:port advertised by this instance
+	Peers []string // host:port of other instances
+	SRV   string   // DNS SRV record name to discover peers; used if Peers are empty
+
+	// IsPrimary returns true if this instance's PostgreSQL connection is the primary.
+	IsPrimary func(ctx context.Context) (bool, error)
+
+	// Timeline returns the PostgreSQL timeline ID that is incremented on each promotion.
+	// It is used as an election term for electionId; nil means the term is always 1.
+	Timeline func(ctx context.Context) (int64, error)
+
+	Interval time.Duration // zero value is replaced with a default
+}
+
+// New creates a new Topology.
+// [Topology.Run] must be called on the returned value.
+func New(opts *NewOpts) (*Topology, error) {
+	must.NotBeZero(opts)
+
+	if _, _, err := net.SplitHostPort(opts.Self); err != nil {
+		return nil, lazyerrors.Errorf("invalid self address %q: %w", opts.Self, err)
+	}
+
+	for _, peer := range opts.Peers {
+		if _, _, err := net.SplitHostPort(peer); err != nil {
+			return nil, lazyerrors.Errorf("invalid peer address %q: %w", peer, err)
+		}
+	}
+
+	if opts.IsPrimary == nil {
+		return nil, lazyerrors.New("IsPrimary is not set")
+	}
+
+	if opts.Interval == 0 {
+		opts.Interval = defaultInterval
+	}
+
+	t := &Topology{
+		opts: opts,
+		status: Status{
+			Me:    opts.Self,
+			Hosts: normalizeHosts(opts.Self, opts.Peers),
+		},
+	}
+
+	t.lookupSRV = t.defaultLookupSRV
+	t.hello = t.defaultHello
+
+	return t, nil
+}
+
+// Run checks topology until ctx is canceled.
+func (t *Topology) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		t.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			t.opts.L.InfoContext(ctx, "Topology checks stopped")
+			return
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// Status returns the last known topology.
+func (t *Topology) Status() Status {
+	t.rw.RLock()
+	defer t.rw.RUnlock()
+
+	res := t.status
+	res.Hosts = slices.Clone(res.Hosts)
+
+	return res
+}
+
+// check updates topology status.
+func (t *Topology) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	peers := t.opts.Peers
+
+	if len(peers) == 0 && t.opts.SRV != "" {
+		var err error
+		if peers, err = t.lookupSRV(ctx, t.opts.SRV); err != nil {
+			t.opts.L.WarnContext(ctx, "Failed to discover peers", slog.String("srv", t.opts.SRV), logging.Error(err))
+
+			// keep previously discovered peers
+			peers = t.Status().Hosts
+		}
+	}
+
+	status := Status{
+		Me:    t.opts.Self,
+		Hosts: normalizeHosts(t.opts.Self, peers),
+	}
+
+	primary, err := t.opts.IsPrimary(ctx)
+
+	switch {
+	case err != nil:
+		// neither primary nor secondary, like a recovering member
+		t.opts.L.WarnContext(ctx, "Failed to check PostgreSQL primary", logging.Error(err))
+
+	case primary:
+		status.Writable = true
+		status.Primary = t.opts.Self
+
+		term := int64(1)
+
+		if t.opts.Timeline != nil {
+			if term, err = t.opts.Timeline(ctx); err != nil {
+				t.opts.L.WarnContext(ctx, "Failed to get PostgreSQL timeline", logging.Error(err))
+
+				// keep the previous term
+				term = termOf(t.Status().ElectionID)
+			}
+		}
+
+		status.ElectionID = ElectionID(term)
+
+	default:
+		status.Secondary = true
+
+		for _, host := range status.Hosts {
+			if host == t.opts.Self {
+				continue
+			}
+
+			var ok bool
+			if ok, err = t.hello(ctx, host); err != nil {
+				t.opts.L.DebugContext(ctx, "Failed to check peer", slog.String("peer", host), logging.Error(err))
+				continue
+			}
+
+			if ok {
+				status.Primary = host
+				break
+			}
+		}
+	}
+
+	t.rw.Lock()
+	prev := t.status
+	t.status = status
+	t.rw.Unlock()
+
+	if prev.Writable != status.Writable || prev.Primary != status.Primary || !slices.Equal(prev.Hosts, status.Hosts) {
+		t.opts.L.InfoContext(
+			ctx, "Topology changed",
+			slog.Any("hosts", status.Hosts), slog.String("primary", status.Primary), slog.Bool("writable", status.Writable),
+		)
+	}
+}
+
+// defaultLookupSRV returns host:port of targets of the given DNS SRV record.
+func (t *Topology) defaultLookupSRV(ctx context.Context, name string) ([]string, error) {
+	_, addrs, err := net.DefaultResolver.LookupSRV(ctx, "", "", name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := make([]string, len(addrs))
+
+	for i, addr := range addrs {
+		res[i] = net.JoinHostPort(strings.TrimSuffix(addr.Target, "."), strconv.Itoa(int(addr.Port)))
+	}
+
+	return res, nil
+}
+
+// defaultHello sends `hello` command to the given peer and returns true if it is a writable primary.
+//
+// TLS-only peers are not supported yet.
+func (t *Topology) defaultHello(ctx context.Context, host string) (bool, error) {
+	conn, err := wireclient.Connect(ctx, "mongodb://"+host+"/", t.opts.L)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	defer conn.Close() //nolint:errcheck // we are only reading
+
+	_, resBody, err := conn.Request(ctx, wire.MustOpMsg(
+		"hello", int32(1),
+		"$db", "admin",
+	))
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	msg, ok := resBody.(*wire.OpMsg)
+	if !ok {
+		return false, lazyerrors.Errorf("unexpected response type %T", resBody)
+	}
+
+	res, err := msg.Document()
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	writable, _ := res.Get("isWritablePrimary").(bool)
+
+	return writable, nil
+}
+
+// normalizeHosts returns sorted and deduplicated list of self and peers.
+func normalizeHosts(self string, peers []string) []string {
+	res := make([]string, 0, len(peers)+1)
+	res = append(res, self)
+	res = append(res, peers...)
+
+	slices.Sort(res)
+
+	return slices.Compact(res)
+}
+
+// ElectionID returns electionId for the given election term,
+// in the same format as MongoDB: 0x7fffffff followed by the big-endian term.
+//
+// Drivers use it to detect stale primaries, so all instances must agree on the term.
+func ElectionID(term int64) wirebson.ObjectID {
+	var res wirebson.ObjectID
+
+	binary.BigEndian.PutUint32(res[0:4], 0x7fffffff)
+	binary.BigEndian.PutUint64(res[4:12], uint64(term))
+
+	return res
+}
+
+// termOf returns the election term of the given electionId, or 1 if it is not set.
+func termOf(id wirebson.ObjectID) int64 {
+	if id == (wirebson.ObjectID{}) {
+		return 1
+	}
+
+	return int64(binary.BigEndian.Uint64(id[4:12]))
+}
@@ -0,0 +1,190 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topology
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestTopology(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		isPrimary    error
+		primary      bool
+		peersPrimary map[string]bool
+		expected     Status
+	}{
+		"Primary": {
+			primary: true,
+			expected: Status{
+				Me:         "b:27017",
+				Hosts:      []string{"a:27017", "b:27017", "c:27017"},
+				Primary:    "b:27017",
+				Writable:   true,
+				ElectionID: ElectionID(1),
+			},
+		},
+		"Secondary": {
+			peersPrimary: map[string]bool{"c:27017": true},
+			expected: Status{
+				Me:        "b:27017",
+				Hosts:     []string{"a:27017", "b:27017", "c:27017"},
+				Primary:   "c:27017",
+				Secondary: true,
+			},
+		},
+		"NoPrimary": {
+			expected: Status{
+				Me:        "b:27017",
+				Hosts:     []string{"a:27017", "b:27017", "c:27017"},
+				Secondary: true,
+			},
+		},
+		"Unavailable": {
+			isPrimary: errors.New("connection refused"),
+			expected: Status{
+				Me:    "b:27017",
+				Hosts: []string{"a:27017", "b:27017", "c:27017"},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			topo, err := New(&NewOpts{
+				L:     testutil.Logger(t),
+				Self:  "b:27017",
+				Peers: []string{"c:27017", "a:27017", "b:27017"},
+				IsPrimary: func(context.Context) (bool, error) {
+					return tc.primary, tc.isPrimary
+				},
+			})
+			require.NoError(t, err)
+
+			topo.hello = func(_ context.Context, host string) (bool, error) {
+				if host == "b:27017" {
+					t.Error("self should not be checked")
+				}
+
+				return tc.peersPrimary[host], nil
+			}
+
+			topo.check(testutil.Ctx(t))
+			assert.Equal(t, tc.expected, topo.Status())
+		})
+	}
+}
+
+func TestTopologySRV(t *testing.T) {
+	t.Parallel()
+
+	topo, err := New(&NewOpts{
+		L:    testutil.Logger(t),
+		Self: "ferretdb-0.ferretdb:27017",
+		SRV:  "_mongodb._tcp.ferretdb",
+		IsPrimary: func(context.Context) (bool, error) {
+			return true, nil
+		},
+	})
+	require.NoError(t, err)
+
+	var fail bool
+
+	topo.lookupSRV = func(_ context.Context, name string) ([]string, error) {
+		assert.Equal(t, "_mongodb._tcp.ferretdb", name)
+
+		if fail {
+			return nil, errors.New("no such host")
+		}
+
+		return []string{"ferretdb-1.ferretdb:27017", "ferretdb-0.ferretdb:27017"}, nil
+	}
+
+	expected := Status{
+		Me:         "ferretdb-0.ferretdb:27017",
+		Hosts:      []string{"ferretdb-0.ferretdb:27017", "ferretdb-1.ferretdb:27017"},
+		Primary:    "ferretdb-0.ferretdb:27017",
+		Writable:   true,
+		ElectionID: ElectionID(1),
+	}
+
+	ctx := testutil.Ctx(t)
+
+	topo.check(ctx)
+	assert.Equal(t, expected, topo.Status())
+
+	fail = true
+
+	topo.check(ctx)
+	assert.Equal(t, expected, topo.Status(), "previously discovered peers should be kept")
+}
+
+func TestTopologyElectionID(t *testing.T) {
+	t.Parallel()
+
+	var timelineErr error
+
+	topo, err := New(&NewOpts{
+		L:    testutil.Logger(t),
+		Self: "a:27017",
+		IsPrimary: func(context.Context) (bool, error) {
+			return true, nil
+		},
+		Timeline: func(context.Context) (int64, error) {
+			if timelineErr != nil {
+				return 0, timelineErr
+			}
+
+			return 3, nil
+		},
+	})
+	require.NoError(t, err)
+
+	ctx := testutil.Ctx(t)
+
+	topo.check(ctx)
+
+	electionID := topo.Status().ElectionID
+	assert.Equal(t, "7fffffff0000000000000003", hex.EncodeToString(electionID[:]))
+
+	timelineErr = errors.New("permission denied")
+
+	topo.check(ctx)
+	assert.Equal(t, ElectionID(3), topo.Status().ElectionID, "previous term should be kept")
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	isPrimary := func(context.Context) (bool, error) { return true, nil }
+
+	_, err := New(&NewOpts{L: testutil.Logger(t), Self: "localhost", IsPrimary: isPrimary})
+	assert.Error(t, err)
+
+	_, err = New(&NewOpts{L: testutil.Logger(t), Self: "localhost:27017", Peers: []string{"peer"}, IsPrimary: isPrimary})
+	assert.Error(t, err)
+
+	_, err = New(&NewOpts{L: testutil.Logger(t), Self: "localhost:27017"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,277 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/v2/internal/documentdb"
+	"github.com/FerretDB/FerretDB/v2/internal/handler/middleware"
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// maxWriteConcernW is the maximal numeric `w` value of write concern.
+const maxWriteConcernW = 50
+
+// writeConcernCommands contains commands that accept `writeConcern`,
+// in addition to aggregations with $out and $merge stages.
+var writeConcernCommands = []string{
+	"collMod",
+	"create",
+	"createIndexes",
+	"createUser",
+	"delete",
+	"drop",
+	"dropAllUsersFromDatabase",
+	"dropDatabase",
+	"dropIndexes",
+	"dropUser",
+	"findAndModify",
+	"insert",
+	"renameCollection",
+	"update",
+	"updateUser",
+}
+
+// writeConcern represents a parsed `writeConcern` document.
+//
+//nolint:vet // for readability
+type writeConcern struct {
+	w        int32  // number of members, ignored if majority is true or mode is set
+	majority bool   // `w: "majority"`
+	mode     string // custom write concern mode name, not supported
+	journal  bool   // `j` or `fsync`
+	timeout  time.Duration
+
+	// as supplied by the client, with provenance
+	doc *wirebson.Document
+}
+
+// wait returns true if the write concern requires waiting after the write.
+func (wc *writeConcern) wait() bool {
+	return wc.majority || wc.mode != "" || wc.w > 1 || wc.journal
+}
+
+// getWriteConcern returns the write concern of the command, or nil if it was not supplied
+// or the command does not accept it.
+func getWriteConcern(doc *wirebson.Document) (*writeConcern, error) {
+	command := doc.Command()
+
+	if !slices.Contains(writeConcernCommands, command) && !middleware.AggregateWrites(doc) {
+		return nil, nil
+	}
+
+	v := doc.Get("writeConcern")
+	if v == nil {
+		return nil, nil
+	}
+
+	wcV, ok := v.(wirebson.AnyDocument)
+	if !ok {
+		msg := fmt.Sprintf(
+			"BSON field '%s.writeConcern' is the wrong type '%s', expected type 'object'",
+			command, aliasFromType(v),
+		)
+
+		return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, command)
+	}
+
+	wcDoc, err := wcV.Decode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := &writeConcern{
+		w:   1,
+		doc: wirebson.MakeDocument(wcDoc.Len() + 1),
+	}
+
+	for k, v := range wcDoc.All() {
+		switch k {
+		case "w":
+			if err = res.setW(v); err != nil {
+				return nil, err
+			}
+
+		case "j", "fsync":
+			var b bool
+			if b, err = getBoolParam("writeConcern."+k, v); err != nil {
+				return nil, err
+			}
+
+			res.journal = res.journal || b
+
+		case "wtimeout":
+			var ms int64
+			if ms, err = getWholeNumberParam(v); err != nil {
+				msg := fmt.Sprintf(
+					"BSON field 'writeConcern.wtimeout' is the wrong type '%s', expected types '[long, int, decimal, double]'",
+					aliasFromType(v),
+				)
+
+				return nil, mongoerrors.NewWithArgument(mongoerrors.ErrTypeMismatch, msg, command)
+			}
+
+			res.timeout = time.Duration(max(ms, 0)) * time.Millisecond
+
+		default:
+			// ignore unknown fields like drivers' legacy options
+			continue
+		}
+
+		must.NoError(res.doc.Add(k, v))
+	}
+
+	must.NoError(res.doc.Add("provenance", "clientSupplied"))
+
+	return res, nil
+}
+
+// setW sets `w` field value.
+func (wc *writeConcern) setW(v any) error {
+	if s, ok := v.(string); ok {
+		if s == "majority" {
+			wc.majority = true
+		} else {
+			wc.mode = s
+		}
+
+		return nil
+	}
+
+	w, err := getWholeNumberParam(v)
+	if err != nil || w < 0 || w > maxWriteConcernW {
+		return mongoerrors.NewWithArgument(
+			mongoerrors.ErrFailedToParse,
+			fmt.Sprintf("w has to be a non-negative number and not greater than %d; found: %v", maxWriteConcernW, v),
+			"writeConcern",
+		)
+	}
+
+	wc.w = int32(w)
+
+	return nil
+}
+
+// getWholeNumberParam returns int64 value of whole number v.
+func getWholeNumberParam(v any) (int64, error) {
+	switch v := v.(type) {
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		if v != math.Trunc(v) || v > math.MaxInt64 || v < math.MinInt64 {
+			return 0, lazyerrors.Errorf("%v is not a whole number", v)
+		}
+
+		return int64(v), nil
+	default:
+		return 0, lazyerrors.Errorf("unexpected type %T", v)
+	}
+}
+
+// waitForWriteConcern waits until the write concern is satisfied and returns the response
+// with added `writeConcernError` field if it was not.
+func (h *Handler) waitForWriteConcern(ctx context.Context, wc *writeConcern, res *middleware.Response) (*middleware.Response, error) {
+	if !wc.wait() || res.OpMsg == nil {
+		return res, nil
+	}
+
+	var wcErr *wirebson.Document
+
+	if wc.mode != "" {
+		wcErr = writeConcernError(
+			mongoerrors.ErrUnknownReplWriteConcern,
+			fmt.Sprintf("No write concern mode named '%s' found in replica set configuration", wc.mode),
+			wc, false,
+		)
+	} else {
+		waitCtx := ctx
+
+		if wc.timeout > 0 {
+			var cancel context.CancelFunc
+			waitCtx, cancel = context.WithTimeout(ctx, wc.timeout)
+
+			defer cancel()
+		}
+
+		// replica set hosts advertised to clients are members too
+		var members int32
+		if h.Topology != nil {
+			members = int32(len(h.Topology.Status().Hosts))
+		}
+
+		err := h.Pool.WaitForReplication(waitCtx, wc.w, wc.majority, wc.journal, members)
+
+		switch {
+		case err == nil:
+			return res, nil
+
+		case errors.Is(err, documentdb.ErrNotEnoughReplicas):
+			wcErr = writeConcernError(mongoerrors.ErrUnsatisfiableWriteConcern, "Not enough data-bearing nodes", wc, false)
+
+		case waitCtx.Err() != nil && ctx.Err() == nil:
+			wcErr = writeConcernError(mongoerrors.ErrWriteConcernFailed, "waiting for replication timed out", wc, true)
+
+		default:
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	doc, err := res.OpMsg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	newDoc := wirebson.MakeDocument(doc.Len() + 1)
+
+	for k, v := range doc.All() {
+		if k == "ok" {
+			must.NoError(newDoc.Add("writeConcernError", wcErr))
+		}
+
+		must.NoError(newDoc.Add(k, v))
+	}
+
+	return middleware.ResponseMsg(newDoc)
+}
+
+// writeConcernError returns `writeConcernError` document.
+func writeConcernError(code mongoerrors.Code, msg string, wc *writeConcern, timeout bool) *wirebson.Document {
+	errInfo := wirebson.MakeDocument(2)
+
+	if timeout {
+		must.NoError(errInfo.Add("wtimeout", true))
+	}
+
+	must.NoError(errInfo.Add("writeConcern", wc.doc))
+
+	return must.NotFail(wirebson.NewDocument(
+		"code", int32(code),
+		"codeName", code.String(),
+		"errmsg", msg,
+		"errInfo", errInfo,
+	))
+}
@@ -0,0 +1,163 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/mongoerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestGetWriteConcern(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		doc *wirebson.Document
+
+		expected *writeConcern // nil means no write concern
+		wait     bool
+		code     mongoerrors.Code
+	}{
+		"Missing": {
+			doc: wirebson.MustDocument("insert", "c"),
+		},
+		"ReadCommand": {
+			doc: wirebson.MustDocument("find", "c", "writeConcern", wirebson.MustDocument("w", int32(2))),
+		},
+		"Default": {
+			doc: wirebson.MustDocument("insert", "c", "writeConcern", wirebson.MustDocument()),
+			expected: &writeConcern{
+				w:   1,
+				doc: wirebson.MustDocument("provenance", "clientSupplied"),
+			},
+		},
+		"Number": {
+			doc: wirebson.MustDocument(
+				"update", "c",
+				"writeConcern", wirebson.MustDocument("w", float64(3), "wtimeout", int32(100)),
+			),
+			expected: &writeConcern{
+				w:       3,
+				timeout: 100 * time.Millisecond,
+				doc:     wirebson.MustDocument("w", float64(3), "wtimeout", int32(100), "provenance", "clientSupplied"),
+			},
+			wait: true,
+		},
+		"Majority": {
+			doc: wirebson.MustDocument(
+				"delete", "c",
+				"writeConcern", wirebson.MustDocument("w", "majority", "j", true),
+			),
+			expected: &writeConcern{
+				w:        1,
+				majority: true,
+				journal:  true,
+				doc:      wirebson.MustDocument("w", "majority", "j", true, "provenance", "clientSupplied"),
+			},
+			wait: true,
+		},
+		"Mode": {
+			doc: wirebson.MustDocument("insert", "c", "writeConcern", wirebson.MustDocument("w", "dc1")),
+			expected: &writeConcern{
+				w:    1,
+				mode: "dc1",
+				doc:  wirebson.MustDocument("w", "dc1", "provenance", "clientSupplied"),
+			},
+			wait: true,
+		},
+		"Aggregate": {
+			doc: wirebson.MustDocument(
+				"aggregate", "c",
+				"pipeline", wirebson.MustArray(wirebson.MustDocument("$out", "c2")),
+				"writeConcern", wirebson.MustDocument("w", int32(0)),
+			),
+			expected: &writeConcern{
+				doc: wirebson.MustDocument("w", int32(0), "provenance", "clientSupplied"),
+			},
+		},
+		"NegativeW": {
+			doc:  wirebson.MustDocument("insert", "c", "writeConcern", wirebson.MustDocument("w", int32(-1))),
+			code: mongoerrors.ErrFailedToParse,
+		},
+		"TooLargeW": {
+			doc:  wirebson.MustDocument("insert", "c", "writeConcern", wirebson.MustDocument("w", int64(51))),
+			code: mongoerrors.ErrFailedToParse,
+		},
+		"WrongType": {
+			doc:  wirebson.MustDocument("insert", "c", "writeConcern", "majority"),
+			code: mongoerrors.ErrTypeMismatch,
+		},
+		"WrongTimeoutType": {
+			doc:  wirebson.MustDocument("insert", "c", "writeConcern", wirebson.MustDocument("wtimeout", "1")),
+			code: mongoerrors.ErrTypeMismatch,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			wc, err := getWriteConcern(tc.doc)
+
+			if tc.code != 0 {
+				var e *mongoerrors.Error
+				require.ErrorAs(t, err, &e)
+				assert.Equal(t, tc.code, mongoerrors.Code(e.Code))
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tc.expected == nil {
+				assert.Nil(t, wc)
+				return
+			}
+
+			require.NotNil(t, wc)
+			testutil.AssertEqual(t, tc.expected.doc, wc.doc)
+
+			wc.doc, tc.expected.doc = nil, nil
+			assert.Equal(t, tc.expected, wc)
+			assert.Equal(t, tc.wait, wc.wait())
+		})
+	}
+}
+
+func TestWriteConcernError(t *testing.T) {
+	t.Parallel()
+
+	wc := &writeConcern{
+		w:   2,
+		doc: wirebson.MustDocument("w", int32(2), "wtimeout", int32(10), "provenance", "clientSupplied"),
+	}
+
+	expected := wirebson.MustDocument(
+		"code", int32(64),
+		"codeName", "WriteConcernFailed",
+		"errmsg", "waiting for replication timed out",
+		"errInfo", wirebson.MustDocument(
+			"wtimeout", true,
+			"writeConcern", wirebson.MustDocument("w", int32(2), "wtimeout", int32(10), "provenance", "clientSupplied"),
+		),
+	)
+
+	actual := writeConcernError(mongoerrors.ErrWriteConcernFailed, "waiting for replication timed out", wc, true)
+	testutil.AssertEqual(t, expected, actual)
+}
@@ -12,6 +12,7 @@ func _() {
 	_ = x[ErrInternalError-1]
 	_ = x[ErrBadValue-2]
 	_ = x[ErrGraphContainsCycle-5]
+	_ = x[ErrHostUnreachable-6]
 	_ = x[ErrFailedToParse-9]
 	_ = x[ErrUserNotFound-11]
 	_ = x[ErrUnsupportedFormat-12]
@@ -40,18 +41,23 @@ func _() {
 	_ = x[ErrDottedFieldName-57]
 	_ = x[ErrCommandNotFound-59]
 	_ = x[ErrShardKeyNotFound-61]
+	_ = x[ErrWriteConcernFailed-64]
 	_ = x[ErrImmutableField-66]
 	_ = x[ErrCannotCreateIndex-67]
 	_ = x[ErrIndexAlreadyExists-68]
 	_ = x[ErrInvalidOptions-72]
 	_ = x[ErrInvalidNamespace-73]
+	_ = x[ErrUnknownReplWriteConcern-79]
 	_ = x[ErrIndexOptionsConflict-85]
 	_ = x[ErrIndexKeySpecsConflict-86]
+	_ = x[ErrShutdownInProgress-91]
 	_ = x[ErrOperationFailed-96]
+	_ = x[ErrUnsatisfiableWriteConcern-100]
 	_ = x[ErrNotExactValueField-111]
 	_ = x[ErrCommandNotSupported-115]
 	_ = x[ErrNamespaceNotSharded-118]
 	_ = x[ErrDocumentFailedValidation-121]
+	_ = x[ErrFailedToSatisfyReadPreference-133]
 	_ = x[ErrExceededMemoryLimit-146]
 	_ = x[ErrDurationOverflow-159]
 	_ = x[ErrViewDepthLimitExceeded-165]
@@ -64,6 +70,7 @@ func _() {
 	_ = x[ErrQueryFeatureNotAllowed-224]
 	_ = x[ErrMaxSubPipelineDepthExceeded-232]
 	_ = x[ErrNotImplemented-238]
+	_ = x[ErrSnapshotTooOld-239]
 	_ = x[ErrConversionFailure-241]
 	_ = x[ErrOperationNotSupportedInTransaction-263]
 	_ = x[ErrIndexBuildAborted-276]
@@ -76,6 +83,7 @@ func _() {
 	_ = x[ErrNotWritablePrimary-10107]
 	_ = x[ErrBsonObjectTooLarge-10334]
 	_ = x[ErrDuplicateKey-11000]
+	_ = x[ErrInterrupted-11601]
 	_ = x[ErrBackgroundOperationInProgressForNamespace-12587]
 	_ = x[ErrLocation13026-13026]
 	_ = x[ErrLocation13027-13027]
@@ -543,542 +551,550 @@ func _() {
 	_ = x[ErrLocation8993000-8993000]
 }
 
-const _Code_name = "UnsetInternalErrorBadValueGraphContainsCycleFailedToParseUserNotFoundUnsupportedFormatUnauthorizedTypeMismatchOverflowInvalidLengthProtocolErrorAuthenticationFailedIllegalOperationAlreadyInitializedNamespaceNotFoundIndexNotFoundPathNotViableRoleNotFoundCannotBackfillArrayConflictingUpdateOperatorsCursorNotFoundNamespaceExistsMaxTimeMSExpiredDollarPrefixedFieldNameCanNotBeTypeArrayNotSingleValueFieldLocation55EmptyFieldNameDottedFieldNameCommandNotFoundShardKeyNotFoundImmutableFieldCannotCreateIndexIndexAlreadyExistsInvalidOptionsInvalidNamespaceIndexOptionsConflictIndexKeySpecsConflictOperationFailedNotExactValueFieldCommandNotSupportedNamespaceNotShardedDocumentFailedValidationExceededMemoryLimitDurationOverflowViewDepthLimitExceededCommandNotSupportedOnViewOptionNotSupportedOnViewAmbiguousIndexKeyPatternClientMetadataCannotBeMutatedInvalidIndexSpecificationOptionInvalidUUIDQueryFeatureNotAllowedMaxSubPipelineDepthExceededNotImplementedConversionFailureOperationNotSupportedInTransactionIndexBuildAbortedUnableToFindIndexMechanismUnavailableUnsupportedOpQueryCommandCollectionUUIDMismatchUserCountLimitExceededLocation10065NotWritablePrimaryBsonObjectTooLargeDuplicateKeyBackgroundOperationInProgressForNamespaceLocation13026Location13027Location13068Location13111MergeStageNoMatchingDocumentDbAlreadyExistsLocation13548Location15947Location15952Location15955Location15957Location15958Location15959Location15972Location15976Location15981Location15998Location16004Location16006Location16007Location16020Location16034Location16035Location16410Location16411Location16433DollarAddNumericOrDateTypesDollarModByZeroProhibitedDollarModOnlyNumericDollarAddOnlyOneDateLocation16702Location16747Location16748Location16749Location16755Location16764HashedIndexDoNotSupportArrayValuesLocation16800Location16801Location16804Location16874Location16875Location16876Location16878Location16879Location16880Location16882Location16883Location16979Location16990Location16994Location17040Location17041Location17042Location17043Location17044Location17045Location17046Location17047Location17048Location17049Location17053DollarCondMissingIfParameterDollarCondMissingThenParameterDollarCondMissingElseParameterDollarCondBadParameterDollarSizeRequiresArrayExactlyOneTextIndexLocation17261Location17276Location17308Location17310DocumentAfterUpdateLargerThanMaxSizeDocumentToUpsertLargerThanMaxSizeLocation18533Location18534Location18535Location18536Location18537Location18628Location18629Location28625Location28646Location28647Location28648Location28650Location28651Location28656Location28657Location28664RangeArgumentExpressionArgsOutOfRangeDollarAbsCantTakeLongMinValueArrayOperatorElemAtFirstArgMustBeArrayDollarArrayElemAtSecondArgArgMustBeNumericDollarArrayElemAtSecondArgArgMustBe32BitDollarSqrtGreaterOrEqualToZeroDollarSliceInvalidInputDollarSliceInvalidTypeSecondArgDollarSliceInvalidValueSecondArgDollarSliceInvalidTypeThirdArgDollarSliceInvalidValueThirdArgDollarSliceInvalidSignThirdArgLocation28745Location28746Location28747Location28748Location28749DollarLogArgumentMustBeNumericDollarLogBaseMustBeNumericDollarLogNumberMustBePositiveDollarLogBaseMustBeGreaterThanOneDollarLog10MustBePositiveNumberDollarPowBaseMustBeNumericDollarPowExponentMustBeNumericDollarPowExponentInvalidForZeroBaseLocation28765DollarLnMustBePositiveNumberLocation28769Location28803Location28808Location28809Location28810Location28811Location28812Location28818Location28822Location31002Location31022Location31023Location31024KeyCannotContainNullByteLocation31034Location31095Location31109Location31119Location31120Location31138Location31170Location31249Location31250Location31253Location31254Location31256Location31271Location31276Location31308Location31325Location31393Location31395Location31441Location31465Location34435Location34443Location34444Location34445Location34446Location34447Location34448Location34449Location34450Location34451Location34452Location34453Location34454Location34455Location34460Location34461Location34462Location34463Location34464Location34465Location34466Location34467Location34468Location34471Location34473DollarSwitchRequiresObjectDollarSwitchRequiresArrayForBranchesDollarSwitchRequiresObjectForEachBranchDollarSwitchUnknownArgumentForBranchDollarSwitchRequiresCaseExpressionForBranchDollarSwitchRequiresThenExpressionForBranchDollarSwitchNoMatchingBranchAndNoDefaultDollarSwitchBadArgumentDollarSwitchRequiresAtLeastOneBranchLocation40075Location40076Location40077Location40078Location40079Location40080DollarInRequiresArrayLocation40085Location40086Location40087Location40090Location40091Location40092Location40093Location40094Location40096Location40097Location40100Location40101Location40102Location40103Location40104Location40105Location40147Location40156Location40158Location40160Location40169Location40177Location40181Location40185Location40191Location40192Location40193Location40194Location40195Location40196Location40197Location40198Location40199Location40200Location40201Location40202Location40218Location40228Location40229Location40234Location40235Location40236Location40237Location40238Location40272Location40319Location40321Location40323UnrecognizedCommandLocation40352DollarArrayToObjectRequiresArrayDollarObjectToArrayRequiresObjectDollarArrayToObjectAllMustBeObjectsDollarArrayToObjectIncorrectNumberOfKeysDollarArrayToObjectRequiresObjectWithKAndVDollarArrayToObjectObjectKeyMustBeStringDollarArrayToObjectArrayKeyMustBeStringDollarArrayToObjectAllMustBeArraysDollarArrayToObjectIncorrectArrayLengthDollarArrayToObjectBadInputTypeFormatDollarMergeObjectsInvalidTypeLocation40414UnknownBsonFieldLocation40485Location40489Location40515Location40516Location40517Location40518Location40519Location40520Location40521Location40522Location40523Location40524Location40525Location40533Location40535Location40536Location40539Location40540Location40541Location40542Location40600Location40601Location40602Location40603Location40621ChangeStreamBadResumeTokenLocation40684InsufficientPrivilegeLocation50687Location50692Location50694Location50695Location50696Location50699Location50700Location50723Location50752Location50759Location50840Location50989Location51003Location51024Location51044Location51045Location51047Location51074Location51075DollarRoundOverflowInt64DollarRoundFirstArgMustBeNumericDollarRoundPrecisionMustBeIntegralDollarRoundPrecisionOutOfRangeLocation51091Location51103Location51104Location51105Location51106Location51107Location51108Location51109Location51110Location51111Location51132Location51134Location51151Location51156Location51178Location51183Location51185Location51186Location51187Location51191Location51246Location51247Location51276Location51743Location51744Location51745Location51746Location51747Location51748Location51749Location51750Location51751Location327391Location327392Location605001DollarIfNullRequiresAtLeastTwoArgsLocation2942500Location2942501Location2942502Location2942503Location2942504Location2942505Location2942506DollarRandNonEmptyArgumentLocation3041701Location3041702Location3041703Location3041704IntermediateResultTooLargeDollarSetFieldRequiresObjectDollarSetFieldUnknownArgumentLocation4161102Location4161103Location4161104Location4161105Location4161106Location4161107Location4161108Location4161109Location4341107Location4890500Location4940400Location4940401Location5107200Location5107201Location5166301Location5166302Location5166303Location5166304Location5166305Location5166307Location5166400Location5166401Location5166402Location5166403Location5166404Location5166405Location5166406Location5339900Location5339901Location5339902Location5371601Location5371602Location5371603Location5423900Location5423901Location5423902Location5429413Location5429414Location5429513Location5439007Location5439008Location5439009Location5439010Location5439012Location5439013Location5439014Location5439015Location5439016Location5439017Location5439018Location5490710Location5624900Location5624901Location5626500Location5654600Location5654601Location5654602Location5687301Location5687302Location5687400Location5687401Location5733201Location5733401Location5733402Location5733403Location5733406Location5733408Location5733409Location5739101Location5746102Location5787801Location5787900Location5787901Location5787902Location5787903Location5787906Location5787907Location5787908Location5788001Location5788002Location5788003Location5788004Location5788005Location5788200Location5788604Location5858203Location5860402Location5876900Location5897900Location5946802Location5976500Location6007200Location6045000Location6050106Location6050202Location6050204Location6053600Location6586400Location7429703Location7436100Location7555701Location7555702Location7749501Location7750301Location7750302Location7750303Location8993000"
+const _Code_name = "UnsetInternalErrorBadValueGraphContainsCycleHostUnreachableFailedToParseUserNotFoundUnsupportedFormatUnauthorizedTypeMismatchOverflowInvalidLengthProtocolErrorAuthenticationFailedIllegalOperationAlreadyInitializedNamespaceNotFoundIndexNotFoundPathNotViableRoleNotFoundCannotBackfillArrayConflictingUpdateOperatorsCursorNotFoundNamespaceExistsMaxTimeMSExpiredDollarPrefixedFieldNameCanNotBeTypeArrayNotSingleValueFieldLocation55EmptyFieldNameDottedFieldNameCommandNotFoundShardKeyNotFoundWriteConcernFailedImmutableFieldCannotCreateIndexIndexAlreadyExistsInvalidOptionsInvalidNamespaceUnknownReplWriteConcernIndexOptionsConflictIndexKeySpecsConflictShutdownInProgressOperationFailedUnsatisfiableWriteConcernNotExactValueFieldCommandNotSupportedNamespaceNotShardedDocumentFailedValidationFailedToSatisfyReadPreferenceExceededMemoryLimitDurationOverflowViewDepthLimitExceededCommandNotSupportedOnViewOptionNotSupportedOnViewAmbiguousIndexKeyPatternClientMetadataCannotBeMutatedInvalidIndexSpecificationOptionInvalidUUIDQueryFeatureNotAllowedMaxSubPipelineDepthExceededNotImplementedSnapshotTooOldConversionFailureOperationNotSupportedInTransactionIndexBuildAbortedUnableToFindIndexMechanismUnavailableUnsupportedOpQueryCommandCollectionUUIDMismatchUserCountLimitExceededLocation10065NotWritablePrimaryBsonObjectTooLargeDuplicateKeyInterruptedBackgroundOperationInProgressForNamespaceLocation13026Location13027Location13068Location13111MergeStageNoMatchingDocumentDbAlreadyExistsLocation13548Location15947Location15952Location15955Location15957Location15958Location15959Location15972Location15976Location15981Location15998Location16004Location16006Location16007Location16020Location16034Location16035Location16410Location16411Location16433DollarAddNumericOrDateTypesDollarModByZeroProhibitedDollarModOnlyNumericDollarAddOnlyOneDateLocation16702Location16747Location16748Location16749Location16755Location16764HashedIndexDoNotSupportArrayValuesLocation16800Location16801Location16804Location16874Location16875Location16876Location16878Location16879Location16880Location16882Location16883Location16979Location16990Location16994Location17040Location17041Location17042Location17043Location17044Location17045Location17046Location17047Location17048Location17049Location17053DollarCondMissingIfParameterDollarCondMissingThenParameterDollarCondMissingElseParameterDollarCondBadParameterDollarSizeRequiresArrayExactlyOneTextIndexLocation17261Location17276Location17308Location17310DocumentAfterUpdateLargerThanMaxSizeDocumentToUpsertLargerThanMaxSizeLocation18533Location18534Location18535Location18536Location18537Location18628Location18629Location28625Location28646Location28647Location28648Location28650Location28651Location28656Location28657Location28664RangeArgumentExpressionArgsOutOfRangeDollarAbsCantTakeLongMinValueArrayOperatorElemAtFirstArgMustBeArrayDollarArrayElemAtSecondArgArgMustBeNumericDollarArrayElemAtSecondArgArgMustBe32BitDollarSqrtGreaterOrEqualToZeroDollarSliceInvalidInputDollarSliceInvalidTypeSecondArgDollarSliceInvalidValueSecondArgDollarSliceInvalidTypeThirdArgDollarSliceInvalidValueThirdArgDollarSliceInvalidSignThirdArgLocation28745Location28746Location28747Location28748Location28749DollarLogArgumentMustBeNumericDollarLogBaseMustBeNumericDollarLogNumberMustBePositiveDollarLogBaseMustBeGreaterThanOneDollarLog10MustBePositiveNumberDollarPowBaseMustBeNumericDollarPowExponentMustBeNumericDollarPowExponentInvalidForZeroBaseLocation28765DollarLnMustBePositiveNumberLocation28769Location28803Location28808Location28809Location28810Location28811Location28812Location28818Location28822Location31002Location31022Location31023Location31024KeyCannotContainNullByteLocation31034Location31095Location31109Location31119Location31120Location31138Location31170Location31249Location31250Location31253Location31254Location31256Location31271Location31276Location31308Location31325Location31393Location31395Location31441Location31465Location34435Location34443Location34444Location34445Location34446Location34447Location34448Location34449Location34450Location34451Location34452Location34453Location34454Location34455Location34460Location34461Location34462Location34463Location34464Location34465Location34466Location34467Location34468Location34471Location34473DollarSwitchRequiresObjectDollarSwitchRequiresArrayForBranchesDollarSwitchRequiresObjectForEachBranchDollarSwitchUnknownArgumentForBranchDollarSwitchRequiresCaseExpressionForBranchDollarSwitchRequiresThenExpressionForBranchDollarSwitchNoMatchingBranchAndNoDefaultDollarSwitchBadArgumentDollarSwitchRequiresAtLeastOneBranchLocation40075Location40076Location40077Location40078Location40079Location40080DollarInRequiresArrayLocation40085Location40086Location40087Location40090Location40091Location40092Location40093Location40094Location40096Location40097Location40100Location40101Location40102Location40103Location40104Location40105Location40147Location40156Location40158Location40160Location40169Location40177Location40181Location40185Location40191Location40192Location40193Location40194Location40195Location40196Location40197Location40198Location40199Location40200Location40201Location40202Location40218Location40228Location40229Location40234Location40235Location40236Location40237Location40238Location40272Location40319Location40321Location40323UnrecognizedCommandLocation40352DollarArrayToObjectRequiresArrayDollarObjectToArrayRequiresObjectDollarArrayToObjectAllMustBeObjectsDollarArrayToObjectIncorrectNumberOfKeysDollarArrayToObjectRequiresObjectWithKAndVDollarArrayToObjectObjectKeyMustBeStringDollarArrayToObjectArrayKeyMustBeStringDollarArrayToObjectAllMustBeArraysDollarArrayToObjectIncorrectArrayLengthDollarArrayToObjectBadInputTypeFormatDollarMergeObjectsInvalidTypeLocation40414UnknownBsonFieldLocation40485Location40489Location40515Location40516Location40517Location40518Location40519Location40520Location40521Location40522Location40523Location40524Location40525Location40533Location40535Location40536Location40539Location40540Location40541Location40542Location40600Location40601Location40602Location40603Location40621ChangeStreamBadResumeTokenLocation40684InsufficientPrivilegeLocation50687Location50692Location50694Location50695Location50696Location50699Location50700Location50723Location50752Location50759Location50840Location50989Location51003Location51024Location51044Location51045Location51047Location51074Location51075DollarRoundOverflowInt64DollarRoundFirstArgMustBeNumericDollarRoundPrecisionMustBeIntegralDollarRoundPrecisionOutOfRangeLocation51091Location51103Location51104Location51105Location51106Location51107Location51108Location51109Location51110Location51111Location51132Location51134Location51151Location51156Location51178Location51183Location51185Location51186Location51187Location51191Location51246Location51247Location51276Location51743Location51744Location51745Location51746Location51747Location51748Location51749Location51750Location51751Location327391Location327392Location605001DollarIfNullRequiresAtLeastTwoArgsLocation2942500Location2942501Location2942502Location2942503Location2942504Location2942505Location2942506DollarRandNonEmptyArgumentLocation3041701Location3041702Location3041703Location3041704IntermediateResultTooLargeDollarSetFieldRequiresObjectDollarSetFieldUnknownArgumentLocation4161102Location4161103Location4161104Location4161105Location4161106Location4161107Location4161108Location4161109Location4341107Location4890500Location4940400Location4940401Location5107200Location5107201Location5166301Location5166302Location5166303Location5166304Location5166305Location5166307Location5166400Location5166401Location5166402Location5166403Location5166404Location5166405Location5166406Location5339900Location5339901Location5339902Location5371601Location5371602Location5371603Location5423900Location5423901Location5423902Location5429413Location5429414Location5429513Location5439007Location5439008Location5439009Location5439010Location5439012Location5439013Location5439014Location5439015Location5439016Location5439017Location5439018Location5490710Location5624900Location5624901Location5626500Location5654600Location5654601Location5654602Location5687301Location5687302Location5687400Location5687401Location5733201Location5733401Location5733402Location5733403Location5733406Location5733408Location5733409Location5739101Location5746102Location5787801Location5787900Location5787901Location5787902Location5787903Location5787906Location5787907Location5787908Location5788001Location5788002Location5788003Location5788004Location5788005Location5788200Location5788604Location5858203Location5860402Location5876900Location5897900Location5946802Location5976500Location6007200Location6045000Location6050106Location6050202Location6050204Location6053600Location6586400Location7429703Location7436100Location7555701Location7555702Location7749501Location7750301Location7750302Location7750303Location8993000"
 
 var _Code_map = map[Code]string{
 	0:       _Code_name[0:5],
 	1:       _Code_name[5:18],
 	2:       _Code_name[18:26],
 	5:       _Code_name[26:44],
-	9:       _Code_name[44:57],
-	11:      _Code_name[57:69],
-	12:      _Code_name[69:86],
-	13:      _Code_name[86:98],
-	14:      _Code_name[98:110],
-	15:      _Code_name[110:118],
-	16:      _Code_name[118:131],
-	17:      _Code_name[131:144],
-	18:      _Code_name[144:164],
-	20:      _Code_name[164:180],
-	23:      _Code_name[180:198],
-	26:      _Code_name[198:215],
-	27:      _Code_name[215:228],
-	28:      _Code_name[228:241],
-	31:      _Code_name[241:253],
-	34:      _Code_name[253:272],
-	40:      _Code_name[272:298],
-	43:      _Code_name[298:312],
-	48:      _Code_name[312:327],
-	50:      _Code_name[327:343],
-	52:      _Code_name[343:366],
-	53:      _Code_name[366:383],
-	54:      _Code_name[383:402],
-	55:      _Code_name[402:412],
-	56:      _Code_name[412:426],
-	57:      _Code_name[426:441],
-	59:      _Code_name[441:456],
-	61:      _Code_name[456:472],
-	66:      _Code_name[472:486],
-	67:      _Code_name[486:503],
-	68:      _Code_name[503:521],
-	72:      _Code_name[521:535],
-	73:      _Code_name[535:551],
-	85:      _Code_name[551:571],
-	86:      _Code_name[571:592],
-	96:      _Code_name[592:607],
-	111:     _Code_name[607:625],
-	115:     _Code_name[625:644],
-	118:     _Code_name[644:663],
-	121:     _Code_name[663:687],
-	146:     _Code_name[687:706],
-	159:     _Code_name[706:722],
-	165:     _Code_name[722:744],
-	166:     _Code_name[744:769],
-	167:     _Code_name[769:793],
-	181:     _Code_name[793:817],
-	186:     _Code_name[817:846],
-	197:     _Code_name[846:877],
-	207:     _Code_name[877:888],
-	224:     _Code_name[888:910],
-	232:     _Code_name[910:937],
-	238:     _Code_name[937:951],
-	241:     _Code_name[951:968],
-	263:     _Code_name[968:1002],
-	276:     _Code_name[1002:1019],
-	291:     _Code_name[1019:1036],
-	334:     _Code_name[1036:1056],
-	352:     _Code_name[1056:1081],
-	361:     _Code_name[1081:1103],
-	8000:    _Code_name[1103:1125],
-	10065:   _Code_name[1125:1138],
-	10107:   _Code_name[1138:1156],
-	10334:   _Code_name[1156:1174],
-	11000:   _Code_name[1174:1186],
-	12587:   _Code_name[1186:1227],
-	13026:   _Code_name[1227:1240],
-	13027:   _Code_name[1240:1253],
-	13068:   _Code_name[1253:1266],
-	13111:   _Code_name[1266:1279],
-	13113:   _Code_name[1279:1307],
-	13297:   _Code_name[1307:1322],
-	13548:   _Code_name[1322:1335],
-	15947:   _Code_name[1335:1348],
-	15952:   _Code_name[1348:1361],
-	15955:   _Code_name[1361:1374],
-	15957:   _Code_name[1374:1387],
-	15958:   _Code_name[1387:1400],
-	15959:   _Code_name[1400:1413],
-	15972:   _Code_name[1413:1426],
-	15976:   _Code_name[1426:1439],
-	15981:   _Code_name[1439:1452],
-	15998:   _Code_name[1452:1465],
-	16004:   _Code_name[1465:1478],
-	16006:   _Code_name[1478:1491],
-	16007:   _Code_name[1491:1504],
-	16020:   _Code_name[1504:1517],
-	16034:   _Code_name[1517:1530],
-	16035:   _Code_name[1530:1543],
-	16410:   _Code_name[1543:1556],
-	16411:   _Code_name[1556:1569],
-	16433:   _Code_name[1569:1582],
-	16554:   _Code_name[1582:1609],
-	16610:   _Code_name[1609:1634],
-	16611:   _Code_name[1634:1654],
-	16612:   _Code_name[1654:1674],
-	16702:   _Code_name[1674:1687],
-	16747:   _Code_name[1687:1700],
-	16748:   _Code_name[1700:1713],
-	16749:   _Code_name[1713:1726],
-	16755:   _Code_name[1726:1739],
-	16764:   _Code_name[1739:1752],
-	16766:   _Code_name[1752:1786],
-	16800:   _Code_name[1786:1799],
-	16801:   _Code_name[1799:1812],
-	16804:   _Code_name[1812:1825],
-	16874:   _Code_name[1825:1838],
-	16875:   _Code_name[1838:1851],
-	16876:   _Code_name[1851:1864],
-	16878:   _Code_name[1864:1877],
-	16879:   _Code_name[1877:1890],
-	16880:   _Code_name[1890:1903],
-	16882:   _Code_name[1903:1916],
-	16883:   _Code_name[1916:1929],
-	16979:   _Code_name[1929:1942],
-	16990:   _Code_name[1942:1955],
-	16994:   _Code_name[1955:1968],
-	17040:   _Code_name[1968:1981],
-	17041:   _Code_name[1981:1994],
-	17042:   _Code_name[1994:2007],
-	17043:   _Code_name[2007:2020],
-	17044:   _Code_name[2020:2033],
-	17045:   _Code_name[2033:2046],
-	17046:   _Code_name[2046:2059],
-	17047:   _Code_name[2059:2072],
-	17048:   _Code_name[2072:2085],
-	17049:   _Code_name[2085:2098],
-	17053:   _Code_name[2098:2111],
-	17080:   _Code_name[2111:2139],
-	17081:   _Code_name[2139:2169],
-	17082:   _Code_name[2169:2199],
-	17083:   _Code_name[2199:2221],
-	17124:   _Code_name[2221:2244],
-	17194:   _Code_name[2244:2263],
-	17261:   _Code_name[2263:2276],
-	17276:   _Code_name[2276:2289],
-	17308:   _Code_name[2289:2302],
-	17310:   _Code_name[2302:2315],
-	17419:   _Code_name[2315:2351],
-	17420:   _Code_name[2351:2384],
-	18533:   _Code_name[2384:2397],
-	18534:   _Code_name[2397:2410],
-	18535:   _Code_name[2410:2423],
-	18536:   _Code_name[2423:2436],
-	18537:   _Code_name[2436:2449],
-	18628:   _Code_name[2449:2462],
-	18629:   _Code_name[2462:2475],
-	28625:   _Code_name[2475:2488],
-	28646:   _Code_name[2488:2501],
-	28647:   _Code_name[2501:2514],
-	28648:   _Code_name[2514:2527],
-	28650:   _Code_name[2527:2540],
-	28651:   _Code_name[2540:2553],
-	28656:   _Code_name[2553:2566],
-	28657:   _Code_name[2566:2579],
-	28664:   _Code_name[2579:2592],
-	28667:   _Code_name[2592:2629],
-	28680:   _Code_name[2629:2658],
-	28689:   _Code_name[2658:2696],
-	28690:   _Code_name[2696:2738],
-	28691:   _Code_name[2738:2778],
-	28714:   _Code_name[2778:2808],
-	28724:   _Code_name[2808:2831],
-	28725:   _Code_name[2831:2862],
-	28726:   _Code_name[2862:2894],
-	28727:   _Code_name[2894:2924],
-	28728:   _Code_name[2924:2955],
-	28729:   _Code_name[2955:2985],
-	28745:   _Code_name[2985:2998],
-	28746:   _Code_name[2998:3011],
-	28747:   _Code_name[3011:3024],
-	28748:   _Code_name[3024:3037],
-	28749:   _Code_name[3037:3050],
-	28756:   _Code_name[3050:3080],
-	28757:   _Code_name[3080:3106],
-	28758:   _Code_name[3106:3135],
-	28759:   _Code_name[3135:3168],
-	28761:   _Code_name[3168:3199],
-	28762:   _Code_name[3199:3225],
-	28763:   _Code_name[3225:3255],
-	28764:   _Code_name[3255:3290],
-	28765:   _Code_name[3290:3303],
-	28766:   _Code_name[3303:3331],
-	28769:   _Code_name[3331:3344],
-	28803:   _Code_name[3344:3357],
-	28808:   _Code_name[3357:3370],
-	28809:   _Code_name[3370:3383],
-	28810:   _Code_name[3383:3396],
-	28811:   _Code_name[3396:3409],
-	28812:   _Code_name[3409:3422],
-	28818:   _Code_name[3422:3435],
-	28822:   _Code_name[3435:3448],
-	31002:   _Code_name[3448:3461],
-	31022:   _Code_name[3461:3474],
-	31023:   _Code_name[3474:3487],
-	31024:   _Code_name[3487:3500],
-	31032:   _Code_name[3500:3524],
-	31034:   _Code_name[3524:3537],
-	31095:   _Code_name[3537:3550],
-	31109:   _Code_name[3550:3563],
-	31119:   _Code_name[3563:3576],
-	31120:   _Code_name[3576:3589],
-	31138:   _Code_name[3589:3602],
-	31170:   _Code_name[3602:3615],
-	31249:   _Code_name[3615:3628],
-	31250:   _Code_name[3628:3641],
-	31253:   _Code_name[3641:3654],
-	31254:   _Code_name[3654:3667],
-	31256:   _Code_name[3667:3680],
-	31271:   _Code_name[3680:3693],
-	31276:   _Code_name[3693:3706],
-	31308:   _Code_name[3706:3719],
-	31325:   _Code_name[3719:3732],
-	31393:   _Code_name[3732:3745],
-	31395:   _Code_name[3745:3758],
-	31441:   _Code_name[3758:3771],
-	31465:   _Code_name[3771:3784],
-	34435:   _Code_name[3784:3797],
-	34443:   _Code_name[3797:3810],
-	34444:   _Code_name[3810:3823],
-	34445:   _Code_name[3823:3836],
-	34446:   _Code_name[3836:3849],
-	34447:   _Code_name[3849:3862],
-	34448:   _Code_name[3862:3875],
-	34449:   _Code_name[3875:3888],
-	34450:   _Code_name[3888:3901],
-	34451:   _Code_name[3901:3914],
-	34452:   _Code_name[3914:3927],
-	34453:   _Code_name[3927:3940],
-	34454:   _Code_name[3940:3953],
-	34455:   _Code_name[3953:3966],
-	34460:   _Code_name[3966:3979],
-	34461:   _Code_name[3979:3992],
-	34462:   _Code_name[3992:4005],
-	34463:   _Code_name[4005:4018],
-	34464:   _Code_name[4018:4031],
-	34465:   _Code_name[4031:4044],
-	34466:   _Code_name[4044:4057],
-	34467:   _Code_name[4057:4070],
-	34468:   _Code_name[4070:4083],
-	34471:   _Code_name[4083:4096],
-	34473:   _Code_name[4096:4109],
-	40060:   _Code_name[4109:4135],
-	40061:   _Code_name[4135:4171],
-	40062:   _Code_name[4171:4210],
-	40063:   _Code_name[4210:4246],
-	40064:   _Code_name[4246:4289],
-	40065:   _Code_name[4289:4332],
-	40066:   _Code_name[4332:4372],
-	40067:   _Code_name[4372:4395],
-	40068:   _Code_name[4395:4431],
-	40075:   _Code_name[4431:4444],
-	40076:   _Code_name[4444:4457],
-	40077:   _Code_name[4457:4470],
-	40078:   _Code_name[4470:4483],
-	40079:   _Code_name[4483:4496],
-	40080:   _Code_name[4496:4509],
-	40081:   _Code_name[4509:4530],
-	40085:   _Code_name[4530:4543],
-	40086:   _Code_name[4543:4556],
-	40087:   _Code_name[4556:4569],
-	40090:   _Code_name[4569:4582],
-	40091:   _Code_name[4582:4595],
-	40092:   _Code_name[4595:4608],
-	40093:   _Code_name[4608:4621],
-	40094:   _Code_name[4621:4634],
-	40096:   _Code_name[4634:4647],
-	40097:   _Code_name[4647:4660],
-	40100:   _Code_name[4660:4673],
-	40101:   _Code_name[4673:4686],
-	40102:   _Code_name[4686:4699],
-	40103:   _Code_name[4699:4712],
-	40104:   _Code_name[4712:4725],
-	40105:   _Code_name[4725:4738],
-	40147:   _Code_name[4738:4751],
-	40156:   _Code_name[4751:4764],
-	40158:   _Code_name[4764:4777],
-	40160:   _Code_name[4777:4790],
-	40169:   _Code_name[4790:4803],
-	40177:   _Code_name[4803:4816],
-	40181:   _Code_name[4816:4829],
-	40185:   _Code_name[4829:4842],
-	40191:   _Code_name[4842:4855],
-	40192:   _Code_name[4855:4868],
-	40193:   _Code_name[4868:4881],
-	40194:   _Code_name[4881:4894],
-	40195:   _Code_name[4894:4907],
-	40196:   _Code_name[4907:4920],
-	40197:   _Code_name[4920:4933],
-	40198:   _Code_name[4933:4946],
-	40199:   _Code_name[4946:4959],
-	40200:   _Code_name[4959:4972],
-	40201:   _Code_name[4972:4985],
-	40202:   _Code_name[4985:4998],
-	40218:   _Code_name[4998:5011],
-	40228:   _Code_name[5011:5024],
-	40229:   _Code_name[5024:5037],
-	40234:   _Code_name[5037:5050],
-	40235:   _Code_name[5050:5063],
-	40236:   _Code_name[5063:5076],
-	40237:   _Code_name[5076:5089],
-	40238:   _Code_name[5089:5102],
-	40272:   _Code_name[5102:5115],
-	40319:   _Code_name[5115:5128],
-	40321:   _Code_name[5128:5141],
-	40323:   _Code_name[5141:5154],
-	40324:   _Code_name[5154:5173],
-	40352:   _Code_name[5173:5186],
-	40386:   _Code_name[5186:5218],
-	40390:   _Code_name[5218:5251],
-	40391:   _Code_name[5251:5286],
-	40392:   _Code_name[5286:5326],
-	40393:   _Code_name[5326:5368],
-	40394:   _Code_name[5368:5408],
-	40395:   _Code_name[5408:5447],
-	40396:   _Code_name[5447:5481],
-	40397:   _Code_name[5481:5520],
-	40398:   _Code_name[5520:5557],
-	40400:   _Code_name[5557:5586],
-	40414:   _Code_name[5586:5599],
-	40415:   _Code_name[5599:5615],
-	40485:   _Code_name[5615:5628],
-	40489:   _Code_name[5628:5641],
-	40515:   _Code_name[5641:5654],
-	40516:   _Code_name[5654:5667],
-	40517:   _Code_name[5667:5680],
-	40518:   _Code_name[5680:5693],
-	40519:   _Code_name[5693:5706],
-	40520:   _Code_name[5706:5719],
-	40521:   _Code_name[5719:5732],
-	40522:   _Code_name[5732:5745],
-	40523:   _Code_name[5745:5758],
-	40524:   _Code_name[5758:5771],
-	40525:   _Code_name[5771:5784],
-	40533:   _Code_name[5784:5797],
-	40535:   _Code_name[5797:5810],
-	40536:   _Code_name[5810:5823],
-	40539:   _Code_name[5823:5836],
-	40540:   _Code_name[5836:5849],
-	40541:   _Code_name[5849:5862],
-	40542:   _Code_name[5862:5875],
-	40600:   _Code_name[5875:5888],
-	40601:   _Code_name[5888:5901],
-	40602:   _Code_name[5901:5914],
-	40603:   _Code_name[5914:5927],
-	40621:   _Code_name[5927:5940],
-	40647:   _Code_name[5940:5966],
-	40684:   _Code_name[5966:5979],
-	42501:   _Code_name[5979:6000],
-	50687:   _Code_name[6000:6013],
-	50692:   _Code_name[6013:6026],
-	50694:   _Code_name[6026:6039],
-	50695:   _Code_name[6039:6052],
-	50696:   _Code_name[6052:6065],
-	50699:   _Code_name[6065:6078],
-	50700:   _Code_name[6078:6091],
-	50723:   _Code_name[6091:6104],
-	50752:   _Code_name[6104:6117],
-	50759:   _Code_name[6117:6130],
-	50840:   _Code_name[6130:6143],
-	50989:   _Code_name[6143:6156],
-	51003:   _Code_name[6156:6169],
-	51024:   _Code_name[6169:6182],
-	51044:   _Code_name[6182:6195],
-	51045:   _Code_name[6195:6208],
-	51047:   _Code_name[6208:6221],
-	51074:   _Code_name[6221:6234],
-	51075:   _Code_name[6234:6247],
-	51080:   _Code_name[6247:6271],
-	51081:   _Code_name[6271:6303],
-	51082:   _Code_name[6303:6337],
-	51083:   _Code_name[6337:6367],
-	51091:   _Code_name[6367:6380],
-	51103:   _Code_name[6380:6393],
-	51104:   _Code_name[6393:6406],
-	51105:   _Code_name[6406:6419],
-	51106:   _Code_name[6419:6432],
-	51107:   _Code_name[6432:6445],
-	51108:   _Code_name[6445:6458],
-	51109:   _Code_name[6458:6471],
-	51110:   _Code_name[6471:6484],
-	51111:   _Code_name[6484:6497],
-	51132:   _Code_name[6497:6510],
-	51134:   _Code_name[6510:6523],
-	51151:   _Code_name[6523:6536],
-	51156:   _Code_name[6536:6549],
-	51178:   _Code_name[6549:6562],
-	51183:   _Code_name[6562:6575],
-	51185:   _Code_name[6575:6588],
-	51186:   _Code_name[6588:6601],
-	51187:   _Code_name[6601:6614],
-	51191:   _Code_name[6614:6627],
-	51246:   _Code_name[6627:6640],
-	51247:   _Code_name[6640:6653],
-	51276:   _Code_name[6653:6666],
-	51743:   _Code_name[6666:6679],
-	51744:   _Code_name[6679:6692],
-	51745:   _Code_name[6692:6705],
-	51746:   _Code_name[6705:6718],
-	51747:   _Code_name[6718:6731],
-	51748:   _Code_name[6731:6744],
-	51749:   _Code_name[6744:6757],
-	51750:   _Code_name[6757:6770],
-	51751:   _Code_name[6770:6783],
-	327391:  _Code_name[6783:6797],
-	327392:  _Code_name[6797:6811],
-	605001:  _Code_name[6811:6825],
-	1257300: _Code_name[6825:6859],
-	2942500: _Code_name[6859:6874],
-	2942501: _Code_name[6874:6889],
-	2942502: _Code_name[6889:6904],
-	2942503: _Code_name[6904:6919],
-	2942504: _Code_name[6919:6934],
-	2942505: _Code_name[6934:6949],
-	2942506: _Code_name[6949:6964],
-	3040501: _Code_name[6964:6990],
-	3041701: _Code_name[6990:7005],
-	3041702: _Code_name[7005:7020],
-	3041703: _Code_name[7020:7035],
-	3041704: _Code_name[7035:7050],
-	4031700: _Code_name[7050:7076],
-	4161100: _Code_name[7076:7104],
-	4161101: _Code_name[7104:7133],
-	4161102: _Code_name[7133:7148],
-	4161103: _Code_name[7148:7163],
-	4161104: _Code_name[7163:7178],
-	4161105: _Code_name[7178:7193],
-	4161106: _Code_name[7193:7208],
-	4161107: _Code_name[7208:7223],
-	4161108: _Code_name[7223:7238],
-	4161109: _Code_name[7238:7253],
-	4341107: _Code_name[7253:7268],
-	4890500: _Code_name[7268:7283],
-	4940400: _Code_name[7283:7298],
-	4940401: _Code_name[7298:7313],
-	5107200: _Code_name[7313:7328],
-	5107201: _Code_name[7328:7343],
-	5166301: _Code_name[7343:7358],
-	5166302: _Code_name[7358:7373],
-	5166303: _Code_name[7373:7388],
-	5166304: _Code_name[7388:7403],
-	5166305: _Code_name[7403:7418],
-	5166307: _Code_name[7418:7433],
-	5166400: _Code_name[7433:7448],
-	5166401: _Code_name[7448:7463],
-	5166402: _Code_name[7463:7478],
-	5166403: _Code_name[7478:7493],
-	5166404: _Code_name[7493:7508],
-	5166405: _Code_name[7508:7523],
-	5166406: _Code_name[7523:7538],
-	5339900: _Code_name[7538:7553],
-	5339901: _Code_name[7553:7568],
-	5339902: _Code_name[7568:7583],
-	5371601: _Code_name[7583:7598],
-	5371602: _Code_name[7598:7613],
-	5371603: _Code_name[7613:7628],
-	5423900: _Code_name[7628:7643],
-	5423901: _Code_name[7643:7658],
-	5423902: _Code_name[7658:7673],
-	5429413: _Code_name[7673:7688],
-	5429414: _Code_name[7688:7703],
-	5429513: _Code_name[7703:7718],
-	5439007: _Code_name[7718:7733],
-	5439008: _Code_name[7733:7748],
-	5439009: _Code_name[7748:7763],
-	5439010: _Code_name[7763:7778],
-	5439012: _Code_name[7778:7793],
-	5439013: _Code_name[7793:7808],
-	5439014: _Code_name[7808:7823],
-	5439015: _Code_name[7823:7838],
-	5439016: _Code_name[7838:7853],
-	5439017: _Code_name[7853:7868],
-	5439018: _Code_name[7868:7883],
-	5490710: _Code_name[7883:7898],
-	5624900: _Code_name[7898:7913],
-	5624901: _Code_name[7913:7928],
-	5626500: _Code_name[7928:7943],
-	5654600: _Code_name[7943:7958],
-	5654601: _Code_name[7958:7973],
-	5654602: _Code_name[7973:7988],
-	5687301: _Code_name[7988:8003],
-	5687302: _Code_name[8003:8018],
-	5687400: _Code_name[8018:8033],
-	5687401: _Code_name[8033:8048],
-	5733201: _Code_name[8048:8063],
-	5733401: _Code_name[8063:8078],
-	5733402: _Code_name[8078:8093],
-	5733403: _Code_name[8093:8108],
-	5733406: _Code_name[8108:8123],
-	5733408: _Code_name[8123:8138],
-	5733409: _Code_name[8138:8153],
-	5739101: _Code_name[8153:8168],
-	5746102: _Code_name[8168:8183],
-	5787801: _Code_name[8183:8198],
-	5787900: _Code_name[8198:8213],
-	5787901: _Code_name[8213:8228],
-	5787902: _Code_name[8228:8243],
-	5787903: _Code_name[8243:8258],
-	5787906: _Code_name[8258:8273],
-	5787907: _Code_name[8273:8288],
-	5787908: _Code_name[8288:8303],
-	5788001: _Code_name[8303:8318],
-	5788002: _Code_name[8318:8333],
-	5788003: _Code_name[8333:8348],
-	5788004: _Code_name[8348:8363],
-	5788005: _Code_name[8363:8378],
-	5788200: _Code_name[8378:8393],
-	5788604: _Code_name[8393:8408],
-	5858203: _Code_name[8408:8423],
-	5860402: _Code_name[8423:8438],
-	5876900: _Code_name[8438:8453],
-	5897900: _Code_name[8453:8468],
-	5946802: _Code_name[8468:8483],
-	5976500: _Code_name[8483:8498],
-	6007200: _Code_name[8498:8513],
-	6045000: _Code_name[8513:8528],
-	6050106: _Code_name[8528:8543],
-	6050202: _Code_name[8543:8558],
-	6050204: _Code_name[8558:8573],
-	6053600: _Code_name[8573:8588],
-	6586400: _Code_name[8588:8603],
-	7429703: _Code_name[8603:8618],
-	7436100: _Code_name[8618:8633],
-	7555701: _Code_name[8633:8648],
-	7555702: _Code_name[8648:8663],
-	7749501: _Code_name[8663:8678],
-	7750301: _Code_name[8678:8693],
-	7750302: _Code_name[8693:8708],
-	7750303: _Code_name[8708:8723],
-	8993000: _Code_name[8723:8738],
+	6:       _Code_name[44:59],
+	9:       _Code_name[59:72],
+	11:      _Code_name[72:84],
+	12:      _Code_name[84:101],
+	13:      _Code_name[101:113],
+	14:      _Code_name[113:125],
+	15:      _Code_name[125:133],
+	16:      _Code_name[133:146],
+	17:      _Code_name[146:159],
+	18:      _Code_name[159:179],
+	20:      _Code_name[179:195],
+	23:      _Code_name[195:213],
+	26:      _Code_name[213:230],
+	27:      _Code_name[230:243],
+	28:      _Code_name[243:256],
+	31:      _Code_name[256:268],
+	34:      _Code_name[268:287],
+	40:      _Code_name[287:313],
+	43:      _Code_name[313:327],
+	48:      _Code_name[327:342],
+	50:      _Code_name[342:358],
+	52:      _Code_name[358:381],
+	53:      _Code_name[381:398],
+	54:      _Code_name[398:417],
+	55:      _Code_name[417:427],
+	56:      _Code_name[427:441],
+	57:      _Code_name[441:456],
+	59:      _Code_name[456:471],
+	61:      _Code_name[471:487],
+	64:      _Code_name[487:505],
+	66:      _Code_name[505:519],
+	67:      _Code_name[519:536],
+	68:      _Code_name[536:554],
+	72:      _Code_name[554:568],
+	73:      _Code_name[568:584],
+	79:      _Code_name[584:607],
+	85:      _Code_name[607:627],
+	86:      _Code_name[627:648],
+	91:      _Code_name[648:666],
+	96:      _Code_name[666:681],
+	100:     _Code_name[681:706],
+	111:     _Code_name[706:724],
+	115:     _Code_name[724:743],
+	118:     _Code_name[743:762],
+	121:     _Code_name[762:786],
+	133:     _Code_name[786:815],
+	146:     _Code_name[815:834],
+	159:     _Code_name[834:850],
+	165:     _Code_name[850:872],
+	166:     _Code_name[872:897],
+	167:     _Code_name[897:921],
+	181:     _Code_name[921:945],
+	186:     _Code_name[945:974],
+	197:     _Code_name[974:1005],
+	207:     _Code_name[1005:1016],
+	224:     _Code_name[1016:1038],
+	232:     _Code_name[1038:1065],
+	238:     _Code_name[1065:1079],
+	239:     _Code_name[1079:1093],
+	241:     _Code_name[1093:1110],
+	263:     _Code_name[1110:1144],
+	276:     _Code_name[1144:1161],
+	291:     _Code_name[1161:1178],
+	334:     _Code_name[1178:1198],
+	352:     _Code_name[1198:1223],
+	361:     _Code_name[1223:1245],
+	8000:    _Code_name[1245:1267],
+	10065:   _Code_name[1267:1280],
+	10107:   _Code_name[1280:1298],
+	10334:   _Code_name[1298:1316],
+	11000:   _Code_name[1316:1328],
+	11601:   _Code_name[1328:1339],
+	12587:   _Code_name[1339:1380],
+	13026:   _Code_name[1380:1393],
+	13027:   _Code_name[1393:1406],
+	13068:   _Code_name[1406:1419],
+	13111:   _Code_name[1419:1432],
+	13113:   _Code_name[1432:1460],
+	13297:   _Code_name[1460:1475],
+	13548:   _Code_name[1475:1488],
+	15947:   _Code_name[1488:1501],
+	15952:   _Code_name[1501:1514],
+	15955:   _Code_name[1514:1527],
+	15957:   _Code_name[1527:1540],
+	15958:   _Code_name[1540:1553],
+	15959:   _Code_name[1553:1566],
+	15972:   _Code_name[1566:1579],
+	15976:   _Code_name[1579:1592],
+	15981:   _Code_name[1592:1605],
+	15998:   _Code_name[1605:1618],
+	16004:   _Code_name[1618:1631],
+	16006:   _Code_name[1631:1644],
+	16007:   _Code_name[1644:1657],
+	16020:   _Code_name[1657:1670],
+	16034:   _Code_name[1670:1683],
+	16035:   _Code_name[1683:1696],
+	16410:   _Code_name[1696:1709],
+	16411:   _Code_name[1709:1722],
+	16433:   _Code_name[1722:1735],
+	16554:   _Code_name[1735:1762],
+	16610:   _Code_name[1762:1787],
+	16611:   _Code_name[1787:1807],
+	16612:   _Code_name[1807:1827],
+	16702:   _Code_name[1827:1840],
+	16747:   _Code_name[1840:1853],
+	16748:   _Code_name[1853:1866],
+	16749:   _Code_name[1866:1879],
+	16755:   _Code_name[1879:1892],
+	16764:   _Code_name[1892:1905],
+	16766:   _Code_name[1905:1939],
+	16800:   _Code_name[1939:1952],
+	16801:   _Code_name[1952:1965],
+	16804:   _Code_name[1965:1978],
+	16874:   _Code_name[1978:1991],
+	16875:   _Code_name[1991:2004],
+	16876:   _Code_name[2004:2017],
+	16878:   _Code_name[2017:2030],
+	16879:   _Code_name[2030:2043],
+	16880:   _Code_name[2043:2056],
+	16882:   _Code_name[2056:2069],
+	16883:   _Code_name[2069:2082],
+	16979:   _Code_name[2082:2095],
+	16990:   _Code_name[2095:2108],
+	16994:   _Code_name[2108:2121],
+	17040:   _Code_name[2121:2134],
+	17041:   _Code_name[2134:2147],
+	17042:   _Code_name[2147:2160],
+	17043:   _Code_name[2160:2173],
+	17044:   _Code_name[2173:2186],
+	17045:   _Code_name[2186:2199],
+	17046:   _Code_name[2199:2212],
+	17047:   _Code_name[2212:2225],
+	17048:   _Code_name[2225:2238],
+	17049:   _Code_name[2238:2251],
+	17053:   _Code_name[2251:2264],
+	17080:   _Code_name[2264:2292],
+	17081:   _Code_name[2292:2322],
+	17082:   _Code_name[2322:2352],
+	17083:   _Code_name[2352:2374],
+	17124:   _Code_name[2374:2397],
+	17194:   _Code_name[2397:2416],
+	17261:   _Code_name[2416:2429],
+	17276:   _Code_name[2429:2442],
+	17308:   _Code_name[2442:2455],
+	17310:   _Code_name[2455:2468],
+	17419:   _Code_name[2468:2504],
+	17420:   _Code_name[2504:2537],
+	18533:   _Code_name[2537:2550],
+	18534:   _Code_name[2550:2563],
+	18535:   _Code_name[2563:2576],
+	18536:   _Code_name[2576:2589],
+	18537:   _Code_name[2589:2602],
+	18628:   _Code_name[2602:2615],
+	18629:   _Code_name[2615:2628],
+	28625:   _Code_name[2628:2641],
+	28646:   _Code_name[2641:2654],
+	28647:   _Code_name[2654:2667],
+	28648:   _Code_name[2667:2680],
+	28650:   _Code_name[2680:2693],
+	28651:   _Code_name[2693:2706],
+	28656:   _Code_name[2706:2719],
+	28657:   _Code_name[2719:2732],
+	28664:   _Code_name[2732:2745],
+	28667:   _Code_name[2745:2782],
+	28680:   _Code_name[2782:2811],
+	28689:   _Code_name[2811:2849],
+	28690:   _Code_name[2849:2891],
+	28691:   _Code_name[2891:2931],
+	28714:   _Code_name[2931:2961],
+	28724:   _Code_name[2961:2984],
+	28725:   _Code_name[2984:3015],
+	28726:   _Code_name[3015:3047],
+	28727:   _Code_name[3047:3077],
+	28728:   _Code_name[3077:3108],
+	28729:   _Code_name[3108:3138],
+	28745:   _Code_name[3138:3151],
+	28746:   _Code_name[3151:3164],
+	28747:   _Code_name[3164:3177],
+	28748:   _Code_name[3177:3190],
+	28749:   _Code_name[3190:3203],
+	28756:   _Code_name[3203:3233],
+	28757:   _Code_name[3233:3259],
+	28758:   _Code_name[3259:3288],
+	28759:   _Code_name[3288:3321],
+	28761:   _Code_name[3321:3352],
+	28762:   _Code_name[3352:3378],
+	28763:   _Code_name[3378:3408],
+	28764:   _Code_name[3408:3443],
+	28765:   _Code_name[3443:3456],
+	28766:   _Code_name[3456:3484],
+	28769:   _Code_name[3484:3497],
+	28803:   _Code_name[3497:3510],
+	28808:   _Code_name[3510:3523],
+	28809:   _Code_name[3523:3536],
+	28810:   _Code_name[3536:3549],
+	28811:   _Code_name[3549:3562],
+	28812:   _Code_name[3562:3575],
+	28818:   _Code_name[3575:3588],
+	28822:   _Code_name[3588:3601],
+	31002:   _Code_name[3601:3614],
+	31022:   _Code_name[3614:3627],
+	31023:   _Code_name[3627:3640],
+	31024:   _Code_name[3640:3653],
+	31032:   _Code_name[3653:3677],
+	31034:   _Code_name[3677:3690],
+	31095:   _Code_name[3690:3703],
+	31109:   _Code_name[3703:3716],
+	31119:   _Code_name[3716:3729],
+	31120:   _Code_name[3729:3742],
+	31138:   _Code_name[3742:3755],
+	31170:   _Code_name[3755:3768],
+	31249:   _Code_name[3768:3781],
+	31250:   _Code_name[3781:3794],
+	31253:   _Code_name[3794:3807],
+	31254:   _Code_name[3807:3820],
+	31256:   _Code_name[3820:3833],
+	31271:   _Code_name[3833:3846],
+	31276:   _Code_name[3846:3859],
+	31308:   _Code_name[3859:3872],
+	31325:   _Code_name[3872:3885],
+	31393:   _Code_name[3885:3898],
+	31395:   _Code_name[3898:3911],
+	31441:   _Code_name[3911:3924],
+	31465:   _Code_name[3924:3937],
+	34435:   _Code_name[3937:3950],
+	34443:   _Code_name[3950:3963],
+	34444:   _Code_name[3963:3976],
+	34445:   _Code_name[3976:3989],
+	34446:   _Code_name[3989:4002],
+	34447:   _Code_name[4002:4015],
+	34448:   _Code_name[4015:4028],
+	34449:   _Code_name[4028:4041],
+	34450:   _Code_name[4041:4054],
+	34451:   _Code_name[4054:4067],
+	34452:   _Code_name[4067:4080],
+	34453:   _Code_name[4080:4093],
+	34454:   _Code_name[4093:4106],
+	34455:   _Code_name[4106:4119],
+	34460:   _Code_name[4119:4132],
+	34461:   _Code_name[4132:4145],
+	34462:   _Code_name[4145:4158],
+	34463:   _Code_name[4158:4171],
+	34464:   _Code_name[4171:4184],
+	34465:   _Code_name[4184:4197],
+	34466:   _Code_name[4197:4210],
+	34467:   _Code_name[4210:4223],
+	34468:   _Code_name[4223:4236],
+	34471:   _Code_name[4236:4249],
+	34473:   _Code_name[4249:4262],
+	40060:   _Code_name[4262:4288],
+	40061:   _Code_name[4288:4324],
+	40062:   _Code_name[4324:4363],
+	40063:   _Code_name[4363:4399],
+	40064:   _Code_name[4399:4442],
+	40065:   _Code_name[4442:4485],
+	40066:   _Code_name[4485:4525],
+	40067:   _Code_name[4525:4548],
+	40068:   _Code_name[4548:4584],
+	40075:   _Code_name[4584:4597],
+	40076:   _Code_name[4597:4610],
+	40077:   _Code_name[4610:4623],
+	40078:   _Code_name[4623:4636],
+	40079:   _Code_name[4636:4649],
+	40080:   _Code_name[4649:4662],
+	40081:   _Code_name[4662:4683],
+	40085:   _Code_name[4683:4696],
+	40086:   _Code_name[4696:4709],
+	40087:   _Code_name[4709:4722],
+	40090:   _Code_name[4722:4735],
+	40091:   _Code_name[4735:4748],
+	40092:   _Code_name[4748:4761],
+	40093:   _Code_name[4761:4774],
+	40094:   _Code_name[4774:4787],
+	40096:   _Code_name[4787:4800],
+	40097:   _Code_name[4800:4813],
+	40100:   _Code_name[4813:4826],
+	40101:   _Code_name[4826:4839],
+	40102:   _Code_name[4839:4852],
+	40103:   _Code_name[4852:4865],
+	40104:   _Code_name[4865:4878],
+	40105:   _Code_name[4878:4891],
+	40147:   _Code_name[4891:4904],
+	40156:   _Code_name[4904:4917],
+	40158:   _Code_name[4917:4930],
+	40160:   _Code_name[4930:4943],
+	40169:   _Code_name[4943:4956],
+	40177:   _Code_name[4956:4969],
+	40181:   _Code_name[4969:4982],
+	40185:   _Code_name[4982:4995],
+	40191:   _Code_name[4995:5008],
+	40192:   _Code_name[5008:5021],
+	40193:   _Code_name[5021:5034],
+	40194:   _Code_name[5034:5047],
+	40195:   _Code_name[5047:5060],
+	40196:   _Code_name[5060:5073],
+	40197:   _Code_name[5073:5086],
+	40198:   _Code_name[5086:5099],
+	40199:   _Code_name[5099:5112],
+	40200:   _Code_name[5112:5125],
+	40201:   _Code_name[5125:5138],
+	40202:   _Code_name[5138:5151],
+	40218:   _Code_name[5151:5164],
+	40228:   _Code_name[5164:5177],
+	40229:   _Code_name[5177:5190],
+	40234:   _Code_name[5190:5203],
+	40235:   _Code_name[5203:5216],
+	40236:   _Code_name[5216:5229],
+	40237:   _Code_name[5229:5242],
+	40238:   _Code_name[5242:5255],
+	40272:   _Code_name[5255:5268],
+	40319:   _Code_name[5268:5281],
+	40321:   _Code_name[5281:5294],
+	40323:   _Code_name[5294:5307],
+	40324:   _Code_name[5307:5326],
+	40352:   _Code_name[5326:5339],
+	40386:   _Code_name[5339:5371],
+	40390:   _Code_name[5371:5404],
+	40391:   _Code_name[5404:5439],
+	40392:   _Code_name[5439:5479],
+	40393:   _Code_name[5479:5521],
+	40394:   _Code_name[5521:5561],
+	40395:   _Code_name[5561:5600],
+	40396:   _Code_name[5600:5634],
+	40397:   _Code_name[5634:5673],
+	40398:   _Code_name[5673:5710],
+	40400:   _Code_name[5710:5739],
+	40414:   _Code_name[5739:5752],
+	40415:   _Code_name[5752:5768],
+	40485:   _Code_name[5768:5781],
+	40489:   _Code_name[5781:5794],
+	40515:   _Code_name[5794:5807],
+	40516:   _Code_name[5807:5820],
+	40517:   _Code_name[5820:5833],
+	40518:   _Code_name[5833:5846],
+	40519:   _Code_name[5846:5859],
+	40520:   _Code_name[5859:5872],
+	40521:   _Code_name[5872:5885],
+	40522:   _Code_name[5885:5898],
+	40523:   _Code_name[5898:5911],
+	40524:   _Code_name[5911:5924],
+	40525:   _Code_name[5924:5937],
+	40533:   _Code_name[5937:5950],
+	40535:   _Code_name[5950:5963],
+	40536:   _Code_name[5963:5976],
+	40539:   _Code_name[5976:5989],
+	40540:   _Code_name[5989:6002],
+	40541:   _Code_name[6002:6015],
+	40542:   _Code_name[6015:6028],
+	40600:   _Code_name[6028:6041],
+	40601:   _Code_name[6041:6054],
+	40602:   _Code_name[6054:6067],
+	40603:   _Code_name[6067:6080],
+	40621:   _Code_name[6080:6093],
+	40647:   _Code_name[6093:6119],
+	40684:   _Code_name[6119:6132],
+	42501:   _Code_name[6132:6153],
+	50687:   _Code_name[6153:6166],
+	50692:   _Code_name[6166:6179],
+	50694:   _Code_name[6179:6192],
+	50695:   _Code_name[6192:6205],
+	50696:   _Code_name[6205:6218],
+	50699:   _Code_name[6218:6231],
+	50700:   _Code_name[6231:6244],
+	50723:   _Code_name[6244:6257],
+	50752:   _Code_name[6257:6270],
+	50759:   _Code_name[6270:6283],
+	50840:   _Code_name[6283:6296],
+	50989:   _Code_name[6296:6309],
+	51003:   _Code_name[6309:6322],
+	51024:   _Code_name[6322:6335],
+	51044:   _Code_name[6335:6348],
+	51045:   _Code_name[6348:6361],
+	51047:   _Code_name[6361:6374],
+	51074:   _Code_name[6374:6387],
+	51075:   _Code_name[6387:6400],
+	51080:   _Code_name[6400:6424],
+	51081:   _Code_name[6424:6456],
+	51082:   _Code_name[6456:6490],
+	51083:   _Code_name[6490:6520],
+	51091:   _Code_name[6520:6533],
+	51103:   _Code_name[6533:6546],
+	51104:   _Code_name[6546:6559],
+	51105:   _Code_name[6559:6572],
+	51106:   _Code_name[6572:6585],
+	51107:   _Code_name[6585:6598],
+	51108:   _Code_name[6598:6611],
+	51109:   _Code_name[6611:6624],
+	51110:   _Code_name[6624:6637],
+	51111:   _Code_name[6637:6650],
+	51132:   _Code_name[6650:6663],
+	51134:   _Code_name[6663:6676],
+	51151:   _Code_name[6676:6689],
+	51156:   _Code_name[6689:6702],
+	51178:   _Code_name[6702:6715],
+	51183:   _Code_name[6715:6728],
+	51185:   _Code_name[6728:6741],
+	51186:   _Code_name[6741:6754],
+	51187:   _Code_name[6754:6767],
+	51191:   _Code_name[6767:6780],
+	51246:   _Code_name[6780:6793],
+	51247:   _Code_name[6793:6806],
+	51276:   _Code_name[6806:6819],
+	51743:   _Code_name[6819:6832],
+	51744:   _Code_name[6832:6845],
+	51745:   _Code_name[6845:6858],
+	51746:   _Code_name[6858:6871],
+	51747:   _Code_name[6871:6884],
+	51748:   _Code_name[6884:6897],
+	51749:   _Code_name[6897:6910],
+	51750:   _Code_name[6910:6923],
+	51751:   _Code_name[6923:6936],
+	327391:  _Code_name[6936:6950],
+	327392:  _Code_name[6950:6964],
+	605001:  _Code_name[6964:6978],
+	1257300: _Code_name[6978:7012],
+	2942500: _Code_name[7012:7027],
+	2942501: _Code_name[7027:7042],
+	2942502: _Code_name[7042:7057],
+	2942503: _Code_name[7057:7072],
+	2942504: _Code_name[7072:7087],
+	2942505: _Code_name[7087:7102],
+	2942506: _Code_name[7102:7117],
+	3040501: _Code_name[7117:7143],
+	3041701: _Code_name[7143:7158],
+	3041702: _Code_name[7158:7173],
+	3041703: _Code_name[7173:7188],
+	3041704: _Code_name[7188:7203],
+	4031700: _Code_name[7203:7229],
+	4161100: _Code_name[7229:7257],
+	4161101: _Code_name[7257:7286],
+	4161102: _Code_name[7286:7301],
+	4161103: _Code_name[7301:7316],
+	4161104: _Code_name[7316:7331],
+	4161105: _Code_name[7331:7346],
+	4161106: _Code_name[7346:7361],
+	4161107: _Code_name[7361:7376],
+	4161108: _Code_name[7376:7391],
+	4161109: _Code_name[7391:7406],
+	4341107: _Code_name[7406:7421],
+	4890500: _Code_name[7421:7436],
+	4940400: _Code_name[7436:7451],
+	4940401: _Code_name[7451:7466],
+	5107200: _Code_name[7466:7481],
+	5107201: _Code_name[7481:7496],
+	5166301: _Code_name[7496:7511],
+	5166302: _Code_name[7511:7526],
+	5166303: _Code_name[7526:7541],
+	5166304: _Code_name[7541:7556],
+	5166305: _Code_name[7556:7571],
+	5166307: _Code_name[7571:7586],
+	5166400: _Code_name[7586:7601],
+	5166401: _Code_name[7601:7616],
+	5166402: _Code_name[7616:7631],
+	5166403: _Code_name[7631:7646],
+	5166404: _Code_name[7646:7661],
+	5166405: _Code_name[7661:7676],
+	5166406: _Code_name[7676:7691],
+	5339900: _Code_name[7691:7706],
+	5339901: _Code_name[7706:7721],
+	5339902: _Code_name[7721:7736],
+	5371601: _Code_name[7736:7751],
+	5371602: _Code_name[7751:7766],
+	5371603: _Code_name[7766:7781],
+	5423900: _Code_name[7781:7796],
+	5423901: _Code_name[7796:7811],
+	5423902: _Code_name[7811:7826],
+	5429413: _Code_name[7826:7841],
+	5429414: _Code_name[7841:7856],
+	5429513: _Code_name[7856:7871],
+	5439007: _Code_name[7871:7886],
+	5439008: _Code_name[7886:7901],
+	5439009: _Code_name[7901:7916],
+	5439010: _Code_name[7916:7931],
+	5439012: _Code_name[7931:7946],
+	5439013: _Code_name[7946:7961],
+	5439014: _Code_name[7961:7976],
+	5439015: _Code_name[7976:7991],
+	5439016: _Code_name[7991:8006],
+	5439017: _Code_name[8006:8021],
+	5439018: _Code_name[8021:8036],
+	5490710: _Code_name[8036:8051],
+	5624900: _Code_name[8051:8066],
+	5624901: _Code_name[8066:8081],
+	5626500: _Code_name[8081:8096],
+	5654600: _Code_name[8096:8111],
+	5654601: _Code_name[8111:8126],
+	5654602: _Code_name[8126:8141],
+	5687301: _Code_name[8141:8156],
+	5687302: _Code_name[8156:8171],
+	5687400: _Code_name[8171:8186],
+	5687401: _Code_name[8186:8201],
+	5733201: _Code_name[8201:8216],
+	5733401: _Code_name[8216:8231],
+	5733402: _Code_name[8231:8246],
+	5733403: _Code_name[8246:8261],
+	5733406: _Code_name[8261:8276],
+	5733408: _Code_name[8276:8291],
+	5733409: _Code_name[8291:8306],
+	5739101: _Code_name[8306:8321],
+	5746102: _Code_name[8321:8336],
+	5787801: _Code_name[8336:8351],
+	5787900: _Code_name[8351:8366],
+	5787901: _Code_name[8366:8381],
+	5787902: _Code_name[8381:8396],
+	5787903: _Code_name[8396:8411],
+	5787906: _Code_name[8411:8426],
+	5787907: _Code_name[8426:8441],
+	5787908: _Code_name[8441:8456],
+	5788001: _Code_name[8456:8471],
+	5788002: _Code_name[8471:8486],
+	5788003: _Code_name[8486:8501],
+	5788004: _Code_name[8501:8516],
+	5788005: _Code_name[8516:8531],
+	5788200: _Code_name[8531:8546],
+	5788604: _Code_name[8546:8561],
+	5858203: _Code_name[8561:8576],
+	5860402: _Code_name[8576:8591],
+	5876900: _Code_name[8591:8606],
+	5897900: _Code_name[8606:8621],
+	5946802: _Code_name[8621:8636],
+	5976500: _Code_name[8636:8651],
+	6007200: _Code_name[8651:8666],
+	6045000: _Code_name[8666:8681],
+	6050106: _Code_name[8681:8696],
+	6050202: _Code_name[8696:8711],
+	6050204: _Code_name[8711:8726],
+	6053600: _Code_name[8726:8741],
+	6586400: _Code_name[8741:8756],
+	7429703: _Code_name[8756:8771],
+	7436100: _Code_name[8771:8786],
+	7555701: _Code_name[8786:8801],
+	7555702: _Code_name[8801:8816],
+	7749501: _Code_name[8816:8831],
+	7750301: _Code_name[8831:8846],
+	7750302: _Code_name[8846:8861],
+	7750303: _Code_name[8861:8876],
+	8993000: _Code_name[8876:8891],
 }
 
 func (i Code) String() string {
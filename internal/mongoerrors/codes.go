@@ -8,6 +8,7 @@
 	ErrInternalError                               = Code(1)       // InternalError
 	ErrBadValue                                    = Code(2)       // BadValue
 	ErrGraphContainsCycle                          = Code(5)       // GraphContainsCycle
+	ErrHostUnreachable                             = Code(6)       // HostUnreachable
 	ErrFailedToParse                               = Code(9)       // FailedToParse
 	ErrUserNotFound                                = Code(11)      // UserNotFound
 	ErrUnsupportedFormat                           = Code(12)      // UnsupportedFormat
@@ -36,18 +37,23 @@
 	ErrDottedFieldName                             = Code(57)      // DottedFieldName
 	ErrCommandNotFound                             = Code(59)      // CommandNotFound
 	ErrShardKeyNotFound                            = Code(61)      // ShardKeyNotFound
+	ErrWriteConcernFailed                          = Code(64)      // WriteConcernFailed
 	ErrImmutableField                              = Code(66)      // ImmutableField
 	ErrCannotCreateIndex                           = Code(67)      // CannotCreateIndex
 	ErrIndexAlreadyExists                          = Code(68)      // IndexAlreadyExists
 	ErrInvalidOptions                              = Code(72)      // InvalidOptions
 	ErrInvalidNamespace                            = Code(73)      // InvalidNamespace
+	ErrUnknownReplWriteConcern                     = Code(79)      // UnknownReplWriteConcern
 	ErrIndexOptionsConflict                        = Code(85)      // IndexOptionsConflict
 	ErrIndexKeySpecsConflict                       = Code(86)      // IndexKeySpecsConflict
+	ErrShutdownInProgress                          = Code(91)      // ShutdownInProgress
 	ErrOperationFailed                             = Code(96)      // OperationFailed
+	ErrUnsatisfiableWriteConcern                   = Code(100)     // UnsatisfiableWriteConcern
 	ErrNotExactValueField                          = Code(111)     // NotExactValueField
 	ErrCommandNotSupported                         = Code(115)     // CommandNotSupported
 	ErrNamespaceNotSharded                         = Code(118)     // NamespaceNotSharded
 	ErrDocumentFailedValidation                    = Code(121)     // DocumentFailedValidation
+	ErrFailedToSatisfyReadPreference               = Code(133)     // FailedToSatisfyReadPreference
 	ErrExceededMemoryLimit                         = Code(146)     // ExceededMemoryLimit
 	ErrDurationOverflow                            = Code(159)     // DurationOverflow
 	ErrViewDepthLimitExceeded                      = Code(165)     // ViewDepthLimitExceeded
@@ -60,6 +66,7 @@
 	ErrQueryFeatureNotAllowed                      = Code(224)     // QueryFeatureNotAllowed
 	ErrMaxSubPipelineDepthExceeded                 = Code(232)     // MaxSubPipelineDepthExceeded
 	ErrNotImplemented                              = Code(238)     // NotImplemented
+	ErrSnapshotTooOld                              = Code(239)     // SnapshotTooOld
 	ErrConversionFailure                           = Code(241)     // ConversionFailure
 	ErrOperationNotSupportedInTransaction          = Code(263)     // OperationNotSupportedInTransaction
 	ErrIndexBuildAborted                           = Code(276)     // IndexBuildAborted
@@ -72,6 +79,7 @@
 	ErrNotWritablePrimary                          = Code(10107)   // NotWritablePrimary
 	ErrBsonObjectTooLarge                          = Code(10334)   // BsonObjectTooLarge
 	ErrDuplicateKey                                = Code(11000)   // DuplicateKey
+	ErrInterrupted                                 = Code(11601)   // Interrupted
 	ErrBackgroundOperationInProgressForNamespace   = Code(12587)   // BackgroundOperationInProgressForNamespace
 	ErrLocation13026                               = Code(13026)   // Location13026
 	ErrLocation13027                               = Code(13027)   // Location13027
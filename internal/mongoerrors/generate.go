@@ -45,6 +45,7 @@ type line struct {
 // extraMongoErrors contains MongoDB error codes FerretDB uses and error_mappings.csv does not include
 var extraMongoErrors = map[string]int{
 	"Unset":                         0,
+	"HostUnreachable":               6,
 	"UserNotFound":                  11,
 	"UnsupportedFormat":             12,
 	"Unauthorized":                  13,
@@ -52,12 +53,19 @@ type line struct {
 	"AuthenticationFailed":          18,
 	"MaxTimeMSExpired":              50,
 	"CommandNotFound":               59,
+	"WriteConcernFailed":            64,
+	"UnknownReplWriteConcern":       79,
+	"ShutdownInProgress":            91,
 	"OperationFailed":               96,
+	"UnsatisfiableWriteConcern":     100,
+	"FailedToSatisfyReadPreference": 133,
 	"ClientMetadataCannotBeMutated": 186,
 	"InvalidUUID":                   207,
 	"NotImplemented":                238,
+	"SnapshotTooOld":                239,
 	"MechanismUnavailable":          334,
 	"UnsupportedOpQueryCommand":     352,
+	"Interrupted":                   11601,
 	"Location16979":                 16979,
 	"Location40621":                 40621,
 	"Location50687":                 50687,
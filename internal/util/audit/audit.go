@@ -0,0 +1,179 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit provides recording of security-relevant events
+// such as authentication, user management, and DDL commands.
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/v2/internal/util/logging"
+	"github.com/FerretDB/FerretDB/v2/internal/util/must"
+)
+
+// Type represents audit event type.
+//
+// Values match MongoDB's `atype` field where possible.
+type Type string
+
+const (
+	// Authenticate is recorded on authentication success or failure.
+	Authenticate Type = "authenticate"
+
+	// CreateUser is recorded by `createUser` command.
+	CreateUser Type = "createUser"
+
+	// DropUser is recorded by `dropUser` command.
+	DropUser Type = "dropUser"
+
+	// DropAllUsersFromDatabase is recorded by `dropAllUsersFromDatabase` command.
+	DropAllUsersFromDatabase Type = "dropAllUsersFromDatabase"
+
+	// UpdateUser is recorded by `updateUser` command.
+	UpdateUser Type = "updateUser"
+
+	// CreateIndex is recorded by `createIndexes` command.
+	CreateIndex Type = "createIndex"
+
+	// DropCollection is recorded by `drop` command.
+	DropCollection Type = "dropCollection"
+
+	// DropDatabase is recorded by `dropDatabase` command.
+	DropDatabase Type = "dropDatabase"
+
+	// RenameCollection is recorded by `renameCollection` command.
+	RenameCollection Type = "renameCollection"
+)
+
+// AllTypes contains all event types.
+var AllTypes = []Type{
+	Authenticate,
+	CreateUser,
+	DropUser,
+	DropAllUsersFromDatabase,
+	UpdateUser,
+	CreateIndex,
+	DropCollection,
+	DropDatabase,
+	RenameCollection,
+}
+
+// Record represents a single audit event.
+//
+//nolint:vet // for readability
+type Record struct {
+	Time    time.Time `json:"ts"`
+	Type    Type      `json:"atype"`
+	Remote  string    `json:"remote"`         // peer address, empty for Unix domain sockets
+	User    string    `json:"user,omitempty"` // empty for unauthenticated connections
+	DB      string    `json:"db,omitempty"`
+	Command string    `json:"command"`
+	Target  string    `json:"target,omitempty"` // command's argument like collection or user name
+	Result  int32     `json:"result"`           // 0 on success, MongoDB error code otherwise
+	Error   string    `json:"error,omitempty"`
+}
+
+// Sink represents audit records destination.
+//
+// Implementations must be thread-safe.
+type Sink interface {
+	// Write writes a single record.
+	Write(r *Record) error
+
+	// Close closes the sink.
+	Close() error
+}
+
+// Auditor filters audit records and writes them to the sink.
+type Auditor struct {
+	sink  Sink
+	types map[Type]struct{}
+	l     *slog.Logger
+}
+
+// NewOpts represents [New] options.
+type NewOpts struct {
+	Sink Sink
+	L    *slog.Logger
+
+	// Types to record; all types are recorded if empty.
+	Types []Type
+}
+
+// New creates a new Auditor.
+// It takes over the passed sink.
+func New(opts *NewOpts) (*Auditor, error) {
+	must.NotBeZero(opts)
+
+	if opts.Sink == nil {
+		return nil, lazyerrors.New("sink is required")
+	}
+
+	types := opts.Types
+	if len(types) == 0 {
+		types = AllTypes
+	}
+
+	a := &Auditor{
+		sink:  opts.Sink,
+		types: make(map[Type]struct{}, len(types)),
+		l:     opts.L,
+	}
+
+	for _, t := range types {
+		if !slices.Contains(AllTypes, t) {
+			return nil, lazyerrors.Errorf("unknown audit event type %q", t)
+		}
+
+		a.types[t] = struct{}{}
+	}
+
+	return a, nil
+}
+
+// Enabled returns true if events of the given type should be recorded.
+func (a *Auditor) Enabled(t Type) bool {
+	_, ok := a.types[t]
+	return ok
+}
+
+// Record writes the given record to the sink if its type is enabled.
+// The Time field is set if it is zero.
+//
+// Sink errors are logged, but not returned, so they do not affect the client.
+func (a *Auditor) Record(ctx context.Context, r *Record) {
+	if !a.Enabled(r.Type) {
+		return
+	}
+
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+
+	if err := a.sink.Write(r); err != nil {
+		a.l.ErrorContext(ctx, "Failed to write audit record", slog.String("atype", string(r.Type)), logging.Error(err))
+	}
+}
+
+// Close closes the underlying sink.
+func (a *Auditor) Close() {
+	if err := a.sink.Close(); err != nil {
+		a.l.Error("Failed to close audit sink", logging.Error(err))
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/testutil"
+)
+
+func TestAuditor(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	t.Run("Filter", func(t *testing.T) {
+		t.Parallel()
+
+		var records []*Record
+
+		a, err := New(&NewOpts{
+			Sink:  FuncSink(func(r *Record) { records = append(records, r) }),
+			L:     testutil.Logger(t),
+			Types: []Type{Authenticate},
+		})
+		require.NoError(t, err)
+
+		assert.True(t, a.Enabled(Authenticate))
+		assert.False(t, a.Enabled(DropCollection))
+
+		a.Record(ctx, &Record{Type: DropCollection, Command: "drop"})
+		a.Record(ctx, &Record{Type: Authenticate, Command: "saslContinue", User: "user"})
+
+		require.Len(t, records, 1)
+		assert.Equal(t, "user", records[0].User)
+		assert.False(t, records[0].Time.IsZero())
+
+		a.Close()
+	})
+
+	t.Run("UnknownType", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New(&NewOpts{
+			Sink:  FuncSink(func(*Record) {}),
+			L:     testutil.Logger(t),
+			Types: []Type{"unknown"},
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+		s, err := NewFileSink(path)
+		require.NoError(t, err)
+
+		a, err := New(&NewOpts{
+			Sink: s,
+			L:    testutil.Logger(t),
+		})
+		require.NoError(t, err)
+
+		a.Record(ctx, &Record{Type: DropDatabase, Command: "dropDatabase", DB: "test"})
+		a.Record(ctx, &Record{Type: CreateUser, Command: "createUser", Result: 51003, Error: "User already exists"})
+		a.Close()
+
+		f, err := os.Open(path)
+		require.NoError(t, err)
+
+		defer f.Close()
+
+		var actual []Record
+
+		for s := bufio.NewScanner(f); s.Scan(); {
+			var r Record
+			require.NoError(t, json.Unmarshal(s.Bytes(), &r))
+			actual = append(actual, r)
+		}
+
+		require.Len(t, actual, 2)
+		assert.Equal(t, DropDatabase, actual[0].Type)
+		assert.Equal(t, "test", actual[0].DB)
+		assert.Equal(t, int32(51003), actual[1].Result)
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// FileSink writes records to a file in JSON Lines format.
+type FileSink struct {
+	m sync.Mutex
+	f *os.File
+	e *json.Encoder
+}
+
+// NewFileSink creates a new FileSink.
+// The file is created if it does not exist; records are appended to existing files.
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &FileSink{
+		f: f,
+		e: json.NewEncoder(f),
+	}, nil
+}
+
+// Write implements [Sink].
+func (s *FileSink) Write(r *Record) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if err := s.e.Encode(r); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// Close implements [Sink].
+func (s *FileSink) Close() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if err := s.f.Sync(); err != nil {
+		_ = s.f.Close()
+		return lazyerrors.Error(err)
+	}
+
+	if err := s.f.Close(); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// FuncSink calls the function for each record.
+// It is intended for embedders.
+type FuncSink func(r *Record)
+
+// Write implements [Sink].
+func (f FuncSink) Write(r *Record) error {
+	f(r)
+	return nil
+}
+
+// Close implements [Sink].
+func (f FuncSink) Close() error {
+	return nil
+}
+
+// check interfaces
+var (
+	_ Sink = (*FileSink)(nil)
+	_ Sink = FuncSink(nil)
+)
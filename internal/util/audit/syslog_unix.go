@@ -0,0 +1,70 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package audit
+
+import (
+	"encoding/json"
+	"log/syslog"
+
+	"github.com/FerretDB/FerretDB/v2/internal/util/lazyerrors"
+)
+
+// syslogSink writes records to the local syslog daemon as JSON.
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+// NewSyslogSink creates a new syslog sink with the given tag.
+func NewSyslogSink(tag string) (Sink, error) {
+	w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_AUTH, tag)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &syslogSink{
+		w: w,
+	}, nil
+}
+
+// Write implements [Sink].
+func (s *syslogSink) Write(r *Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	// syslog.Writer is already thread-safe
+	if err = s.w.Info(string(b)); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// Close implements [Sink].
+func (s *syslogSink) Close() error {
+	if err := s.w.Close(); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// check interfaces
+var (
+	_ Sink = (*syslogSink)(nil)
+)
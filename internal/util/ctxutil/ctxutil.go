@@ -27,10 +27,13 @@
 // errDelayed is returned by [context.Cause] when [WithDelay]'s context is canceled after delay.
 var errDelayed = errors.New("context canceled after delay")
 
+// DefaultDelay is the delay typically used with [WithDelay].
+const DefaultDelay = 3 * time.Second
+
 // WithDelay returns a copy of the parent context (with its values), which is canceled
 // when returned [context.CancelCauseFunc] is called (without any delay),
-// or when the parent is canceled and 3 seconds have passed.
-func WithDelay(parent context.Context) (context.Context, context.CancelCauseFunc) {
+// or when the parent is canceled and the given delay has passed.
+func WithDelay(parent context.Context, delay time.Duration) (context.Context, context.CancelCauseFunc) {
 	ctx, cancel := context.WithCancelCause(context.WithoutCancel(parent))
 
 	go func() {
@@ -39,7 +42,7 @@ func WithDelay(parent context.Context) (context.Context, context.CancelCauseFunc
 			cancel(nil)
 
 		case <-parent.Done():
-			t := time.NewTimer(3 * time.Second)
+			t := time.NewTimer(delay)
 			defer t.Stop()
 
 			select {
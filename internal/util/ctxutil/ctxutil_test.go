@@ -15,6 +15,7 @@
 package ctxutil
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"os"
@@ -28,6 +29,38 @@
 	"github.com/stretchr/testify/require"
 )
 
+func TestWithDelay(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Delay", func(t *testing.T) {
+		t.Parallel()
+
+		parent, parentCancel := context.WithCancel(context.Background())
+
+		ctx, cancel := WithDelay(parent, 50*time.Millisecond)
+		defer cancel(nil)
+
+		parentCancel()
+
+		start := time.Now()
+		<-ctx.Done()
+
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+		assert.ErrorIs(t, context.Cause(ctx), errDelayed)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := WithDelay(context.Background(), time.Hour)
+
+		cancel(nil)
+		<-ctx.Done()
+
+		assert.ErrorIs(t, context.Cause(ctx), context.Canceled)
+	})
+}
+
 func TestDurationWithJitter(t *testing.T) {
 	t.Parallel()
 
@@ -68,6 +68,12 @@ type Handler struct {
 	stdL     *log.Logger
 }
 
+// Page represents an additional debug page.
+type Page struct {
+	Handler     http.Handler
+	Description string
+}
+
 // ListenOpts represents [Listen] options.
 //
 //nolint:vet // for readability
@@ -77,6 +83,9 @@ type ListenOpts struct {
 	R       prometheus.Registerer
 	Livez   Probe
 	Readyz  Probe
+
+	// Additional pages by path like `/debug/name`.
+	Pages map[string]Page
 }
 
 // Listen creates a new debug handler and starts listener on the given TCP address.
@@ -182,6 +191,15 @@ func Listen(opts *ListenOpts) (*Handler, error) {
 		"/debug/events":   "/x/net/trace events",
 	}
 
+	for path, p := range opts.Pages {
+		if _, ok := handlers[path]; ok {
+			panic(fmt.Sprintf("debug page %q is already registered", path))
+		}
+
+		http.Handle(path, p.Handler)
+		handlers[path] = p.Description
+	}
+
 	var page bytes.Buffer
 	must.NoError(template.Must(template.New("debug").Parse(`
 	<html>
@@ -249,7 +267,7 @@ func (h *Handler) Serve(ctx context.Context) {
 	<-ctx.Done()
 
 	// ctx is already canceled, but we want to inherit its values
-	shutdownCtx, shutdownCancel := ctxutil.WithDelay(ctx)
+	shutdownCtx, shutdownCancel := ctxutil.WithDelay(ctx, ctxutil.DefaultDelay)
 	defer shutdownCancel(nil)
 
 	if err := s.Shutdown(shutdownCtx); err != nil {
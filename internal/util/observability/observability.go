@@ -116,7 +116,7 @@ func (ot *OTelTraceExporter) Run(ctx context.Context) {
 	<-ctx.Done()
 
 	// ctx is already canceled, but we want to inherit its values
-	shutdownCtx, shutdownCancel := ctxutil.WithDelay(ctx)
+	shutdownCtx, shutdownCancel := ctxutil.WithDelay(ctx, ctxutil.DefaultDelay)
 	defer shutdownCancel(nil)
 
 	if err := ot.tp.ForceFlush(shutdownCtx); err != nil {